@@ -0,0 +1,132 @@
+/*
+Package credentials retrieves Splunk API credentials from external secret backends,
+so that they do not have to be provided as literal values or environment variables.
+*/
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sourceCacheTTL is how long credentials retrieved from a secret backend are cached, so that rotated secrets are picked up.
+const sourceCacheTTL = 5 * time.Minute
+
+// Credentials are Splunk API credentials: either a bearer token, or a user and a password.
+type Credentials struct {
+	Token    string `json:"token"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+func (c Credentials) validate() error {
+	if c.Token == "" && (c.User == "" || c.Password == "") {
+		return errors.New("no token or user/password found")
+	}
+	return nil
+}
+
+// Source retrieves credentials from a secret backend.
+// Sources are safe for concurrent use and cache the credentials as long as they are valid.
+type Source interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// Invalidator is implemented by sources caching their credentials. Invalidate discards the cached credentials
+// if they are the rejected ones, so that they are retrieved again by the next call to Credentials.
+type Invalidator interface {
+	Invalidate(rejected Credentials)
+}
+
+// Config selects a credential source. Exactly one source must be set.
+type Config struct {
+	// Path of a file containing a bearer token, or credentials as JSON. The file is read again whenever it changes.
+	File string `mapstructure:"file"`
+
+	// Credential helper command and arguments, printing credentials as JSON to stdout.
+	Exec []string `mapstructure:"exec"`
+
+	SSM   *SSMConfig   `mapstructure:"ssm"`
+	Vault *VaultConfig `mapstructure:"vault"`
+}
+
+// IsSet returns whether a credential source is configured.
+func (c Config) IsSet() bool {
+	return c.File != "" || len(c.Exec) > 0 || c.SSM != nil || c.Vault != nil
+}
+
+// Source returns the configured credential source, or nil if none is configured.
+func (c Config) Source() (Source, error) {
+	sources := []Source{}
+	if c.File != "" {
+		sources = append(sources, NewFileSource(c.File))
+	}
+	if len(c.Exec) > 0 {
+		sources = append(sources, NewExecSource(c.Exec))
+	}
+	if c.SSM != nil {
+		if c.SSM.Parameter == "" {
+			return nil, errors.New("missing SSM parameter")
+		}
+		sources = append(sources, NewSSMSource(*c.SSM))
+	}
+	if c.Vault != nil {
+		if c.Vault.Path == "" {
+			return nil, errors.New("missing Vault secret path")
+		}
+		sources = append(sources, NewVaultSource(*c.Vault))
+	}
+
+	switch len(sources) {
+	case 0:
+		return nil, nil
+	case 1:
+		return sources[0], nil
+	default:
+		return nil, fmt.Errorf("only one credential source can be configured, got %d", len(sources))
+	}
+}
+
+// cachedSource caches the credentials retrieved successfully from a backend for a while, or until they are rejected.
+type cachedSource struct {
+	ttl      time.Duration
+	retrieve func(ctx context.Context) (Credentials, error)
+
+	mu          sync.Mutex
+	credentials *Credentials
+	expiration  time.Time
+}
+
+func newCachedSource(retrieve func(ctx context.Context) (Credentials, error)) *cachedSource {
+	return &cachedSource{ttl: sourceCacheTTL, retrieve: retrieve}
+}
+
+func (s *cachedSource) Credentials(ctx context.Context) (Credentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.credentials != nil && time.Now().Before(s.expiration) {
+		return *s.credentials, nil
+	}
+
+	c, err := s.retrieve(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if err := c.validate(); err != nil {
+		return Credentials{}, err
+	}
+	s.credentials, s.expiration = &c, time.Now().Add(s.ttl)
+	return c, nil
+}
+
+func (s *cachedSource) Invalidate(rejected Credentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.credentials != nil && *s.credentials == rejected {
+		s.credentials = nil
+	}
+}
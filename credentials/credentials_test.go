@@ -0,0 +1,66 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestConfigSource(t *testing.T) {
+	if s, err := (Config{}).Source(); s != nil || err != nil {
+		t.Errorf("expected no source, got %v, %v", s, err)
+	}
+	if s, err := (Config{File: "token"}).Source(); err != nil {
+		t.Error(err)
+	} else if _, ok := s.(*FileSource); !ok {
+		t.Errorf("expected a file source, got %T", s)
+	}
+	if _, err := (Config{File: "token", Exec: []string{"helper"}}).Source(); err == nil {
+		t.Error("expected an error for multiple sources")
+	}
+	if _, err := (Config{SSM: &SSMConfig{}}).Source(); err == nil {
+		t.Error("expected an error for a missing SSM parameter")
+	}
+}
+
+func TestCachedSource(t *testing.T) {
+	ctx := context.Background()
+
+	retrievals := 0
+	s := newCachedSource(func(context.Context) (Credentials, error) {
+		retrievals++
+		return Credentials{Token: fmt.Sprintf("secret%d", retrievals)}, nil
+	})
+
+	credentials := func() Credentials {
+		c, err := s.Credentials(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	if c := credentials(); c.Token != "secret1" || credentials() != c || retrievals != 1 {
+		t.Errorf("expected the credentials to be cached, got %d retrievals", retrievals)
+	}
+
+	// credentials other than the cached ones, e.g. rejected before a rotation, do not invalidate the cache
+	s.Invalidate(Credentials{Token: "secret0"})
+	if c := credentials(); c.Token != "secret1" {
+		t.Errorf("expected the cached credentials, got %#v", c)
+	}
+
+	s.Invalidate(Credentials{Token: "secret1"})
+	if c := credentials(); c.Token != "secret2" {
+		t.Errorf("expected rejected credentials to be retrieved again, got %#v", c)
+	}
+
+	s.expiration = time.Now()
+	if c := credentials(); c.Token != "secret3" {
+		t.Errorf("expected expired credentials to be retrieved again, got %#v", c)
+	}
+	if s.ttl != sourceCacheTTL || time.Until(s.expiration) <= sourceCacheTTL-time.Minute {
+		t.Errorf("expected the credentials to be cached for %s, until %s", sourceCacheTTL, s.expiration)
+	}
+}
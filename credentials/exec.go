@@ -0,0 +1,77 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecSource runs a credential helper command, which prints credentials as JSON to stdout:
+//
+//	{"token": "...", "expiration": "2025-01-02T15:04:05Z"}
+//
+// The credentials are cached until their optional expiration, or until they are rejected.
+type ExecSource struct {
+	command []string
+
+	mu          sync.Mutex
+	credentials *Credentials
+	expiration  time.Time
+}
+
+// expirationMargin renews the credentials of an exec source ahead of their expiration.
+const expirationMargin = time.Minute
+
+func NewExecSource(command []string) *ExecSource {
+	return &ExecSource{command: command}
+}
+
+func (s *ExecSource) Credentials(ctx context.Context) (Credentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.credentials != nil && (s.expiration.IsZero() || time.Now().Add(expirationMargin).Before(s.expiration)) {
+		return *s.credentials, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.command[0], s.command[1:]...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return Credentials{}, fmt.Errorf("credential helper %s failed: %w: %s", s.command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	var output struct {
+		Credentials
+		Expiration *time.Time `json:"expiration"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return Credentials{}, fmt.Errorf("credential helper %s: invalid output: %w", s.command[0], err)
+	}
+	if err := output.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("credential helper %s: %w", s.command[0], err)
+	}
+
+	s.expiration = time.Time{}
+	if output.Expiration != nil {
+		if !time.Now().Before(*output.Expiration) {
+			return Credentials{}, fmt.Errorf("credential helper %s: credentials expired at %s", s.command[0], output.Expiration)
+		}
+		s.expiration = *output.Expiration
+	}
+	s.credentials = &output.Credentials
+	return output.Credentials, nil
+}
+
+func (s *ExecSource) Invalidate(rejected Credentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.credentials != nil && *s.credentials == rejected {
+		s.credentials = nil
+	}
+}
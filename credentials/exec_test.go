@@ -0,0 +1,84 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// credentialHelper returns a credential helper command printing output, and counting its runs in a file.
+func credentialHelper(t *testing.T, output string) (command []string, runs func() int) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	command = []string{"sh", "-c", fmt.Sprintf("echo >> %s; echo '%s'", counter, output)}
+	runs = func() int {
+		by, _ := os.ReadFile(counter)
+		return strings.Count(string(by), "\n")
+	}
+	return
+}
+
+func TestExecSource(t *testing.T) {
+	ctx := context.Background()
+
+	command, runs := credentialHelper(t, `{"token": "secret"}`)
+	s := NewExecSource(command)
+	for range 2 {
+		c, err := s.Credentials(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != (Credentials{Token: "secret"}) {
+			t.Errorf("expected secret token, got %#v", c)
+		}
+	}
+	if runs() != 1 {
+		t.Errorf("expected credentials without expiration to be cached, got %d runs", runs())
+	}
+	s.Invalidate(Credentials{Token: "secret"})
+	if _, err := s.Credentials(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if runs() != 2 {
+		t.Errorf("expected rejected credentials to be retrieved again, got %d runs", runs())
+	}
+}
+
+func TestExecSourceExpiration(t *testing.T) {
+	ctx := context.Background()
+
+	// credentials expiring within the expiration margin are renewed on every use
+	expiration := time.Now().Add(expirationMargin / 2).UTC().Format(time.RFC3339)
+	command, runs := credentialHelper(t, fmt.Sprintf(`{"token": "secret", "expiration": "%s"}`, expiration))
+	s := NewExecSource(command)
+	for range 2 {
+		if _, err := s.Credentials(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if runs() != 2 {
+		t.Errorf("expected expiring credentials to be renewed, got %d runs", runs())
+	}
+
+	command, _ = credentialHelper(t, `{"token": "secret", "expiration": "2000-01-01T00:00:00Z"}`)
+	if _, err := NewExecSource(command).Credentials(ctx); err == nil {
+		t.Error("expected an error for expired credentials")
+	}
+}
+
+func TestExecSourceErrors(t *testing.T) {
+	ctx := context.Background()
+
+	for _, command := range [][]string{
+		{"sh", "-c", "echo failed >&2; exit 1"},
+		{"sh", "-c", "echo not json"},
+		{"sh", "-c", `echo '{"user": "admin"}'`},
+	} {
+		if _, err := NewExecSource(command).Credentials(ctx); err == nil {
+			t.Errorf("%v: expected an error", command)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileSource reads credentials from a file: either a bearer token, or credentials as JSON.
+// The file is read again whenever its modification time or size changes, so that rotated tokens are picked up.
+type FileSource struct {
+	path string
+
+	mu          sync.Mutex
+	modTime     time.Time
+	size        int64
+	credentials Credentials
+}
+
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+func (s *FileSource) Credentials(_ context.Context) (Credentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.credentials, nil
+	}
+
+	by, err := os.ReadFile(s.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var c Credentials
+	if by = bytes.TrimSpace(by); bytes.HasPrefix(by, []byte("{")) {
+		if err := json.Unmarshal(by, &c); err != nil {
+			return Credentials{}, fmt.Errorf("failed to parse %s: %w", s.path, err)
+		}
+	} else {
+		c.Token = string(by)
+	}
+	if err := c.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	s.modTime, s.size, s.credentials = info.ModTime(), info.Size(), c
+	return c, nil
+}
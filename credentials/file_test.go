@@ -0,0 +1,54 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSource(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("token-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewFileSource(path)
+	c, err := s.Credentials(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != (Credentials{Token: "token-1"}) {
+		t.Errorf("expected token-1, got %#v", c)
+	}
+
+	// rotate the credentials
+	if err := os.WriteFile(path, []byte(`{"user": "admin", "password": "changeme"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = s.Credentials(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != (Credentials{User: "admin", Password: "changeme"}) {
+		t.Errorf("expected admin/changeme, got %#v", c)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"user": "admin"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Credentials(ctx); err == nil {
+		t.Error("expected an error for incomplete credentials")
+	}
+
+	if _, err := NewFileSource(filepath.Join(t.TempDir(), "missing")).Credentials(ctx); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
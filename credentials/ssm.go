@@ -0,0 +1,65 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// SSMConfig locates a bearer token stored in an AWS SSM parameter.
+type SSMConfig struct {
+	Parameter string `mapstructure:"parameter"`
+	// AWS shared config profile and region. By default, the AWS SDK defaults apply.
+	Profile string `mapstructure:"profile"`
+	Region  string `mapstructure:"region"`
+}
+
+// NewSSMSource returns a source retrieving a bearer token from an AWS SSM (secure string) parameter.
+func NewSSMSource(c SSMConfig) Source {
+	return newCachedSource(func(ctx context.Context) (Credentials, error) {
+		token, err := TokenFromSSM(ctx, c.Parameter, c.Profile, c.Region)
+		return Credentials{Token: token}, err
+	})
+}
+
+// TokenFromSSM retrieves a bearer token from an AWS SSM (secure string) parameter.
+func TokenFromSSM(ctx context.Context, tokenPath, profile, region string) (accessToken string, err error) {
+	opts := []func(*config.LoadOptions) error{}
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	if region != "" {
+		cfg.Region = region
+	}
+	client := ssm.NewFromConfig(cfg)
+	decryption := true
+
+	param, err := client.GetParameters(
+		ctx,
+		&ssm.GetParametersInput{
+			Names:          []string{tokenPath},
+			WithDecryption: &decryption,
+		})
+	if err != nil {
+		return "", err
+	}
+
+	secretsInfo := map[string]string{}
+	for _, item := range param.Parameters {
+		secretsInfo[*item.Name] = *item.Value
+	}
+
+	token, ok := secretsInfo[tokenPath]
+	if !ok {
+		return "", fmt.Errorf("client token not found from SSM")
+	}
+
+	return token, nil
+}
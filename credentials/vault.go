@@ -0,0 +1,115 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const vaultRequestTimeout = 30 * time.Second
+
+// VaultConfig locates credentials stored in a HashiCorp Vault KV secret,
+// under the token, user and password keys.
+type VaultConfig struct {
+	// Vault address. Defaults to the VAULT_ADDR environment variable.
+	Address string `mapstructure:"address"`
+	// Vault token. Defaults to the VAULT_TOKEN environment variable, or the ~/.vault-token file.
+	Token string `mapstructure:"token"`
+	// API path of the secret, e.g. secret/data/splunk/itsi for a KV v2 secret, or kv/splunk/itsi for a KV v1 secret.
+	Path string `mapstructure:"path"`
+}
+
+// NewVaultSource returns a source retrieving credentials from a HashiCorp Vault KV (v1 or v2) secret.
+func NewVaultSource(c VaultConfig) Source {
+	return newCachedSource(c.credentials)
+}
+
+func (c VaultConfig) token() (string, error) {
+	if c.Token != "" {
+		return c.Token, nil
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	by, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return "", fmt.Errorf("no Vault token found: %w", err)
+	}
+	return strings.TrimSpace(string(by)), nil
+}
+
+func (c VaultConfig) credentials(ctx context.Context) (Credentials, error) {
+	address := c.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return Credentials{}, fmt.Errorf("missing Vault address")
+	}
+	token, err := c.token()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, vaultRequestTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(address, "/"), strings.TrimPrefix(c.Path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read Vault secret %s: %w", c.Path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read Vault secret %s: %w", c.Path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("failed to read Vault secret %s: %s \n%s", c.Path, resp.Status, body)
+	}
+
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse Vault secret %s: %w", c.Path, err)
+	}
+
+	// KV v2 secrets nest the secret data along with its metadata
+	data := secret.Data
+	if _, ok := data["metadata"]; ok {
+		if nested, ok := data["data"]; ok {
+			data = nil
+			if err := json.Unmarshal(nested, &data); err != nil {
+				return Credentials{}, fmt.Errorf("failed to parse Vault secret %s: %w", c.Path, err)
+			}
+		}
+	}
+
+	var creds Credentials
+	by, _ := json.Marshal(data)
+	if err := json.Unmarshal(by, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse Vault secret %s: %w", c.Path, err)
+	}
+	if err := creds.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("Vault secret %s: %w", c.Path, err)
+	}
+	return creds, nil
+}
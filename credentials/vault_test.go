@@ -0,0 +1,54 @@
+package credentials
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVaultSource(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/splunk":
+			w.Write([]byte(`{"data": {"data": {"user": "admin", "password": "changeme"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/splunk":
+			w.Write([]byte(`{"data": {"token": "secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		config   VaultConfig
+		expected Credentials
+	}{
+		{VaultConfig{Address: server.URL, Token: "vault-token", Path: "secret/data/splunk"}, Credentials{User: "admin", Password: "changeme"}},
+		{VaultConfig{Address: server.URL + "/", Token: "vault-token", Path: "/kv/splunk"}, Credentials{Token: "secret"}},
+	}
+	for _, test := range tests {
+		c, err := NewVaultSource(test.config).Credentials(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != test.expected {
+			t.Errorf("%s: expected %#v, got %#v", test.config.Path, test.expected, c)
+		}
+	}
+
+	for _, config := range []VaultConfig{
+		{Address: server.URL, Token: "invalid", Path: "kv/splunk"},
+		{Address: server.URL, Token: "vault-token", Path: "kv/missing"},
+	} {
+		if _, err := NewVaultSource(config).Credentials(ctx); err == nil {
+			t.Errorf("%s: expected an error", config.Path)
+		}
+	}
+}
@@ -1,3 +1,7 @@
 data "itsi_entity_type" "host" {
   title = "Host"
 }
+
+output "host_vital_metrics" {
+  value = [for m in data.itsi_entity_type.host.vital_metric : m.metric_name]
+}
@@ -0,0 +1,7 @@
+data "itsi_entity_types" "kubernetes" {
+  title = "Kubernetes *"
+}
+
+output "kubernetes_entity_type_ids" {
+  value = { for et in data.itsi_entity_types.kubernetes.entity_types : et.title => et.id }
+}
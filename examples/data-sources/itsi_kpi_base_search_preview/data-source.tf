@@ -0,0 +1,24 @@
+data "itsi_kpi_base_search_preview" "existing" {
+  base_search_id = itsi_kpi_base_search.web.id
+  earliest_time  = "-1h"
+}
+
+data "itsi_kpi_base_search_preview" "inline" {
+  base_search                = "index=web sourcetype=access_combined"
+  entity_breakdown_id_fields = "host"
+  max_events                 = 5000
+
+  metric {
+    title            = "Response time"
+    threshold_field  = "response_time"
+    entity_statop    = "avg"
+    aggregate_statop = "perc95"
+  }
+}
+
+output "web_preview" {
+  value = {
+    entities = data.itsi_kpi_base_search_preview.inline.entity_count
+    metrics  = { for m in data.itsi_kpi_base_search_preview.inline.results : m.title => m.value }
+  }
+}
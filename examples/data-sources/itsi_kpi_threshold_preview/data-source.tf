@@ -0,0 +1,11 @@
+data "itsi_kpi_threshold_preview" "latency" {
+  threshold_template_id = itsi_kpi_threshold_template.business_hours.id
+  service_id            = itsi_service.web.id
+  kpi_id                = one([for kpi in itsi_service.web.kpi : kpi.id if kpi.title == "Latency"])
+  earliest_time         = "-14d"
+  time_zone             = "America/Los_Angeles"
+}
+
+output "latency_critical_minutes" {
+  value = { for r in data.itsi_kpi_threshold_preview.latency.results : r.policy_name => lookup(r.severity_counts, "critical", 0) }
+}
@@ -0,0 +1,3 @@
+output "host_count_kpi_id" {
+  value = provider::itsi::kpi_id(itsi_kpi_base_search.sample.id, "host_count")
+}
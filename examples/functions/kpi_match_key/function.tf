@@ -0,0 +1,3 @@
+output "host_count_kpi_match_key" {
+  value = provider::itsi::kpi_match_key(itsi_kpi_base_search.sample.id, "host_count")
+}
@@ -0,0 +1,43 @@
+locals {
+  web_entities = provider::itsi::parse_rule_expr("host matches web-*,api-* AND info:env not prod")
+  tier1_events = provider::itsi::parse_rule_expr("alert_group = * AND tier = 1 OR severity >= 5")
+}
+
+resource "itsi_entity_management_policy" "web" {
+  # ...
+
+  dynamic "entity_rules" {
+    for_each = local.web_entities.entity_rules
+    content {
+      dynamic "rule" {
+        for_each = entity_rules.value.rule
+        content {
+          field      = rule.value.field
+          field_type = rule.value.field_type
+          rule_type  = rule.value.rule_type
+          value      = rule.value.value
+        }
+      }
+    }
+  }
+}
+
+resource "itsi_notable_event_aggregation_policy" "tier1" {
+  # ...
+
+  filter_criteria {
+    dynamic "clause" {
+      for_each = local.tier1_events.clauses
+      content {
+        dynamic "notable_event_field" {
+          for_each = clause.value.notable_event_field
+          content {
+            field    = notable_event_field.value.field
+            operator = notable_event_field.value.operator
+            value    = notable_event_field.value.value
+          }
+        }
+      }
+    }
+  }
+}
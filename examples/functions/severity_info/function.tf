@@ -0,0 +1,3 @@
+output "critical_color" {
+  value = provider::itsi::severity_info("critical").color
+}
@@ -0,0 +1,3 @@
+output "resolved_status" {
+  value = provider::itsi::status_value("resolved")
+}
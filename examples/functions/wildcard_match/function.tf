@@ -0,0 +1,7 @@
+locals {
+  services = ["Sample Service A", "Sample Service B", "Other Service"]
+}
+
+output "sample_services" {
+  value = [for s in local.services : s if provider::itsi::wildcard_match("sample*", s)]
+}
@@ -0,0 +1 @@
+terraform import itsi_entities.example {{scope}}
\ No newline at end of file
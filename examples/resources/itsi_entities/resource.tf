@@ -0,0 +1,29 @@
+resource "itsi_entities" "hosts" {
+  scope = "example_hosts"
+
+  entity {
+    title       = "a.example.com"
+    description = "a.example.com host"
+
+    aliases = {
+      "host" = ["a.example.com"]
+    }
+
+    info = {
+      "env" : ["test"]
+    }
+  }
+
+  entity {
+    title       = "b.example.com"
+    description = "b.example.com host"
+
+    aliases = {
+      "host" = ["b.example.com"]
+    }
+
+    info = {
+      "env" : ["prod"]
+    }
+  }
+}
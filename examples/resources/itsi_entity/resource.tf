@@ -4,13 +4,15 @@ resource "itsi_entity" "host_entity" {
   description = "example.com host"
 
   aliases = {
-    "entityTitle" = "example.com"
-    "host"        = "example.com"
+    "entityTitle" = ["example.com"]
+    "host"        = ["example.com"]
+    "ip"          = ["10.0.0.1", "10.0.0.2"]
   }
 
   info = {
-    "env" : "test"
-    "entityType" : "host"
+    "env" : ["test"]
+    "entityType" : ["host"]
+    "location" : ["Alviso, CA"]
   }
 
 }
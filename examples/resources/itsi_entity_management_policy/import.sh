@@ -0,0 +1 @@
+terraform import itsi_entity_management_policy.example {{id}}
\ No newline at end of file
@@ -0,0 +1,27 @@
+resource "itsi_entity_management_policy" "autoscaling_hosts" {
+  title             = "Autoscaling hosts cleanup"
+  description       = "Retire autoscaling hosts that stopped reporting data"
+  inactivity_period = "2d"
+  action            = "retire"
+  cron_schedule     = "0 * * * *"
+  enabled           = false
+
+  entity_rules {
+    rule {
+      field      = "host"
+      field_type = "alias"
+      rule_type  = "matches"
+      value      = "ip-10-*"
+    }
+    rule {
+      field      = "env"
+      field_type = "info"
+      rule_type  = "not"
+      value      = "prod"
+    }
+  }
+}
+
+output "autoscaling_hosts_matching_entities" {
+  value = itsi_entity_management_policy.autoscaling_hosts.matching_entity_count
+}
@@ -128,7 +128,7 @@ require (
 	golang.org/x/crypto v0.39.0 // indirect
 	golang.org/x/exp v0.0.0-20250531010427-b6e5de432a8b // indirect
 	golang.org/x/mod v0.25.0 // indirect
-	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/net v0.40.0
 	golang.org/x/sync v0.15.0 // indirect
 	golang.org/x/sys v0.43.0 // indirect
 	golang.org/x/text v0.26.0 // indirect
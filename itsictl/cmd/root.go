@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -83,9 +84,15 @@ func initConfig() {
 }
 
 func initClient() {
-	authentication := fmt.Sprintf("basic (%s)", cfg.User)
+	authentication := fmt.Sprintf("session (%s)", cfg.User)
 
 	switch {
+	case cfg.Credentials.IsSet():
+		authentication = "credential source"
+		if err := cfg.InitCredentialSource(); err != nil {
+			fmt.Println("Invalid credential source:", err)
+			os.Exit(1)
+		}
 	case cfg.AccessToken != "":
 		authentication = "bearer token"
 	case cfg.User != "":
@@ -109,16 +116,48 @@ func initClient() {
 	if cfg.Verbose {
 		fmt.Println("----------------------------------------------------------")
 		fmt.Println("CONFIG:")
-		fmt.Printf("\tHost: %s\n", cfg.Host)
+		if len(cfg.Hosts) > 0 {
+			fmt.Printf("\tHosts: %s\n", strings.Join(cfg.Hosts, ", "))
+		} else {
+			fmt.Printf("\tHost: %s\n", cfg.Host)
+		}
 		fmt.Printf("\tPort: %d\n", cfg.Port)
 		fmt.Printf("\tInsecure: %v\n", cfg.Insecure)
-		fmt.Printf("\tConcurrency: %d\n", cfg.Concurrency)
+		fmt.Printf("\tMutual TLS: %v\n", cfg.ClientCert != "")
+		fmt.Printf("\tProxy: %v\n", cfg.ProxyURL != "")
+		if minConcurrency, maxConcurrency, ok := cfg.AdaptiveConcurrency(); ok {
+			fmt.Printf("\tConcurrency: %d (adaptive API limit %d-%d)\n", cfg.Concurrency, minConcurrency, maxConcurrency)
+		} else {
+			fmt.Printf("\tConcurrency: %d\n", cfg.Concurrency)
+		}
 		fmt.Printf("\tAuthentication: %s\n", authentication)
 		fmt.Println("----------------------------------------------------------")
 	}
 
-	provider.InitSplunkSearchLimiter(cfg.Concurrency)
-	models.InitItsiApiLimiter(cfg.Concurrency)
+	if _, err := cfg.ClientConfig().TLSConfig(); err != nil {
+		fmt.Println("Invalid TLS configuration:", err)
+		os.Exit(1)
+	}
+	if _, err := cfg.ClientConfig().ProxyFunc(); err != nil {
+		fmt.Println("Invalid proxy configuration:", err)
+		os.Exit(1)
+	}
+	if _, err := cfg.ExtraHeaders(); err != nil {
+		fmt.Println("Invalid headers:", err)
+		os.Exit(1)
+	}
+
+	if minConcurrency, maxConcurrency, ok := cfg.AdaptiveConcurrency(); ok {
+		if minConcurrency > maxConcurrency {
+			fmt.Printf("Invalid concurrency: --min-concurrency (%d) cannot be greater than --max-concurrency (%d)\n", minConcurrency, maxConcurrency)
+			os.Exit(1)
+		}
+		provider.InitSplunkSearchAdaptiveLimiter(minConcurrency, maxConcurrency)
+		models.InitItsiApiAdaptiveLimiter(minConcurrency, maxConcurrency)
+	} else {
+		provider.InitSplunkSearchLimiter(cfg.Concurrency)
+		models.InitItsiApiLimiter(cfg.Concurrency)
+	}
 }
 
 func init() {
@@ -126,29 +165,49 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "Config file (default is $HOME/.itsictl.yaml)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
 	rootCmd.PersistentFlags().Int("concurrency", 10, "Number of concurrent operations")
+	rootCmd.PersistentFlags().Int("min-concurrency", 0, "Lower bound of the adaptive API concurrency limit (enables the adaptive limit)")
+	rootCmd.PersistentFlags().Int("max-concurrency", 0, "Upper bound of the adaptive API concurrency limit (default is --concurrency)")
 
 	// Connection Options
 	rootCmd.PersistentFlags().String("host", "localhost", "ITSI host")
+	rootCmd.PersistentFlags().StringSlice("hosts", []string{}, "Search head cluster members to fail over between, as host or host:port (overrides --host)")
 	rootCmd.PersistentFlags().Int("port", 8089, "ITSI port")
 	rootCmd.PersistentFlags().Bool("insecure", false, "Disable TLS certificate verification")
+	rootCmd.PersistentFlags().String("ca-cert", "", "CA bundle to verify the ITSI certificate (PEM file path or contents)")
+	rootCmd.PersistentFlags().String("client-cert", "", "Client certificate for mutual TLS (PEM file path or contents)")
+	rootCmd.PersistentFlags().String("client-key", "", "Client certificate key for mutual TLS (PEM file path or contents)")
+	rootCmd.PersistentFlags().String("proxy-url", "", "Proxy URL (default is $HTTPS_PROXY)")
+	rootCmd.PersistentFlags().String("no-proxy", "", "Hosts to access without the proxy (default is $NO_PROXY)")
+	rootCmd.PersistentFlags().StringArray("header", []string{}, "Extra HTTP header, as \"Name: value\" (can be used multiple times)")
 
 	// Authentication Options
 	rootCmd.PersistentFlags().String("access-token", "", "Access token for authentication")
 	rootCmd.PersistentFlags().String("user", "admin", "Username for authentication")
 	rootCmd.PersistentFlags().String("password", "", "Password for authentication")
+	rootCmd.PersistentFlags().String("token-file", "", "File containing the access token, read again whenever it changes")
 
 	// Bind flags to Viper
 
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("concurrency", rootCmd.PersistentFlags().Lookup("concurrency"))
+	viper.BindPFlag("min_concurrency", rootCmd.PersistentFlags().Lookup("min-concurrency"))
+	viper.BindPFlag("max_concurrency", rootCmd.PersistentFlags().Lookup("max-concurrency"))
 
 	viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("host"))
+	viper.BindPFlag("hosts", rootCmd.PersistentFlags().Lookup("hosts"))
 	viper.BindPFlag("port", rootCmd.PersistentFlags().Lookup("port"))
 	viper.BindPFlag("insecure", rootCmd.PersistentFlags().Lookup("insecure"))
+	viper.BindPFlag("ca_cert", rootCmd.PersistentFlags().Lookup("ca-cert"))
+	viper.BindPFlag("client_cert", rootCmd.PersistentFlags().Lookup("client-cert"))
+	viper.BindPFlag("client_key", rootCmd.PersistentFlags().Lookup("client-key"))
+	viper.BindPFlag("proxy_url", rootCmd.PersistentFlags().Lookup("proxy-url"))
+	viper.BindPFlag("no_proxy", rootCmd.PersistentFlags().Lookup("no-proxy"))
+	viper.BindPFlag("headers", rootCmd.PersistentFlags().Lookup("header"))
 
 	viper.BindPFlag("access_token", rootCmd.PersistentFlags().Lookup("access-token"))
 	viper.BindPFlag("user", rootCmd.PersistentFlags().Lookup("user"))
 	viper.BindPFlag("password", rootCmd.PersistentFlags().Lookup("password"))
+	viper.BindPFlag("credentials.file", rootCmd.PersistentFlags().Lookup("token-file"))
 }
 
 func Execute() {
@@ -14,6 +14,15 @@ var (
 	thresholdCmdDryRun                          bool
 	thresholdRecommendCmdUseLatestData          bool
 	thresholdRecommendCmdInsufficientDataAction string
+	thresholdRecommendCmdSettings               thld.RecommendationSettings
+	thresholdRecommendCmdOverridesFile          string
+	thresholdRecommendCmdReport                 string
+	thresholdRecommendCmdReportFormat           string
+	thresholdRecomputeCmdTemplate               string
+	thresholdApplyTemplateCmdTemplate           string
+	thresholdApplyTemplateCmdUnlink             bool
+	thresholdExportCmdFile                      string
+	thresholdImportCmdFile                      string
 )
 
 var thresholdCmd = &cobra.Command{
@@ -23,7 +32,7 @@ var thresholdCmd = &cobra.Command{
 	Long: util.Dedent(`
 The "threshold" command allows you to manage KPI thresholds in Splunk ITSI.
 
-It provides subcommands to reset thresholds or apply machine learning-assisted thresholds to your KPIs.
+It provides subcommands to reset thresholds, link KPIs to threshold templates, export and import thresholds, recompute adaptive thresholds or apply machine learning-assisted thresholds to your KPIs.
 `),
 }
 
@@ -80,6 +89,21 @@ var recommendCmd = &cobra.Command{
 	  * By default, the analysis starts from the 'start_date' specified at the KPI level. You can override this by using the '--use-latest-data' flag to analyze the most recent data instead.
 	  * If the ML analysis cannot recommend any thresholds due to insufficient historical data or constant KPI values during the analysis window, the KPI is skipped by default, retaining its current configuration.
 	    You can change this behavior using the '--insufficient-data-action' flag and opt in for resetting such KPI's thresholds instead.
+	  * The '--algorithm', '--sensitivity', '--min-training-days' and '--severity-level' flags tune how recommendations are turned into thresholds.
+	    Per-KPI settings can be read from a YAML file with the '--overrides' flag; they take precedence over the flags:
+
+	      overrides:
+	        - service: "sample service*"
+	          kpi: errors
+	          algorithm: quantile
+	          sensitivity: 3
+	          min_training_days: 14
+	          severity_levels: [high, critical]
+
+	    KPIs with different settings are analyzed by separate Splunk searches.
+	  * The '--report' flag writes a report of every analyzed KPI, with its current thresholds, the recommended policies, the recommendation flags,
+	    the training window and the action taken, so that the recommendations can be reviewed, e.g. along with '--dry-run'.
+	    The report format (json, csv or md) is derived from the file extension, unless set with the '--report-format' flag.
 	`),
 	Example: `
   - Analyze and update the thresholds for all KPIs configued for ML-assisted thresholds:
@@ -106,9 +130,21 @@ var recommendCmd = &cobra.Command{
 
     itsictl threshold recommend --service service1 --insufficient-data-action reset
 
+  - Apply ML-assisted thresholds using quantile adaptive thresholds, generating only high and critical severity levels:
+
+    itsictl threshold recommend --service service1 --algorithm quantile --severity-level high --severity-level critical
+
+  - Skip KPIs with less than 14 days of data, and apply per-KPI settings from a file:
+
+    itsictl threshold recommend --min-training-days 14 --overrides recommend-overrides.yaml
+
   - Perform a dry run to see what changes would be made without applying them:
 
     itsictl threshold recommend --service service1 --dry-run
+
+  - Perform a dry run and write a Markdown report comparing the current and recommended thresholds:
+
+    itsictl threshold recommend --service service1 --dry-run --report recommendations.md
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if !(thresholdRecommendCmdInsufficientDataAction == "skip" ||
@@ -116,6 +152,28 @@ var recommendCmd = &cobra.Command{
 			fmt.Println("Error: invalid value for '--insufficient-data-action'. Must be 'skip' or 'reset'.")
 			os.Exit(1)
 		}
+		if err := thresholdRecommendCmdSettings.Validate(); err != nil {
+			fmt.Printf("Error: %s\n", err.Error())
+			os.Exit(1)
+		}
+
+		var overrides []thld.RecommendationOverride
+		if thresholdRecommendCmdOverridesFile != "" {
+			var err error
+			if overrides, err = thld.LoadRecommendationOverrides(thresholdRecommendCmdOverridesFile); err != nil {
+				fmt.Printf("Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
+
+		reportFormat := ""
+		if thresholdRecommendCmdReport != "" {
+			var err error
+			if reportFormat, err = thld.RecommendationReportFormat(thresholdRecommendCmdReport, thresholdRecommendCmdReportFormat); err != nil {
+				fmt.Printf("Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
 
 		initClient()
 
@@ -126,6 +184,106 @@ var recommendCmd = &cobra.Command{
 			thresholdCmdDryRun,
 			thresholdRecommendCmdUseLatestData,
 			thresholdRecommendCmdInsufficientDataAction,
+			thresholdRecommendCmdSettings,
+			overrides,
+		)
+		if thresholdRecommendCmdReport != "" {
+			w = w.WithReport(thresholdRecommendCmdReport, reportFormat)
+		}
+
+		err := w.Execute(context.Background())
+		if err != nil {
+			fmt.Printf("Workflow has completed with errors: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var recomputeCmd = &cobra.Command{
+	Use:   "recompute",
+	Short: "Recompute adaptive thresholds",
+	Long: util.Dedent(`
+	Recomputes the adaptive thresholds of matching KPIs immediately, instead of waiting for ITSI's nightly adaptive thresholds search.
+	  * Only KPIs with adaptive thresholds enabled are recomputed.
+	  * ITSI's adaptive thresholds search for the training window of every matching KPI (e.g. itsi_at_search_kpi_minus7d)
+	    is triggered, and the command waits for it to complete. ITSI recomputes and saves the thresholds itself,
+	    including every other KPI with adaptive thresholds enabled and the same training window.
+	  * Only the -7d, -14d, -30d and -60d training windows are supported: no search is triggered if a matching KPI has another one.
+	  * The '--template' flag limits the command to the KPIs linked to a threshold template.
+	  The recomputed threshold values of the matching KPIs are logged once the searches have completed.
+	`),
+	Example: `
+  - Recompute the adaptive thresholds of all KPIs linked to a threshold template:
+
+    itsictl threshold recompute --template "Sample time variate standard deviation threshold template"
+
+  - Recompute the adaptive thresholds of the "errors" KPI in service1:
+
+    itsictl threshold recompute --service service1 --kpi errors
+
+  - Perform a dry run to see the matching KPIs and their current thresholds, without triggering the searches:
+
+    itsictl threshold recompute --service service1 --dry-run
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		initClient()
+
+		w := thld.NewThresholdRecomputeWorkflow(cfg, services, kpis, thresholdRecomputeCmdTemplate, thresholdCmdDryRun)
+		err := w.Execute(context.Background())
+		if err != nil {
+			fmt.Printf("Workflow has completed with errors: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var applyTemplateCmd = &cobra.Command{
+	Use:   "apply-template",
+	Short: "Link selected KPIs to a threshold template",
+	Long: util.Dedent(`
+	Links the specified KPIs/services to a threshold template.
+
+	  Matching KPIs will have their kpi_threshold_template_id set, and the thresholding configuration of the template
+	  (adaptive thresholds, time variate thresholds and their policies) copied into them.
+	  With the '--unlink' flag, matching KPIs are unlinked from their threshold templates instead, retaining their current thresholds.
+
+	  To prevent accidental changes to all services, the "apply-template" command requires at least one service selector to be provided using the '--service' flag.`),
+	Example: `
+  - Link all KPIs in a specific service to a threshold template:
+
+    itsictl threshold apply-template --template "Sample static threshold template" --service service1
+
+  - Link all KPIs starting with "network" in the services that start with "sample service" to a threshold template, by ID:
+
+    itsictl threshold apply-template -t 5d8ad1dbec2a1e6b2c6d2dc3 -s "sample service*" -k "network*"
+
+  - Unlink the "errors" KPI in service1 from its threshold template:
+
+    itsictl threshold apply-template --unlink --service service1 --kpi errors
+
+  - Perform a dry run to see which KPIs would be linked without saving them:
+
+    itsictl threshold apply-template --template "Sample static threshold template" --service service1 --dry-run
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(services) == 0 {
+			fmt.Println("No services specified. You must provide one or more service selectors using the --service argument.")
+			os.Exit(1)
+		}
+		if (thresholdApplyTemplateCmdTemplate == "") == !thresholdApplyTemplateCmdUnlink {
+			fmt.Println("Error: exactly one of '--template' or '--unlink' must be specified.")
+			os.Exit(1)
+		}
+
+		initClient()
+
+		w := thld.NewThresholdApplyTemplateWorkflow(
+			cfg,
+			services,
+			kpis,
+			thresholdApplyTemplateCmdTemplate,
+			thresholdApplyTemplateCmdUnlink,
+			thresholdCmdDryRun,
 		)
 
 		err := w.Execute(context.Background())
@@ -136,19 +294,101 @@ var recommendCmd = &cobra.Command{
 	},
 }
 
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export thresholds of selected KPIs/services to a YAML file",
+	Long: util.Dedent(`
+	Exports the thresholding configuration of the specified KPIs/services to a YAML file, keyed by service title and KPI title.
+
+	  For every KPI, the file captures the adaptive thresholds settings, outlier exclusion settings and time variate threshold policies
+	  with their aggregate and entity thresholds. Unless time variate thresholds are enabled, the default policy holds the KPI's aggregate and entity thresholds.
+	  The field names are those of the itsi_kpi_threshold_template resource, so that the thresholds of a KPI can be turned into a threshold template.`),
+	Example: `
+  - Export the thresholds of all KPIs in a specific service:
+
+    itsictl threshold export --service service1 --file thresholds.yaml
+
+  - Export the thresholds of the "errors" KPI and all KPIs starting with "network" in the services that start with "sample service":
+
+    itsictl threshold export -s "sample service*" -k errors -k "network*" -f thresholds.yaml
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		initClient()
+
+		w := thld.NewThresholdExportWorkflow(cfg, services, kpis, thresholdExportCmdFile)
+		err := w.Execute(context.Background())
+		if err != nil {
+			fmt.Printf("Workflow has completed with errors: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import thresholds of KPIs from a YAML file",
+	Long: util.Dedent(`
+	Applies the thresholding configuration of a YAML file, as written by the "export" command, to the KPIs it lists.
+
+	  * KPIs are matched by service title and KPI title. The '--service' and '--kpi' flags further restrict the KPIs to update.
+	  * The changes to every KPI are logged as a diff of its thresholding configuration before the service is saved.
+	  * KPI level aggregate and entity thresholds are set to those of the default policy.
+	  * Updated KPIs are unlinked from their threshold templates, so that ITSI does not overwrite the imported thresholds.
+	  Use the '--dry-run' flag to preview the changes.`),
+	Example: `
+  - Preview the changes that importing a threshold file would make:
+
+    itsictl threshold import --file thresholds.yaml --dry-run
+
+  - Import the thresholds of the KPIs of service1 only:
+
+    itsictl threshold import --file thresholds.yaml --service service1
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		initClient()
+
+		w := thld.NewThresholdImportWorkflow(cfg, services, kpis, thresholdImportCmdFile, thresholdCmdDryRun)
+		err := w.Execute(context.Background())
+		if err != nil {
+			fmt.Printf("Workflow has completed with errors: %s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(thresholdCmd)
 
-	thresholdCommands := []*cobra.Command{resetCmd, recommendCmd}
+	thresholdCommands := []*cobra.Command{resetCmd, applyTemplateCmd, exportCmd, importCmd, recommendCmd, recomputeCmd}
 
 	for _, cmd := range thresholdCommands {
 		thresholdCmd.AddCommand(cmd)
 		cmd.Flags().StringArrayVarP(&services, "service", "s", []string{}, "Specify Service Selector (service ID, title, or a wildcard pattern; can be used multiple times)")
 		cmd.Flags().StringArrayVarP(&kpis, "kpi", "k", []string{}, "Specify KPI Selector (KPI ID, title, or a wildcard pattern; can be used multiple times)")
-		cmd.Flags().BoolVar(&thresholdCmdDryRun, "dry-run", false, "Run the command without actaully changing anything")
+		if cmd != exportCmd {
+			cmd.Flags().BoolVar(&thresholdCmdDryRun, "dry-run", false, "Run the command without actaully changing anything")
+		}
 	}
 
+	exportCmd.Flags().StringVarP(&thresholdExportCmdFile, "file", "f", "", "Path of the YAML file to export the thresholds to")
+	exportCmd.MarkFlagRequired("file")
+
+	importCmd.Flags().StringVarP(&thresholdImportCmdFile, "file", "f", "", "Path of the YAML file to import the thresholds from")
+	importCmd.MarkFlagRequired("file")
+
 	recommendCmd.Flags().BoolVar(&thresholdRecommendCmdUseLatestData, "use-latest-data", false, "Use the latest KPI data for analysis (ignore the stored starting date)")
 	recommendCmd.Flags().StringVar(&thresholdRecommendCmdInsufficientDataAction, "insufficient-data-action", "skip", "Action to take for KPIs with insufficient data: 'skip' or 'reset'")
+	recommendCmd.Flags().StringVar(&thresholdRecommendCmdSettings.Algorithm, "algorithm", "", "Adaptive thresholding algorithm of the recommended adaptive policies: 'stdev', 'quantile' or 'range' (default: as recommended)")
+	recommendCmd.Flags().Float64Var(&thresholdRecommendCmdSettings.Sensitivity, "sensitivity", 0, "Outlier exclusion sensitivity (default: as recommended)")
+	recommendCmd.Flags().IntVar(&thresholdRecommendCmdSettings.MinTrainingDays, "min-training-days", 0, "Minimum number of days of KPI data in the training window; KPIs with less data are treated as having insufficient data")
+	recommendCmd.Flags().StringArrayVar(&thresholdRecommendCmdSettings.SeverityLevels, "severity-level", []string{}, "Severity level to generate: 'info', 'low', 'medium', 'high' or 'critical' (can be used multiple times; default: all recommended levels)")
+	recommendCmd.Flags().StringVar(&thresholdRecommendCmdOverridesFile, "overrides", "", "Path of a YAML file with per-KPI recommendation settings")
+	recommendCmd.Flags().StringVar(&thresholdRecommendCmdReport, "report", "", "Path of a report file to write the recommendations of every analyzed KPI to")
+	recommendCmd.Flags().StringVar(&thresholdRecommendCmdReportFormat, "report-format", "", "Report format: 'json', 'csv' or 'md' (default: derived from the report file extension)")
+
+	applyTemplateCmd.Flags().StringVarP(&thresholdApplyTemplateCmdTemplate, "template", "t", "", "Threshold template to link the KPIs to (threshold template ID or title)")
+	applyTemplateCmd.Flags().BoolVar(&thresholdApplyTemplateCmdUnlink, "unlink", false, "Unlink the KPIs from their threshold templates instead, retaining their current thresholds")
+
+	recomputeCmd.Flags().StringVarP(&thresholdRecomputeCmdTemplate, "template", "t", "", "Only recompute KPIs linked to the threshold template (threshold template ID or title)")
 
 }
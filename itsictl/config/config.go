@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lestrrat-go/backoff/v2"
+	"github.com/tivo/terraform-provider-splunk-itsi/credentials"
 	"github.com/tivo/terraform-provider-splunk-itsi/models"
 )
 
@@ -12,26 +15,97 @@ type Config struct {
 	Verbose     bool
 	Concurrency int
 
+	// Bounds of the adaptive concurrency limit of API requests and searches; the limit is fixed to Concurrency if unset
+	MinConcurrency int `mapstructure:"min_concurrency"`
+	MaxConcurrency int `mapstructure:"max_concurrency"`
+
 	// Connection Options
 	Host     string
 	Port     int
 	Insecure bool
 
+	// Search head cluster members, as host or host:port, to fail over between instead of Host
+	Hosts []string `mapstructure:"hosts"`
+
+	// TLS Options: PEM encoded contents or file paths
+	CACert     string `mapstructure:"ca_cert"`
+	ClientCert string `mapstructure:"client_cert"`
+	ClientKey  string `mapstructure:"client_key"`
+
+	// Proxy Options
+	ProxyURL string `mapstructure:"proxy_url"`
+	NoProxy  string `mapstructure:"no_proxy"`
+
+	// Extra HTTP headers, as "Name: value"
+	Headers []string `mapstructure:"headers"`
+
 	// Authentication Options
 	AccessToken string
 	User        string
 	Password    string
+
+	// External credential source, taking precedence over the other authentication options
+	Credentials      credentials.Config `mapstructure:"credentials"`
+	credentialSource credentials.Source
+}
+
+// InitCredentialSource sets up the configured credential source, if any.
+func (c *Config) InitCredentialSource() (err error) {
+	c.credentialSource, err = c.Credentials.Source()
+	return
+}
+
+// AdaptiveConcurrency returns the bounds of the adaptive concurrency limit, and whether it is enabled.
+func (c Config) AdaptiveConcurrency() (minConcurrency, maxConcurrency int, ok bool) {
+	if c.MinConcurrency <= 0 && c.MaxConcurrency <= 0 {
+		return 0, c.Concurrency, false
+	}
+	minConcurrency, maxConcurrency = max(c.MinConcurrency, 1), c.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = c.Concurrency
+	}
+	return minConcurrency, maxConcurrency, true
+}
+
+// ExtraHeaders parses the extra HTTP headers.
+func (c Config) ExtraHeaders() (map[string]string, error) {
+	headers := map[string]string{}
+	for _, header := range c.Headers {
+		name, value, ok := strings.Cut(header, ":")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q: must be formatted as \"Name: value\"", header)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
 }
 
 func (c Config) ClientConfig() models.ClientConfig {
+	// invalid headers are reported when the client is initialized
+	headers, _ := c.ExtraHeaders()
+	_, concurrency, _ := c.AdaptiveConcurrency()
+
+	host := c.Host
+	if len(c.Hosts) > 0 {
+		host = models.PrimaryHost(c.Hosts)
+	}
+
 	return models.ClientConfig{
-		BearerToken: c.AccessToken,
-		Host:        c.Host,
-		Port:        c.Port,
-		User:        c.User,
-		Password:    c.Password,
-		SkipTLS:     c.Insecure,
-		Concurrency: c.Concurrency,
+		BearerToken:      c.AccessToken,
+		Host:             host,
+		Hosts:            c.Hosts,
+		Port:             c.Port,
+		User:             c.User,
+		Password:         c.Password,
+		SkipTLS:          c.Insecure,
+		CACert:           c.CACert,
+		ClientCert:       c.ClientCert,
+		ClientKey:        c.ClientKey,
+		ProxyURL:         c.ProxyURL,
+		NoProxy:          c.NoProxy,
+		ExtraHeaders:     headers,
+		CredentialSource: c.credentialSource,
+		Concurrency:      concurrency,
 		RetryPolicy: backoff.Exponential(
 			backoff.WithMinInterval(500*time.Millisecond),
 			backoff.WithMaxInterval(time.Minute),
@@ -43,20 +43,37 @@ func (p *analysisProcessor) splunkreq(batch kpisByTrainingConfig) *provider.Splu
 		{{ .BT }}metrics_service_level_kpi_only{{ .BT }} by itsi_kpi_id, itsi_service_id span=1m
 		| where alert_level!=-2
 		| table _time, alert_value, alert_level, itsi_kpi_id, itsi_service_id
-		| sort 0 itsi_kpi_id | recommendthresholdtemplate threshold_direction={{ .DIRECTION }}
+		{{- if .MIN_FIRST_TIME }}
+		| eventstats min(_time) AS first_time by itsi_kpi_id, itsi_service_id
+		| where first_time <= {{ .MIN_FIRST_TIME }}
+		| fields - first_time
+		{{- end }}
+		| sort 0 itsi_kpi_id | recommendthresholdtemplate {{ .ARGS }}
 	`)
 
 	searches := []provider.SplunkSearch{}
 
 	for trainingConf, kpis := range batch {
+		latestTime := trainingConf.startTime + trainingConf.size*86400
+
+		// KPIs must have data going back at least the minimum number of training days from the end of the training window
+		minFirstTime := ""
+		if trainingConf.minTrainingDays > 0 {
+			minFirstTime = fmt.Sprintf("%d", latestTime-trainingConf.minTrainingDays*86400)
+		}
+
 		var buf bytes.Buffer
-		if err := tpl.Execute(&buf, struct{ FILTER, DIRECTION, BT string }{FILTER: kpis.filterExpression(), DIRECTION: trainingConf.thresholdDirection, BT: "`"}); err != nil {
+		if err := tpl.Execute(&buf, struct{ FILTER, ARGS, MIN_FIRST_TIME, BT string }{
+			FILTER:         kpis.filterExpression(),
+			ARGS:           recommendationSearchArgs(trainingConf),
+			MIN_FIRST_TIME: minFirstTime,
+			BT:             "`",
+		}); err != nil {
 			p.workflow.Log.Fatal(
 				"unexpected error while rendering a Splunk search for running ML thresholding analysis",
 				"filter", kpis.filterExpression(),
-				"direction", trainingConf.thresholdDirection)
+				"args", recommendationSearchArgs(trainingConf))
 		}
-		latestTime := trainingConf.startTime + trainingConf.size*86400
 
 		searches = append(searches, provider.SplunkSearch{
 			Query:               buf.String(),
@@ -0,0 +1,163 @@
+package thld
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/itsictl/config"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+	"gopkg.in/yaml.v3"
+)
+
+type ThresholdApplyTemplateWorkflow struct {
+	thresholdWorkflow
+
+	/*
+		Threshold template selector: a threshold template ID or title.
+		Ignored when unlink is set.
+	*/
+	template string
+
+	/*
+		If set, matching KPIs are unlinked from their threshold templates instead.
+		Unlinked KPIs retain their current thresholds.
+	*/
+	unlink bool
+
+	templateKey   string
+	templateTitle string
+	templateMap   map[string]any
+}
+
+func NewThresholdApplyTemplateWorkflow(cfg config.Config, services []string, kpis []string, template string, unlink bool, dryrun bool) *ThresholdApplyTemplateWorkflow {
+	return &ThresholdApplyTemplateWorkflow{thresholdWorkflow: makeThresholdWorkflow(cfg, services, kpis, dryrun), template: template, unlink: unlink}
+}
+
+type serviceThresholdApplyTemplateProcessor struct {
+	services []*models.ItsiObj
+
+	w *ThresholdApplyTemplateWorkflow
+}
+
+func (p *serviceThresholdApplyTemplateProcessor) Items() []*models.ItsiObj {
+	return p.services
+}
+
+func (p *serviceThresholdApplyTemplateProcessor) Process(ctx context.Context, svc *models.ItsiObj) (err error) {
+
+	svcMap, err := svc.RawJson.ToInterfaceMap()
+	if err != nil {
+		return err
+	}
+
+	svcID, svcTitle := svc.RESTKey, svcMap["title"].(string)
+
+	kpis, err := provider.UnpackSlice[map[string]any](svcMap["kpis"])
+	if err != nil {
+		return err
+	}
+
+	kpisUpdated := []string{}
+
+	for _, kpi := range kpis {
+		kpiID, kpiTitle := kpi["_key"].(string), kpi["title"].(string)
+
+		if strings.HasPrefix(kpiID, "SHKPI") || !p.w.kpiMatch(kpiID, kpiTitle) {
+			continue
+		}
+
+		if p.w.unlink {
+			if templateID, _ := kpi["kpi_threshold_template_id"].(string); templateID == "" {
+				continue
+			}
+			kpi["kpi_threshold_template_id"] = ""
+		} else {
+			provider.ApplyKpiThresholdTemplate(kpi, p.w.templateKey, p.w.templateMap)
+		}
+		kpisUpdated = append(kpisUpdated, fmt.Sprintf("%s (%s)", kpiTitle, kpiID))
+	}
+
+	if len(kpisUpdated) > 0 {
+		svcMap["kpis"] = kpis
+		if err := svc.PopulateRawJSON(ctx, svcMap); err != nil {
+			return fmt.Errorf("failed to populate service api model: %w", err)
+		}
+
+		if !p.w.dryrun {
+			if diags := svc.UpdateAsync(ctx); diags.HasError() {
+				return fmt.Errorf("failed to save service: %#v", diags)
+			}
+		}
+
+		kpisUpdatedYaml, err := yaml.Marshal(kpisUpdated)
+		if err != nil {
+			return err
+		}
+
+		msg := ""
+		if !p.w.dryrun {
+			msg = fmt.Sprintf("Service [ %s ] has been saved. ", svcTitle)
+		}
+		if p.w.unlink {
+			msg += fmt.Sprintf("%d KPIs have been unlinked from their threshold templates.", len(kpisUpdated))
+		} else {
+			msg += fmt.Sprintf("Threshold template [ %s ] has been applied to %d KPIs.", p.w.templateTitle, len(kpisUpdated))
+		}
+
+		p.w.Log.Info(
+			msg,
+			"service_id", svcID,
+			"kpis_updated", string(kpisUpdatedYaml),
+		)
+	}
+
+	return
+}
+
+func (w *ThresholdApplyTemplateWorkflow) processBatch(ctx context.Context, services []*models.ItsiObj) error {
+	return util.ProcessInParallel(ctx, &serviceThresholdApplyTemplateProcessor{services, w}, w.Cfg.Concurrency)
+}
+
+func (w *ThresholdApplyTemplateWorkflow) Execute(ctx context.Context) error {
+
+	w.Log.Info(
+		"Starting threshold template apply workflow",
+		"service_selectors", w.displaySelectors(w.services),
+		"kpi_selectors", w.displaySelectors(w.kpis),
+		"threshold_template", w.template,
+		"unlink", w.unlink,
+		"concurrency", w.Cfg.Concurrency,
+		"dry_run", w.dryrun,
+	)
+
+	if !w.unlink {
+		template, err := w.thresholdTemplate(ctx, w.template)
+		if err != nil {
+			return err
+		}
+		if w.templateMap, err = template.RawJson.ToInterfaceMap(); err != nil {
+			return err
+		}
+		w.templateKey = template.RESTKey
+		w.templateTitle, _ = w.templateMap["title"].(string)
+	}
+
+	batch := []*models.ItsiObj{}
+	for svc, err := range w.Services(ctx) {
+		if err != nil {
+			return err
+		}
+
+		if batch = append(batch, svc); len(batch)%w.Cfg.Concurrency == 0 {
+			if err = w.processBatch(ctx, batch); err != nil {
+				return err
+			}
+			batch = []*models.ItsiObj{}
+		}
+	}
+
+	return w.processBatch(ctx, batch)
+}
@@ -0,0 +1,131 @@
+package thld
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/itsictl/config"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+	"gopkg.in/yaml.v3"
+)
+
+// thresholdingFile is the content of a threshold export file:
+// the thresholding configuration of KPIs, keyed by service title and KPI title.
+type thresholdingFile map[string]map[string]provider.KpiThresholding
+
+type ThresholdExportWorkflow struct {
+	thresholdWorkflow
+
+	// Path of the YAML file to export the thresholds to.
+	file string
+
+	mu           sync.Mutex
+	thresholding thresholdingFile
+}
+
+func NewThresholdExportWorkflow(cfg config.Config, services []string, kpis []string, file string) *ThresholdExportWorkflow {
+	return &ThresholdExportWorkflow{thresholdWorkflow: makeThresholdWorkflow(cfg, services, kpis, false), file: file, thresholding: thresholdingFile{}}
+}
+
+type serviceThresholdExportProcessor struct {
+	services []*models.ItsiObj
+
+	w *ThresholdExportWorkflow
+}
+
+func (p *serviceThresholdExportProcessor) Items() []*models.ItsiObj {
+	return p.services
+}
+
+func (p *serviceThresholdExportProcessor) Process(ctx context.Context, svc *models.ItsiObj) (err error) {
+
+	svcMap, err := svc.RawJson.ToInterfaceMap()
+	if err != nil {
+		return err
+	}
+
+	svcID, svcTitle := svc.RESTKey, svcMap["title"].(string)
+
+	kpis, err := provider.UnpackSlice[map[string]any](svcMap["kpis"])
+	if err != nil {
+		return err
+	}
+
+	exported := map[string]provider.KpiThresholding{}
+
+	for _, kpi := range kpis {
+		kpiID, kpiTitle := kpi["_key"].(string), kpi["title"].(string)
+
+		if strings.HasPrefix(kpiID, "SHKPI") || !p.w.kpiMatch(kpiID, kpiTitle) {
+			continue
+		}
+
+		thresholding, diags := provider.KpiThresholdingFromAPI(kpi)
+		if diags.HasError() {
+			return fmt.Errorf("failed to export thresholds of KPI %s (%s) of service %s: %#v", kpiTitle, kpiID, svcTitle, diags)
+		}
+		exported[kpiTitle] = thresholding
+	}
+
+	if len(exported) > 0 {
+		p.w.mu.Lock()
+		p.w.thresholding[svcTitle] = exported
+		p.w.mu.Unlock()
+
+		p.w.Log.Info(
+			fmt.Sprintf("Thresholds have been exported for %d KPIs of service [ %s ].", len(exported), svcTitle),
+			"service_id", svcID,
+		)
+	}
+
+	return
+}
+
+func (w *ThresholdExportWorkflow) processBatch(ctx context.Context, services []*models.ItsiObj) error {
+	return util.ProcessInParallel(ctx, &serviceThresholdExportProcessor{services, w}, w.Cfg.Concurrency)
+}
+
+func (w *ThresholdExportWorkflow) Execute(ctx context.Context) error {
+
+	w.Log.Info(
+		"Starting threshold export workflow",
+		"service_selectors", w.displaySelectors(w.services),
+		"kpi_selectors", w.displaySelectors(w.kpis),
+		"file", w.file,
+		"concurrency", w.Cfg.Concurrency,
+	)
+
+	batch := []*models.ItsiObj{}
+	for svc, err := range w.Services(ctx) {
+		if err != nil {
+			return err
+		}
+
+		if batch = append(batch, svc); len(batch)%w.Cfg.Concurrency == 0 {
+			if err = w.processBatch(ctx, batch); err != nil {
+				return err
+			}
+			batch = []*models.ItsiObj{}
+		}
+	}
+
+	if err := w.processBatch(ctx, batch); err != nil {
+		return err
+	}
+
+	by, err := yaml.Marshal(w.thresholding)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(w.file, by, 0644); err != nil {
+		return err
+	}
+
+	w.Log.Info(fmt.Sprintf("Thresholds of %d services have been written to %s.", len(w.thresholding), w.file))
+	return nil
+}
@@ -0,0 +1,218 @@
+package thld
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/itsictl/config"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+	"gopkg.in/yaml.v3"
+)
+
+type ThresholdImportWorkflow struct {
+	thresholdWorkflow
+
+	// Path of the YAML file to import the thresholds from, as written by the export workflow.
+	file string
+
+	thresholding thresholdingFile
+
+	mu sync.Mutex
+	// "service title/KPI title" of the file entries that have been matched to a KPI.
+	found util.Set[string]
+}
+
+func NewThresholdImportWorkflow(cfg config.Config, services []string, kpis []string, file string, dryrun bool) *ThresholdImportWorkflow {
+	return &ThresholdImportWorkflow{thresholdWorkflow: makeThresholdWorkflow(cfg, services, kpis, dryrun), file: file, found: util.NewSet[string]()}
+}
+
+type serviceThresholdImportProcessor struct {
+	services []*models.ItsiObj
+
+	w *ThresholdImportWorkflow
+}
+
+func (p *serviceThresholdImportProcessor) Items() []*models.ItsiObj {
+	return p.services
+}
+
+func (p *serviceThresholdImportProcessor) Process(ctx context.Context, svc *models.ItsiObj) (err error) {
+
+	svcMap, err := svc.RawJson.ToInterfaceMap()
+	if err != nil {
+		return err
+	}
+
+	svcID, svcTitle := svc.RESTKey, svcMap["title"].(string)
+
+	imported, ok := p.w.thresholding[svcTitle]
+	if !ok {
+		return
+	}
+
+	kpis, err := provider.UnpackSlice[map[string]any](svcMap["kpis"])
+	if err != nil {
+		return err
+	}
+
+	kpisUpdated := []string{}
+
+	for _, kpi := range kpis {
+		kpiID, kpiTitle := kpi["_key"].(string), kpi["title"].(string)
+
+		thresholding, ok := imported[kpiTitle]
+		if strings.HasPrefix(kpiID, "SHKPI") || !ok || !p.w.kpiMatch(kpiID, kpiTitle) {
+			continue
+		}
+
+		p.w.mu.Lock()
+		p.w.found.Add(svcTitle + "/" + kpiTitle)
+		p.w.mu.Unlock()
+
+		current, diags := provider.KpiThresholdingFromAPI(kpi)
+		if diags.HasError() {
+			return fmt.Errorf("failed to read thresholds of KPI %s (%s) of service %s: %#v", kpiTitle, kpiID, svcTitle, diags)
+		}
+		if diags = thresholding.ApplyToAPI(ctx, kpi); diags.HasError() {
+			return fmt.Errorf("failed to import thresholds of KPI %s (%s) of service %s: %#v", kpiTitle, kpiID, svcTitle, diags)
+		}
+		updated, diags := provider.KpiThresholdingFromAPI(kpi)
+		if diags.HasError() {
+			return fmt.Errorf("failed to read thresholds of KPI %s (%s) of service %s: %#v", kpiTitle, kpiID, svcTitle, diags)
+		}
+
+		diff, err := p.w.diff(current, updated)
+		if err != nil {
+			return err
+		}
+		if diff == "" {
+			p.w.Log.Debug("Thresholds are up to date", "service", svcTitle, "kpi", kpiTitle)
+			continue
+		}
+
+		p.w.Log.Info(
+			fmt.Sprintf("Thresholds of KPI [ %s ] will be updated.", kpiTitle),
+			"service_id", svcID,
+			"kpi_id", kpiID,
+			"diff", diff,
+		)
+		kpisUpdated = append(kpisUpdated, fmt.Sprintf("%s (%s)", kpiTitle, kpiID))
+	}
+
+	if len(kpisUpdated) > 0 {
+		svcMap["kpis"] = kpis
+		if err := svc.PopulateRawJSON(ctx, svcMap); err != nil {
+			return fmt.Errorf("failed to populate service api model: %w", err)
+		}
+
+		if !p.w.dryrun {
+			if diags := svc.UpdateAsync(ctx); diags.HasError() {
+				return fmt.Errorf("failed to save service: %#v", diags)
+			}
+		}
+
+		kpisUpdatedYaml, err := yaml.Marshal(kpisUpdated)
+		if err != nil {
+			return err
+		}
+
+		msg := ""
+		if !p.w.dryrun {
+			msg = fmt.Sprintf("Service [ %s ] has been saved. ", svcTitle)
+		}
+		msg += fmt.Sprintf("Thresholds have been imported for %d KPIs.", len(kpisUpdated))
+
+		p.w.Log.Info(
+			msg,
+			"service_id", svcID,
+			"kpis_updated", string(kpisUpdatedYaml),
+		)
+	}
+
+	return
+}
+
+// diff renders the changes between two KPI thresholding configurations, as YAML lines.
+func (w *ThresholdImportWorkflow) diff(current, updated provider.KpiThresholding) (string, error) {
+	currentYaml, err := yaml.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	updatedYaml, err := yaml.Marshal(updated)
+	if err != nil {
+		return "", err
+	}
+	return util.LineDiff(string(currentYaml), string(updatedYaml), 2), nil
+}
+
+func (w *ThresholdImportWorkflow) processBatch(ctx context.Context, services []*models.ItsiObj) error {
+	return util.ProcessInParallel(ctx, &serviceThresholdImportProcessor{services, w}, w.Cfg.Concurrency)
+}
+
+func (w *ThresholdImportWorkflow) Execute(ctx context.Context) error {
+
+	by, err := os.ReadFile(w.file)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(by, &w.thresholding); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", w.file, err)
+	}
+
+	// unless service selectors are provided, import the thresholds of every service in the file,
+	// looking the services up by their exact titles, since titles may contain wildcard or regular expression characters
+	var services iter.Seq2[*models.ItsiObj, error]
+	if len(w.services) == 0 {
+		titles := []string{}
+		for svcTitle := range w.thresholding {
+			titles = append(titles, svcTitle)
+		}
+		slices.Sort(titles)
+		services = w.servicesByExactTitle(ctx, titles)
+	} else {
+		services = w.Services(ctx)
+	}
+
+	w.Log.Info(
+		"Starting threshold import workflow",
+		"service_selectors", w.displaySelectors(w.services),
+		"kpi_selectors", w.displaySelectors(w.kpis),
+		"file", w.file,
+		"concurrency", w.Cfg.Concurrency,
+		"dry_run", w.dryrun,
+	)
+
+	batch := []*models.ItsiObj{}
+	for svc, err := range services {
+		if err != nil {
+			return err
+		}
+
+		if batch = append(batch, svc); len(batch)%w.Cfg.Concurrency == 0 {
+			if err = w.processBatch(ctx, batch); err != nil {
+				return err
+			}
+			batch = []*models.ItsiObj{}
+		}
+	}
+
+	if err := w.processBatch(ctx, batch); err != nil {
+		return err
+	}
+
+	for svcTitle, kpis := range w.thresholding {
+		for kpiTitle := range kpis {
+			if !w.found.Contains(svcTitle + "/" + kpiTitle) {
+				w.Log.Warn("KPI not found or not selected, skipping", "service", svcTitle, "kpi", kpiTitle)
+			}
+		}
+	}
+	return nil
+}
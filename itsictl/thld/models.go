@@ -51,12 +51,17 @@ func makeTrainingWindow(startTime int, sizeStr string) (tw trainingWindow, err e
 type trainingConfig struct {
 	trainingWindow
 	thresholdDirection string
+	recommendationConfig
 }
 
 func (tc *trainingConfig) String() string {
 	t := time.Unix(int64(tc.startTime), 0)
 	strDate := t.Format("2006-01-02 15:04:05")
-	return fmt.Sprintf("%s/%dd/%s", strDate, tc.size, tc.thresholdDirection)
+	s := fmt.Sprintf("%s/%dd/%s", strDate, tc.size, tc.thresholdDirection)
+	if settings := tc.recommendationConfig.String(); settings != "" {
+		s += "/" + settings
+	}
+	return s
 }
 
 type kpiID struct {
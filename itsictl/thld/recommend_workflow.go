@@ -2,6 +2,7 @@ package thld
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"math"
@@ -20,6 +21,14 @@ type ThresholdRecommendationWorkflow struct {
 	useLatestData          bool
 	insufficientDataAction string // 'skip' or 'reset'
 
+	// recommendation settings of every KPI, unless overridden
+	settings RecommendationSettings
+	// per-KPI recommendation settings, applied in order on top of the default settings
+	overrides []RecommendationOverride
+
+	// recommendation report, written once the workflow has completed
+	report *recommendationReport
+
 	latestDataStartDates map[int]int
 }
 
@@ -36,16 +45,36 @@ func getLatestStartDatesMap() map[int]int {
 	return startDates
 }
 
-func NewThresholdRecommendationWorkflow(cfg config.Config, services []string, kpis []string, dryrun bool, useLatestData bool, insufficientDataAction string) *ThresholdRecommendationWorkflow {
+func NewThresholdRecommendationWorkflow(cfg config.Config, services []string, kpis []string, dryrun bool, useLatestData bool, insufficientDataAction string, settings RecommendationSettings, overrides []RecommendationOverride) *ThresholdRecommendationWorkflow {
 
 	return &ThresholdRecommendationWorkflow{
 		makeThresholdWorkflow(cfg, services, kpis, dryrun),
 		useLatestData,
 		insufficientDataAction,
+		settings,
+		overrides,
+		nil,
 		getLatestStartDatesMap(),
 	}
 }
 
+// WithReport makes the workflow write a report of the recommendations to path, in the json, csv or md format.
+func (w *ThresholdRecommendationWorkflow) WithReport(path, format string) *ThresholdRecommendationWorkflow {
+	w.report = &recommendationReport{path: path, format: format}
+	return w
+}
+
+// recommendationSettings returns the recommendation settings of a KPI.
+func (w *ThresholdRecommendationWorkflow) recommendationSettings(serviceID, serviceTitle, kpiID, kpiTitle string) RecommendationSettings {
+	settings := w.settings
+	for i := range w.overrides {
+		if w.overrides[i].match(serviceID, serviceTitle, kpiID, kpiTitle) {
+			settings = settings.merge(w.overrides[i].RecommendationSettings)
+		}
+	}
+	return settings
+}
+
 func (w *ThresholdRecommendationWorkflow) newAnalysisProcessor(batches []kpisByTrainingConfig, searchConcurrency int) (p *analysisProcessor) {
 	svcByKpi := map[string]*models.ItsiObj{}
 	trainingConfigByKPI := make(map[kpiID]trainingConfig)
@@ -153,7 +182,8 @@ func (w *ThresholdRecommendationWorkflow) analysisBatches(ctx context.Context, n
 					yield(nil, err)
 					return
 				}
-				tc := trainingConfig{tw, thresholdDirection}
+				settings := w.recommendationSettings(svc.RESTKey, svcMap["title"].(string), id, kpiTitle)
+				tc := trainingConfig{tw, thresholdDirection, settings.config()}
 
 				svcTrainingConfig[tc] = append(svcTrainingConfig[tc], []kpiID{{svc, id}}...)
 
@@ -216,7 +246,18 @@ func (w *ThresholdRecommendationWorkflow) analysisParallelismParameters(preferre
 	return
 }
 
-func (w *ThresholdRecommendationWorkflow) Execute(ctx context.Context) error {
+func (w *ThresholdRecommendationWorkflow) Execute(ctx context.Context) (err error) {
+	// report the KPIs processed so far, even if the workflow fails
+	if w.report != nil {
+		defer func() {
+			if reportErr := w.report.write(); reportErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to write the recommendation report: %w", reportErr))
+			} else {
+				w.Log.Info(fmt.Sprintf("Recommendation report of %d KPIs has been written to %s.", len(w.report.entries), w.report.path))
+			}
+		}()
+	}
+
 	parallelSearches, parallelBatches := w.analysisParallelismParameters(0.5)
 
 	w.Log.Info(
@@ -225,6 +266,8 @@ func (w *ThresholdRecommendationWorkflow) Execute(ctx context.Context) error {
 		"kpi_selectors", w.displaySelectors(w.kpis),
 		"use_latest_data", w.useLatestData,
 		"insufficient_data_action", w.insufficientDataAction,
+		"recommendation_settings", w.settings.config().String(),
+		"recommendation_overrides", len(w.overrides),
 		"parallel_batches", parallelBatches,
 		"parallel_searches", parallelSearches,
 		"concurrency", w.Cfg.Concurrency,
@@ -0,0 +1,238 @@
+package thld
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/provider"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider/splunk"
+)
+
+var recommendationReportFormats = []string{"json", "csv", "md"}
+
+// RecommendationReportFormat returns the report format of a report path: the format, if set, or the file extension.
+func RecommendationReportFormat(path, format string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	if format == "markdown" {
+		format = "md"
+	}
+	if !slices.Contains(recommendationReportFormats, format) {
+		return "", fmt.Errorf("unsupported report format %q: must be one of %s", format, strings.Join(recommendationReportFormats, ", "))
+	}
+	return format, nil
+}
+
+// recommendedPolicy is a thresholding policy recommended by the ML analysis of a KPI.
+type recommendedPolicy struct {
+	RecommendationFlag string               `json:"recommendation_flag"`
+	Algorithm          string               `json:"algorithm"`
+	Cron               string               `json:"cron,omitempty"`
+	Duration           string               `json:"duration,omitempty"`
+	Mean               string               `json:"mean,omitempty"`
+	Std                string               `json:"std,omitempty"`
+	Sensitivity        string               `json:"sensitivity,omitempty"`
+	Thresholds         map[string][]float64 `json:"thresholds,omitempty"`
+}
+
+func (p recommendedPolicy) String() string {
+	s := strings.ToLower(p.Algorithm)
+	if p.Cron != "" && p.Cron != "None" {
+		s += fmt.Sprintf(" [%s, %sm]", p.Cron, p.Duration)
+	}
+
+	severities := []string{}
+	for severity := range p.Thresholds {
+		severities = append(severities, severity)
+	}
+	slices.Sort(severities)
+
+	levels := []string{}
+	for _, severity := range severities {
+		for _, param := range p.Thresholds[severity] {
+			levels = append(levels, fmt.Sprintf("%s=%g", severity, param))
+		}
+	}
+	if len(levels) > 0 {
+		s += ": " + strings.Join(levels, ", ")
+	}
+	if p.Mean != "" || p.Std != "" {
+		s += fmt.Sprintf(" (mean=%s, std=%s)", p.Mean, p.Std)
+	}
+	return s
+}
+
+// recommendationReportEntry reports the threshold recommendation of a KPI.
+type recommendationReportEntry struct {
+	ServiceID           string                    `json:"service_id"`
+	ServiceTitle        string                    `json:"service_title"`
+	KpiID               string                    `json:"kpi_id"`
+	KpiTitle            string                    `json:"kpi_title"`
+	TrainingWindow      string                    `json:"training_window"`
+	RecommendationFlags []string                  `json:"recommendation_flags"`
+	RecommendedPolicies []recommendedPolicy       `json:"recommended_policies"`
+	CurrentThresholds   *provider.KpiThresholding `json:"current_thresholds"`
+	NewThresholds       *provider.KpiThresholding `json:"new_thresholds"`
+	// configured, skipped, reset or failed
+	Action  string `json:"action"`
+	Summary string `json:"summary"`
+	// outcome of the service update: saved, dry run or failed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type recommendationReport struct {
+	path, format string
+
+	mu      sync.Mutex
+	entries []recommendationReportEntry
+}
+
+func (r *recommendationReport) add(entries ...recommendationReportEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entries...)
+}
+
+func (r *recommendationReport) write() error {
+	slices.SortFunc(r.entries, func(a, b recommendationReportEntry) int {
+		if c := strings.Compare(a.ServiceTitle, b.ServiceTitle); c != 0 {
+			return c
+		}
+		return strings.Compare(a.KpiTitle, b.KpiTitle)
+	})
+
+	var (
+		by  []byte
+		err error
+	)
+	switch r.format {
+	case "json":
+		by, err = json.MarshalIndent(r.entries, "", "  ")
+	case "csv":
+		by, err = r.csv()
+	case "md":
+		by = r.markdown()
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, by, 0644)
+}
+
+var recommendationReportColumns = []string{
+	"service_id", "service_title", "kpi_id", "kpi_title", "training_window",
+	"recommendation_flags", "recommended_policies", "current_thresholds", "new_thresholds", "action", "summary", "status", "error",
+}
+
+// rows renders the entries as table rows, joining multiple policies with sep.
+func (r *recommendationReport) rows(sep string) (rows [][]string) {
+	for _, e := range r.entries {
+		policies := make([]string, len(e.RecommendedPolicies))
+		for i, p := range e.RecommendedPolicies {
+			policies[i] = p.String()
+		}
+		rows = append(rows, []string{
+			e.ServiceID, e.ServiceTitle, e.KpiID, e.KpiTitle, e.TrainingWindow,
+			strings.Join(e.RecommendationFlags, ", "),
+			strings.Join(policies, sep),
+			thresholdingSummary(e.CurrentThresholds, sep),
+			thresholdingSummary(e.NewThresholds, sep),
+			e.Action, e.Summary, e.Status, e.Error,
+		})
+	}
+	return
+}
+
+func (r *recommendationReport) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(recommendationReportColumns); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(r.rows("; ")); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *recommendationReport) markdown() []byte {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	var buf bytes.Buffer
+	buf.WriteString("| " + strings.Join(recommendationReportColumns, " | ") + " |\n")
+	buf.WriteString(strings.Repeat("| --- ", len(recommendationReportColumns)) + "|\n")
+	for _, row := range r.rows("<br>") {
+		for i := range row {
+			row[i] = escape.Replace(row[i])
+		}
+		buf.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	return buf.Bytes()
+}
+
+// thresholdingSummary renders the aggregate threshold values of every policy of a KPI, joining policies with sep.
+func thresholdingSummary(t *provider.KpiThresholding, sep string) string {
+	if t == nil {
+		return ""
+	}
+
+	policies := []string{}
+	for _, p := range t.TimeVariateThresholdsSpecification.Policies {
+		if !t.TimeVariateThresholds && p.PolicyName != "default_policy" {
+			continue
+		}
+		levels := []string{}
+		for _, l := range p.AggregateThresholds.ThresholdLevels {
+			levels = append(levels, fmt.Sprintf("%s=%g", l.SeverityLabel, l.ThresholdValue))
+		}
+		policies = append(policies, fmt.Sprintf("%s (%s): %s", p.Title, p.PolicyType, strings.Join(levels, ", ")))
+	}
+	return strings.Join(policies, sep)
+}
+
+// recommendedPolicies extracts the recommended policies of a KPI from the ML analysis search results.
+func recommendedPolicies(rows []map[string]splunk.Value, parseThresholds func(string) map[string][]float64) (policies []recommendedPolicy, flags []string) {
+	str := func(row map[string]splunk.Value, field string) string {
+		s, _ := row[field].(string)
+		return s
+	}
+
+	for _, row := range rows {
+		p := recommendedPolicy{
+			RecommendationFlag: str(row, "Recommendation Flag"),
+			Algorithm:          str(row, "Algorithm"),
+			Cron:               str(row, "Cron Expression"),
+			Duration:           str(row, "Duration"),
+			Mean:               str(row, "Mean"),
+			Std:                str(row, "Std"),
+			Sensitivity:        str(row, "Sensitivity"),
+		}
+		if algo := strings.ToLower(p.Algorithm); algo != "none" && str(row, "Thresholds") != "" {
+			p.Thresholds = parseThresholds(str(row, "Thresholds"))
+		}
+		policies = append(policies, p)
+
+		if !slices.Contains(flags, p.RecommendationFlag) {
+			flags = append(flags, p.RecommendationFlag)
+		}
+	}
+
+	if len(rows) == 0 {
+		flags = []string{"INSUFFICIENT_DATA"}
+	}
+	return
+}
+
+func (tc *trainingConfig) window() string {
+	return fmt.Sprintf("%s/%dd", time.Unix(int64(tc.startTime), 0).UTC().Format(time.RFC3339), tc.size)
+}
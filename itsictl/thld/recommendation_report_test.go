@@ -0,0 +1,142 @@
+package thld
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/itsictl/config"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider/splunk"
+)
+
+func testRecommendationReport() *recommendationReport {
+	return &recommendationReport{
+		path:   "report",
+		format: "csv",
+		entries: []recommendationReportEntry{
+			{
+				ServiceID:           "svc1",
+				ServiceTitle:        "service | one",
+				KpiID:               "kpi1",
+				KpiTitle:            "errors",
+				TrainingWindow:      "2026-01-01T00:00:00Z/14d",
+				RecommendationFlags: []string{"OK"},
+				RecommendedPolicies: []recommendedPolicy{
+					{RecommendationFlag: "OK", Algorithm: "Stdev", Cron: "0 0 * * 6", Duration: "2880", Mean: "10", Std: "2", Thresholds: map[string][]float64{"high": {2}}},
+					{RecommendationFlag: "OK", Algorithm: "Stdev", Cron: "None", Mean: "5", Std: "1", Thresholds: map[string][]float64{"critical": {3}}},
+				},
+				Action:  "configured",
+				Summary: "time variate, adaptive (stdev)",
+				Status:  "failed",
+				Error:   "failed to save service:\nunavailable",
+			},
+		},
+	}
+}
+
+func TestRecommendationReportCSV(t *testing.T) {
+	by, err := testRecommendationReport().csv()
+	if err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(by))).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read the CSV report: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected a header and 1 row, got %d records", len(records))
+	}
+	if !slices.Equal(records[0], recommendationReportColumns) {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	for column, expected := range map[string]string{
+		"service_title":        "service | one",
+		"recommendation_flags": "OK",
+		"recommended_policies": "stdev [0 0 * * 6, 2880m]: high=2 (mean=10, std=2); stdev: critical=3 (mean=5, std=1)",
+		"action":               "configured",
+		"status":               "failed",
+		"error":                "failed to save service:\nunavailable",
+	} {
+		if row[column] != expected {
+			t.Errorf("%s: expected %q, got %q", column, expected, row[column])
+		}
+	}
+}
+
+func TestRecommendationReportMarkdown(t *testing.T) {
+	lines := strings.Split(strings.TrimSuffix(string(testRecommendationReport().markdown()), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header, a separator and 1 row, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if expected := "| " + strings.Join(recommendationReportColumns, " | ") + " |"; lines[0] != expected {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if expected := strings.Repeat("| --- ", len(recommendationReportColumns)) + "|"; lines[1] != expected {
+		t.Errorf("unexpected separator: %s", lines[1])
+	}
+
+	expected := "| svc1 | service \\| one | kpi1 | errors | 2026-01-01T00:00:00Z/14d | OK | " +
+		"stdev [0 0 * * 6, 2880m]: high=2 (mean=10, std=2)<br>stdev: critical=3 (mean=5, std=1) |  |  | " +
+		"configured | time variate, adaptive (stdev) | failed | failed to save service: unavailable |"
+	if lines[2] != expected {
+		t.Errorf("unexpected row:\n%s\nexpected:\n%s", lines[2], expected)
+	}
+}
+
+func TestReportServiceUpdate(t *testing.T) {
+	w := NewThresholdRecommendationWorkflow(config.Config{}, nil, nil, false, false, "skip", RecommendationSettings{}, nil).WithReport("report.csv", "csv")
+	p := &serviceThresholdUpdateProcessor{workflow: w}
+	c := &serviceThresholdingConfigurator{
+		serviceID:    "svc1",
+		serviceTitle: "service",
+		policies: policiesByKpi{
+			"kpi1": []map[string]splunk.Value{{"Recommendation Flag": "OK", "Algorithm": "Static", "Cron Expression": "None", "Mean": "1", "Std": "0", "Thresholds": "{'high': 5}"}},
+			"kpi2": []map[string]splunk.Value{{"Recommendation Flag": "OK", "Algorithm": "Unknown"}},
+		},
+		kpisToConfigure: []map[string]any{
+			{"_key": "kpi1", "title": "latency"},
+			{"_key": "kpi2", "title": "errors"},
+		},
+		trainingConfigByKPI: map[kpiID]trainingConfig{},
+		workflow:            w,
+		changeSummary:       map[string]string{},
+	}
+
+	// the second KPI fails to be configured, so the service is not saved
+	err := c.Configure()
+	if err == nil {
+		t.Fatalf("expected an error for an unsupported recommendation")
+	}
+	p.reportServiceUpdate(c, err)
+
+	entries := w.report.entries
+	if len(entries) != 2 {
+		t.Fatalf("expected both KPIs to be reported, got %+v", entries)
+	}
+	if e := entries[0]; e.KpiID != "kpi1" || e.Action != "configured" || e.Status != "failed" || e.Error != err.Error() {
+		t.Errorf("unexpected entry of the configured KPI: %+v", e)
+	}
+	if e := entries[1]; e.KpiID != "kpi2" || e.Action != "failed" || e.Status != "failed" || !strings.Contains(e.Error, "unknown (OK) recommendation is not supported yet") {
+		t.Errorf("unexpected entry of the failed KPI: %+v", e)
+	}
+
+	w.report.entries = nil
+	p.reportServiceUpdate(&serviceThresholdingConfigurator{reportEntries: []recommendationReportEntry{{KpiID: "kpi1"}}}, nil)
+	if e := w.report.entries[0]; e.Status != "saved" || e.Error != "" {
+		t.Errorf("expected a saved entry, got %+v", e)
+	}
+
+	w.dryrun = true
+	w.report.entries = nil
+	p.reportServiceUpdate(&serviceThresholdingConfigurator{reportEntries: []recommendationReportEntry{{KpiID: "kpi1"}}}, nil)
+	if e := w.report.entries[0]; e.Status != "dry run" {
+		t.Errorf("expected a dry run entry, got %+v", e)
+	}
+}
@@ -0,0 +1,202 @@
+package thld
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	recommendationAlgorithms     = []string{"stdev", "quantile", "range"}
+	recommendationSeverityLevels = []string{"info", "low", "medium", "high", "critical"}
+)
+
+/*
+RecommendationSettings tune how ML-assisted threshold recommendations are turned into KPI thresholds.
+Zero values keep the behaviour of the recommendation search.
+*/
+type RecommendationSettings struct {
+	// Adaptive thresholding algorithm of the recommended adaptive policies: stdev, quantile or range.
+	Algorithm string `yaml:"algorithm,omitempty"`
+
+	// Outlier exclusion sensitivity. By default, the sensitivity recommended by the analysis is used.
+	Sensitivity float64 `yaml:"sensitivity,omitempty"`
+
+	// Minimum number of days of KPI data in the training window. KPIs with less data are treated as having insufficient data.
+	MinTrainingDays int `yaml:"min_training_days,omitempty"`
+
+	// Severity levels to generate. By default, all recommended severity levels are generated.
+	SeverityLevels []string `yaml:"severity_levels,omitempty"`
+}
+
+func (s RecommendationSettings) Validate() error {
+	if s.Algorithm != "" && !slices.Contains(recommendationAlgorithms, s.Algorithm) {
+		return fmt.Errorf("invalid algorithm %q: must be one of %s", s.Algorithm, strings.Join(recommendationAlgorithms, ", "))
+	}
+	if s.Sensitivity < 0 {
+		return fmt.Errorf("invalid sensitivity %v: must be positive", s.Sensitivity)
+	}
+	if s.MinTrainingDays < 0 {
+		return fmt.Errorf("invalid minimum training days %d: must be positive", s.MinTrainingDays)
+	}
+	for _, severity := range s.SeverityLevels {
+		if !slices.Contains(recommendationSeverityLevels, severity) {
+			return fmt.Errorf("invalid severity level %q: must be one of %s", severity, strings.Join(recommendationSeverityLevels, ", "))
+		}
+	}
+	return nil
+}
+
+// merge returns the settings with the non-zero settings of o applied on top.
+func (s RecommendationSettings) merge(o RecommendationSettings) RecommendationSettings {
+	if o.Algorithm != "" {
+		s.Algorithm = o.Algorithm
+	}
+	if o.Sensitivity != 0 {
+		s.Sensitivity = o.Sensitivity
+	}
+	if o.MinTrainingDays != 0 {
+		s.MinTrainingDays = o.MinTrainingDays
+	}
+	if len(o.SeverityLevels) > 0 {
+		s.SeverityLevels = o.SeverityLevels
+	}
+	return s
+}
+
+// config returns the comparable form of the settings, to be used as a part of a training config.
+func (s RecommendationSettings) config() recommendationConfig {
+	severityLevels := slices.Clone(s.SeverityLevels)
+	slices.Sort(severityLevels)
+	return recommendationConfig{
+		algorithm:       s.Algorithm,
+		sensitivity:     s.Sensitivity,
+		minTrainingDays: s.MinTrainingDays,
+		severityLevels:  strings.Join(slices.Compact(severityLevels), ","),
+	}
+}
+
+/*
+RecommendationOverride applies recommendation settings to the KPIs matching its selectors.
+Each selector is an ID or a title wildcard expression (case insensitive); an empty selector matches everything.
+*/
+type RecommendationOverride struct {
+	Service string `yaml:"service"`
+	KPI     string `yaml:"kpi"`
+
+	RecommendationSettings `yaml:",inline"`
+
+	serviceWildcard, kpiWildcard *regexp.Regexp
+}
+
+func (o *RecommendationOverride) match(serviceID, serviceTitle, kpiID, kpiTitle string) bool {
+	matchSelector := func(selector string, re *regexp.Regexp, id, title string) bool {
+		return selector == "" || selector == id || re.MatchString(title)
+	}
+	return matchSelector(o.Service, o.serviceWildcard, serviceID, serviceTitle) &&
+		matchSelector(o.KPI, o.kpiWildcard, kpiID, kpiTitle)
+}
+
+/*
+LoadRecommendationOverrides reads per-KPI recommendation settings from a YAML file:
+
+	overrides:
+	  - service: "sample service*"
+	    kpi: errors
+	    algorithm: quantile
+	    severity_levels: [high, critical]
+*/
+func LoadRecommendationOverrides(path string) ([]RecommendationOverride, error) {
+	by, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Overrides []RecommendationOverride `yaml:"overrides"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(by))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for i := range file.Overrides {
+		o := &file.Overrides[i]
+		if err := o.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: override #%d: %w", path, i+1, err)
+		}
+		o.serviceWildcard, o.kpiWildcard = util.WildcardToRegexp(o.Service), util.WildcardToRegexp(o.KPI)
+	}
+	return file.Overrides, nil
+}
+
+// recommendationConfig is the comparable form of RecommendationSettings.
+type recommendationConfig struct {
+	algorithm       string
+	sensitivity     float64
+	minTrainingDays int
+	severityLevels  string // sorted, comma separated
+}
+
+func (c recommendationConfig) String() string {
+	settings := []string{}
+	if c.algorithm != "" {
+		settings = append(settings, c.algorithm)
+	}
+	if c.sensitivity != 0 {
+		settings = append(settings, fmt.Sprintf("sensitivity=%v", c.sensitivity))
+	}
+	if c.minTrainingDays != 0 {
+		settings = append(settings, fmt.Sprintf("min=%dd", c.minTrainingDays))
+	}
+	if c.severityLevels != "" {
+		settings = append(settings, c.severityLevels)
+	}
+	return strings.Join(settings, "/")
+}
+
+func (c recommendationConfig) severityLevelEnabled(severity string) bool {
+	return c.severityLevels == "" || slices.Contains(strings.Split(c.severityLevels, ","), severity)
+}
+
+/*
+recommendationSearchArgs returns the arguments of the recommendthresholdtemplate search command for a training config,
+so that the analysis recommends the dynamic parameters of the configured algorithm and sensitivity
+rather than standard deviation multiples to be converted.
+*/
+func recommendationSearchArgs(tc trainingConfig) string {
+	args := []string{fmt.Sprintf("threshold_direction=%s", tc.thresholdDirection)}
+	if tc.algorithm != "" {
+		args = append(args, fmt.Sprintf("algorithm=%s", tc.algorithm))
+	}
+	if tc.sensitivity != 0 {
+		args = append(args, fmt.Sprintf("sensitivity=%s", strconv.FormatFloat(tc.sensitivity, 'f', -1, 64)))
+	}
+	return strings.Join(args, " ")
+}
+
+/*
+recommendedThresholdValue returns the initial threshold value of a threshold level recommended with the dynamic parameter of algo:
+  - stdev: the multiple of standard deviations from the mean of the training data,
+  - quantile, range: the mean of the training data, until ITSI's adaptive thresholds search computes the value from the KPI data,
+  - static: the dynamic parameter itself.
+*/
+func recommendedThresholdValue(algo string, mean, std, dynamicParam float64) float64 {
+	switch algo {
+	case "stdev":
+		return mean + std*dynamicParam
+	case "quantile", "range":
+		return mean
+	default:
+		return dynamicParam
+	}
+}
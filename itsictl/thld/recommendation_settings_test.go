@@ -0,0 +1,114 @@
+package thld
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/itsictl/config"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider/splunk"
+)
+
+func TestRecommendationSearchArgs(t *testing.T) {
+	for _, tc := range []struct {
+		config   trainingConfig
+		expected string
+	}{
+		{
+			config:   trainingConfig{thresholdDirection: "both"},
+			expected: "threshold_direction=both",
+		},
+		{
+			config:   trainingConfig{thresholdDirection: "upper", recommendationConfig: recommendationConfig{algorithm: "quantile"}},
+			expected: "threshold_direction=upper algorithm=quantile",
+		},
+		{
+			config:   trainingConfig{thresholdDirection: "lower", recommendationConfig: recommendationConfig{algorithm: "range", sensitivity: 2.5}},
+			expected: "threshold_direction=lower algorithm=range sensitivity=2.5",
+		},
+		{
+			// settings applied locally are not passed to the analysis
+			config:   trainingConfig{thresholdDirection: "both", recommendationConfig: recommendationConfig{minTrainingDays: 14, severityLevels: "critical,high"}},
+			expected: "threshold_direction=both",
+		},
+	} {
+		if actual := recommendationSearchArgs(tc.config); actual != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.config.String(), tc.expected, actual)
+		}
+	}
+}
+
+func TestRecommendedThresholdValue(t *testing.T) {
+	for _, tc := range []struct {
+		algo     string
+		param    float64
+		expected float64
+	}{
+		{"stdev", 2, 14},
+		{"stdev", -1, 8},
+		{"quantile", 0.95, 10},
+		{"range", 0.8, 10},
+		{"static", 42, 42},
+	} {
+		if actual := recommendedThresholdValue(tc.algo, 10, 2, tc.param); actual != tc.expected {
+			t.Errorf("%s(%v): expected %v, got %v", tc.algo, tc.param, tc.expected, actual)
+		}
+	}
+}
+
+func TestConfigureKPIRecommendedAlgorithm(t *testing.T) {
+	configurator := func(algorithm string) (*serviceThresholdingConfigurator, map[string]any) {
+		kpi := map[string]any{"_key": "kpi1", "title": "errors"}
+		w := NewThresholdRecommendationWorkflow(config.Config{}, nil, nil, true, false, "skip", RecommendationSettings{}, nil)
+		return &serviceThresholdingConfigurator{
+			serviceID:    "svc1",
+			serviceTitle: "service",
+			policies: policiesByKpi{"kpi1": []map[string]splunk.Value{{
+				"Recommendation Flag": "OK",
+				"Algorithm":           "Quantile",
+				"Cron Expression":     "0 0 * * 6",
+				"Duration":            "2880",
+				"Mean":                "10",
+				"Std":                 "2",
+				"Sensitivity":         "3",
+				"Thresholds":          "{'high': 0.95, 'critical': [0.99]}",
+			}}},
+			trainingConfigByKPI: map[kpiID]trainingConfig{
+				{nil, "kpi1"}: {trainingWindow{startTime: 0, size: 14}, "upper", recommendationConfig{algorithm: algorithm}},
+			},
+			workflow:      w,
+			changeSummary: map[string]string{},
+		}, kpi
+	}
+
+	c, kpi := configurator("quantile")
+	if err := c.configureKPI(kpi); err != nil {
+		t.Fatalf("configureKPI: %v", err)
+	}
+
+	policies := kpi["time_variate_thresholds_specification"].(map[string]any)["policies"].(map[string]any)
+	var policy map[string]any
+	for key, p := range policies {
+		if key != "default_policy" {
+			policy = p.(map[string]any)
+		}
+	}
+	if policy == nil || policy["policy_type"] != "quantile" {
+		t.Fatalf("expected a quantile policy, got %#v", policies)
+	}
+
+	params := map[string]float64{}
+	for _, level := range policy["aggregate_thresholds"].(map[string]any)["thresholdLevels"].([]map[string]any) {
+		params[level["severityLabel"].(string)] = level["dynamicParam"].(float64)
+	}
+	if params["high"] != 0.95 || params["critical"] != 0.99 {
+		t.Errorf("expected the recommended dynamic parameters to be used as is, got %v", params)
+	}
+	if kpi["adaptive_thresholds_is_enabled"] != true {
+		t.Errorf("expected adaptive thresholds to be enabled")
+	}
+
+	c, kpi = configurator("range")
+	if err := c.configureKPI(kpi); err == nil || !strings.Contains(err.Error(), "quantile policy was recommended instead of range") {
+		t.Errorf("expected an error for a recommendation of another algorithm, got %v", err)
+	}
+}
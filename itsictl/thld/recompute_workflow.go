@@ -0,0 +1,132 @@
+package thld
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tivo/terraform-provider-splunk-itsi/itsictl/config"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider"
+	"gopkg.in/yaml.v3"
+)
+
+type ThresholdRecomputeWorkflow struct {
+	thresholdWorkflow
+
+	/*
+		Threshold template selector: a threshold template ID or title.
+		If set, only KPIs linked to the threshold template are recomputed.
+	*/
+	template string
+
+	templateKey string
+}
+
+func NewThresholdRecomputeWorkflow(cfg config.Config, services []string, kpis []string, template string, dryrun bool) *ThresholdRecomputeWorkflow {
+	return &ThresholdRecomputeWorkflow{thresholdWorkflow: makeThresholdWorkflow(cfg, services, kpis, dryrun), template: template}
+}
+
+func (w *ThresholdRecomputeWorkflow) Execute(ctx context.Context) error {
+
+	w.Log.Info(
+		"Starting adaptive threshold recompute workflow",
+		"service_selectors", w.displaySelectors(w.services),
+		"kpi_selectors", w.displaySelectors(w.kpis),
+		"threshold_template", w.template,
+		"dry_run", w.dryrun,
+	)
+
+	services := w.Services(ctx)
+	if w.template != "" {
+		template, err := w.thresholdTemplate(ctx, w.template)
+		if err != nil {
+			return err
+		}
+		w.templateKey = template.RESTKey
+		if len(w.services) == 0 {
+			filter := fmt.Sprintf(`{"kpis.kpi_threshold_template_id": %q}`, w.templateKey)
+			services = w.servicesIter(ctx, &models.Parameters{Filter: filter})
+		}
+	}
+
+	match := func(kpi map[string]any) bool {
+		kpiID, _ := kpi["_key"].(string)
+		kpiTitle, _ := kpi["title"].(string)
+		return w.kpiMatch(kpiID, kpiTitle) && (w.templateKey == "" || kpi["kpi_threshold_template_id"] == w.templateKey)
+	}
+
+	kpis := []provider.AdaptiveThresholdsKpi{}
+	for svc, err := range services {
+		if err != nil {
+			return err
+		}
+		svcKpis, err := provider.AdaptiveThresholdsKpis(svc, match)
+		if err != nil {
+			return fmt.Errorf("failed to select the KPIs of service %s: %w", svc.RESTKey, err)
+		}
+		kpis = append(kpis, svcKpis...)
+	}
+
+	if len(kpis) == 0 {
+		w.Log.Info("No matching KPIs with adaptive thresholds enabled were found.")
+		return nil
+	}
+
+	// ITSI only has adaptive thresholds searches for some training windows: fail before any search is triggered
+	unsupported := []error{}
+	for _, kpi := range kpis {
+		if err := provider.ValidateAdaptiveThresholdsTrainingWindow(kpi.TrainingWindow); err != nil {
+			unsupported = append(unsupported, fmt.Errorf("KPI %q of service %q: %w", kpi.KpiTitle, kpi.ServiceTitle, err))
+		}
+	}
+	if len(unsupported) > 0 {
+		return errors.Join(unsupported...)
+	}
+
+	for _, kpi := range kpis {
+		w.Log.Info(
+			"Selected KPI for adaptive thresholds recomputation.",
+			"service", kpi.ServiceTitle,
+			"kpi", kpi.KpiTitle,
+			"training_window", kpi.TrainingWindow,
+		)
+	}
+	if !w.dryrun {
+		w.Log.Info("Triggering ITSI's adaptive thresholds searches and waiting for them to complete.", "kpis", len(kpis))
+	}
+
+	results, diags := provider.RecomputeAdaptiveThresholds(ctx, w.Cfg.ClientConfig(), kpis, w.dryrun)
+	if diags.HasError() {
+		return fmt.Errorf("failed to recompute adaptive thresholds: %#v", diags)
+	}
+
+	for _, kpi := range results {
+		thresholds := map[string]map[string]float64{}
+		for _, policy := range kpi.Policies {
+			thresholds[policy.Name] = map[string]float64{}
+			for _, level := range policy.Levels {
+				thresholds[policy.Name][level.Severity] = level.Threshold
+			}
+		}
+
+		thresholdsYaml, err := yaml.Marshal(thresholds)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("Adaptive thresholds have been recomputed for KPI [ %s ] of service [ %s ].", kpi.KpiTitle, kpi.ServiceTitle)
+		if w.dryrun {
+			msg = fmt.Sprintf("Current adaptive thresholds of KPI [ %s ] of service [ %s ].", kpi.KpiTitle, kpi.ServiceTitle)
+		}
+
+		w.Log.Info(
+			msg,
+			"service_id", kpi.ServiceID,
+			"kpi_id", kpi.KpiID,
+			"thresholds", string(thresholdsYaml),
+		)
+	}
+
+	return nil
+}
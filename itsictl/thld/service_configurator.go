@@ -31,6 +31,9 @@ type serviceThresholdingConfigurator struct {
 
 	changeSummary map[string]string
 	failedKpis    util.Set[string]
+
+	// report entries of the configured KPIs, if a report is requested
+	reportEntries []recommendationReportEntry
 }
 
 func (c *serviceThresholdingConfigurator) parseThresholds(thldsStr string) map[string][]float64 {
@@ -60,8 +63,65 @@ func (c *serviceThresholdingConfigurator) parseThresholds(thldsStr string) map[s
 	return normalizedThresholds
 }
 
-func (c *serviceThresholdingConfigurator) recordKPIUpdateSummary(kpiKey, kpiTitle, summary string) {
+func (c *serviceThresholdingConfigurator) recordKPIUpdateSummary(kpi map[string]any, current *provider.KpiThresholding, action, summary string) {
+	kpiKey, kpiTitle := kpi["_key"].(string), kpi["title"].(string)
 	c.changeSummary[fmt.Sprintf("%s (%s)", kpiTitle, kpiKey)] = summary
+
+	if c.workflow.report == nil {
+		return
+	}
+
+	tc := c.trainingConfigByKPI[kpiID{c.service, kpiKey}]
+	policies, flags := recommendedPolicies(c.policies[kpiKey], c.parseThresholds)
+	c.reportEntries = append(c.reportEntries, recommendationReportEntry{
+		ServiceID:           c.serviceID,
+		ServiceTitle:        c.serviceTitle,
+		KpiID:               kpiKey,
+		KpiTitle:            kpiTitle,
+		TrainingWindow:      tc.window(),
+		RecommendationFlags: flags,
+		RecommendedPolicies: policies,
+		CurrentThresholds:   current,
+		NewThresholds:       c.thresholding(kpi),
+		Action:              action,
+		Summary:             summary,
+	})
+}
+
+// recordKPIUpdateFailure reports a KPI whose thresholds could not be configured, if a report is requested.
+func (c *serviceThresholdingConfigurator) recordKPIUpdateFailure(kpi map[string]any, err error) {
+	if c.workflow.report == nil {
+		return
+	}
+
+	kpiKey, kpiTitle := kpi["_key"].(string), kpi["title"].(string)
+	tc := c.trainingConfigByKPI[kpiID{c.service, kpiKey}]
+	policies, flags := recommendedPolicies(c.policies[kpiKey], c.parseThresholds)
+	c.reportEntries = append(c.reportEntries, recommendationReportEntry{
+		ServiceID:           c.serviceID,
+		ServiceTitle:        c.serviceTitle,
+		KpiID:               kpiKey,
+		KpiTitle:            kpiTitle,
+		TrainingWindow:      tc.window(),
+		RecommendationFlags: flags,
+		RecommendedPolicies: policies,
+		CurrentThresholds:   c.thresholding(kpi),
+		Action:              "failed",
+		Error:               err.Error(),
+	})
+}
+
+// thresholding returns the thresholding configuration of a KPI to report, if a report is requested.
+func (c *serviceThresholdingConfigurator) thresholding(kpi map[string]any) *provider.KpiThresholding {
+	if c.workflow.report == nil {
+		return nil
+	}
+	t, diags := provider.KpiThresholdingFromAPI(kpi)
+	if diags.HasError() {
+		c.workflow.Log.Debug("Failed to read KPI thresholds for the report", "service_id", c.serviceID, "kpi_id", kpi["_key"], "diags", diags)
+		return nil
+	}
+	return &t
 }
 
 func (c *serviceThresholdingConfigurator) kpiConfError(kpiID, kpiTitle string, err error) error {
@@ -74,6 +134,8 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 
 	kpiKey, kpiTitle := kpi["_key"].(string), kpi["title"].(string)
 	kpiPolicies := c.policies[kpiKey]
+	tc := c.trainingConfigByKPI[kpiID{c.service, kpiKey}]
+	current := c.thresholding(kpi)
 
 	timeVariateThresholdsEnabled := false
 	adaptiveThresholdsEnabled := false
@@ -83,6 +145,9 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 
 	var sensitivity float64
 
+	// adaptive thresholding algorithm of the recommended adaptive policies
+	var adaptiveAlgo string
+
 	timeVariateThresholdPolicies := c.workflow.defaultPolicies()
 
 	if len(kpiPolicies) > 0 {
@@ -114,10 +179,14 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 			return c.kpiConfError(kpiKey, kpiTitle, fmt.Errorf("unexpected KPI policy (%#v): Alogrithm is not provided", kpiPolicy))
 		}
 
-		//ATM, we only support stdev and static policies
+		//ATM, we only support adaptive and static policies
 		switch algo {
-		case "stdev":
+		case "stdev", "quantile", "range":
+			if tc.algorithm != "" && algo != tc.algorithm {
+				return c.kpiConfError(kpiKey, kpiTitle, fmt.Errorf("%s policy was recommended instead of %s", algo, tc.algorithm))
+			}
 			adaptiveThresholdsEnabled = true
+			adaptiveAlgo = algo
 		case "static":
 		case "none":
 			if recommendationFlag == "CONSTANT_KPI" && len(kpiPolicies) == 1 {
@@ -148,7 +217,9 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 		}
 
 		if adaptiveThresholdsEnabled {
-			if sensitivity, err = strconv.ParseFloat(kpiPolicy["Sensitivity"].(string), 64); err != nil {
+			if tc.sensitivity != 0 {
+				sensitivity = tc.sensitivity
+			} else if sensitivity, err = strconv.ParseFloat(kpiPolicy["Sensitivity"].(string), 64); err != nil {
 				return c.kpiConfError(kpiKey, kpiTitle, fmt.Errorf("could not parse Sensitivity"))
 			}
 		}
@@ -158,14 +229,11 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 		aggregateThresholdLevels := []map[string]any{}
 
 		for severity, dynamicParams := range thresholds {
+			if !tc.severityLevelEnabled(severity) {
+				continue
+			}
 			for _, dynamicParam := range dynamicParams {
-				thldValue := 0.0
-				if algo == "stdev" {
-					thldValue = mean + std*dynamicParam
-				} else {
-					//static
-					thldValue = dynamicParam
-				}
+				thldValue := recommendedThresholdValue(algo, mean, std, dynamicParam)
 
 				itsiThldLevel := map[string]any{
 					"severityLabel":      severity,
@@ -187,11 +255,15 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 
 			title := fmt.Sprintf("[%s] %v", cron, duration) //TODO: improve policy title generation
 			key := util.Sha256(title)
+			policyType := "stdev"
+			if algo != "static" {
+				policyType = algo
+			}
 			itsiPolicy := map[string]any{
 				"title":                title,
 				"aggregate_thresholds": c.workflow.aggregateThresholds(aggregateThresholdLevels),
 				"entity_thresholds":    c.workflow.entityThresholds(),
-				"policy_type":          "stdev",
+				"policy_type":          policyType,
 				"time_blocks":          []any{[]any{cron, duration}},
 			}
 			timeVariateThresholdPolicies[key] = itsiPolicy
@@ -243,7 +315,7 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 			details = append(details, "time variate")
 		}
 		if adaptiveThresholdsEnabled {
-			details = append(details, "adaptive (stdev)")
+			details = append(details, fmt.Sprintf("adaptive (%s)", adaptiveAlgo))
 		} else {
 			if isConstantKPI {
 				details = append(details, "None (constant KPI)")
@@ -260,7 +332,15 @@ func (c *serviceThresholdingConfigurator) configureKPI(kpi map[string]any) (err
 		}
 	}
 
-	c.recordKPIUpdateSummary(kpiKey, kpiTitle, strings.Join(details, ", "))
+	action := "configured"
+	if !ok {
+		action = "skipped"
+		if c.workflow.insufficientDataAction == "reset" {
+			action = "reset"
+		}
+	}
+
+	c.recordKPIUpdateSummary(kpi, current, action, strings.Join(details, ", "))
 	return nil
 }
 
@@ -268,6 +348,7 @@ func (c *serviceThresholdingConfigurator) Configure() error {
 	var err error
 	for _, kpi := range c.kpisToConfigure {
 		if err = c.configureKPI(kpi); err != nil {
+			c.recordKPIUpdateFailure(kpi, err)
 			return err
 		}
 	}
@@ -93,21 +93,46 @@ func (p *serviceThresholdUpdateProcessor) Process(ctx context.Context, svc *mode
 		return err
 	}
 
-	if err = configurator.Configure(); err != nil {
-		return err
-	}
-
-	if !p.workflow.dryrun {
+	err = configurator.Configure()
+	if err == nil && !p.workflow.dryrun {
 		if diags := svc.UpdateAsync(ctx); diags.HasError() {
-			return fmt.Errorf("failed to save service: %#v", diags)
+			err = fmt.Errorf("failed to save service: %#v", diags)
 		}
 	}
 
-	p.printServiceUpdateSummary(configurator)
+	// report the KPIs of the service whether or not it could be updated
+	p.reportServiceUpdate(configurator, err)
+	if err != nil {
+		return err
+	}
 
+	p.printServiceUpdateSummary(configurator)
 	return nil
 }
 
+// reportServiceUpdate adds the report entries of the configured KPIs, with the outcome of the service update, if a report is requested.
+func (p *serviceThresholdUpdateProcessor) reportServiceUpdate(configurator *serviceThresholdingConfigurator, err error) {
+	if p.workflow.report == nil {
+		return
+	}
+
+	for i := range configurator.reportEntries {
+		e := &configurator.reportEntries[i]
+		switch {
+		case err != nil:
+			e.Status = "failed"
+			if e.Error == "" {
+				e.Error = err.Error()
+			}
+		case p.workflow.dryrun:
+			e.Status = "dry run"
+		default:
+			e.Status = "saved"
+		}
+	}
+	p.workflow.report.add(configurator.reportEntries...)
+}
+
 func (p *serviceThresholdUpdateProcessor) printServiceUpdateSummary(configurator *serviceThresholdingConfigurator) {
 	summaryYaml, err := yaml.Marshal(configurator.changeSummary)
 	if err != nil {
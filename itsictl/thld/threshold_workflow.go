@@ -118,6 +118,23 @@ func (w *thresholdWorkflow) servicesByTitle(ctx context.Context, titlePattern st
 	return w.servicesIter(ctx, &models.Parameters{Filter: filter})
 }
 
+// servicesByExactTitle streams the services with the given titles, matched exactly rather than as wildcard expressions.
+func (w *thresholdWorkflow) servicesByExactTitle(ctx context.Context, titles []string) iter.Seq2[*models.ItsiObj, error] {
+	iters := []iter.Seq2[*models.ItsiObj, error]{}
+	for c := range slices.Chunk(titles, 10) {
+		conditions := make([]map[string]string, len(c))
+		for i, title := range c {
+			conditions[i] = map[string]string{"title": title}
+		}
+		filter, err := json.Marshal(map[string]any{"$or": conditions})
+		if err != nil {
+			w.Log.Fatal("Failed to render a filter expression to filter services by title", "titles", c)
+		}
+		iters = append(iters, w.servicesIter(ctx, &models.Parameters{Filter: string(filter)}))
+	}
+	return util.Concat2(iters...)
+}
+
 /*
 Returns an iterator that will stream service objects.
 If `services` field is not empty, only services matching provided selectors will be streamed.
@@ -162,6 +179,22 @@ func (w *thresholdWorkflow) kpiMatch(kpiID, kpiTitle string) bool {
 	return false
 }
 
+// thresholdTemplate looks up a threshold template by ID or title.
+func (w *thresholdWorkflow) thresholdTemplate(ctx context.Context, selector string) (*models.ItsiObj, error) {
+	client := w.Cfg.ClientConfig()
+
+	template, err := models.NewItsiObj(client, selector, "", "kpi_threshold_template").Read(ctx)
+	if err != nil || template == nil {
+		if template, err = models.NewItsiObj(client, "", selector, "kpi_threshold_template").Find(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if template == nil {
+		return nil, fmt.Errorf("threshold template %q not found", selector)
+	}
+	return template, nil
+}
+
 func (w *thresholdWorkflow) entityThresholds() map[string]any {
 	const baseSeverity = "normal"
 	return map[string]any{
@@ -1,13 +1,34 @@
 package models
 
 import (
+	"net/http"
+
 	"github.com/tivo/terraform-provider-splunk-itsi/util"
 )
 
 var clients IHttpClients
 
-var itsiLimiter *util.Limiter
+var itsiLimiter util.ConcurrencyLimiter
 
 func InitItsiApiLimiter(concurrency int) {
 	itsiLimiter = util.NewLimiter(concurrency)
 }
+
+// InitItsiApiAdaptiveLimiter bounds the concurrency of ITSI API requests with a limit adapting to ITSI throttling.
+func InitItsiApiAdaptiveLimiter(minConcurrency, maxConcurrency int) {
+	itsiLimiter = util.NewAdaptiveLimiter(minConcurrency, maxConcurrency)
+}
+
+// itsiApiLimiter returns the limiter of the ITSI API requests of the client config.
+func (c ClientConfig) itsiApiLimiter() util.ConcurrencyLimiter {
+	if c.ItsiLimiter != nil {
+		return c.ItsiLimiter
+	}
+	return itsiLimiter
+}
+
+// Throttled returns whether a response status code indicates that Splunk is overloaded.
+// Request errors, e.g. client-side timeouts, are not counted as throttling.
+func Throttled(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
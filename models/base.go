@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/lestrrat-go/backoff/v2"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
 )
 
 type Base struct {
@@ -23,17 +24,92 @@ func (b *Base) requestWithRetry(ctx context.Context, method string, url string,
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	bo := b.Splunk.RetryPolicy.Start(ctx)
+	// credentials retrieved from a credential source may change between requests
+	client, requestErr := b.Splunk.WithCredentials(ctx)
+	if requestErr != nil {
+		tflog.Error(ctx, fmt.Sprintf("%v %v failed: %s", method, url, requestErr.Error()))
+		return
+	}
+	// ITOA requests stick to one search head member per run, to avoid KV store replication races
+	client = client.StickyEndpoint(ctx)
+
+	reauthenticated := false
+	var endpointURL string
+	var loginErr error
+	sendOnce := func() (statusCode int, responseBody []byte, retryAfter time.Duration, err error) {
+		endpointURL = client.EndpointURL(url)
+		authenticated := &Base{Splunk: client, RetryFunc: b.RetryFunc}
+		loginErr = nil
+		var sessionKey string
+		if client.UsesSessionAuth() {
+			if sessionKey, loginErr = SessionKey(ctx, client); loginErr != nil {
+				return LoginStatusCode(loginErr), nil, 0, loginErr
+			}
+		}
+		statusCode, responseBody, retryAfter, err = authenticated.request(ctx, method, endpointURL, body, sessionKey)
+		if statusCode == http.StatusUnauthorized && sessionKey != "" && !reauthenticated {
+			// the session key has expired or has been revoked: log in again and repeat the request
+			reauthenticated = true
+			if sessionKey, loginErr = RenewSessionKey(ctx, client, sessionKey); loginErr != nil {
+				return LoginStatusCode(loginErr), nil, 0, loginErr
+			}
+			statusCode, responseBody, retryAfter, err = authenticated.request(ctx, method, endpointURL, body, sessionKey)
+		}
+		return
+	}
+	credentialsRefreshed := false
+	send := func() (statusCode int, responseBody []byte, retryAfter time.Duration, err error) {
+		statusCode, responseBody, retryAfter, err = sendOnce()
+		if statusCode == http.StatusUnauthorized && !credentialsRefreshed && client.InvalidateCredentials() {
+			// the credentials cached by the credential source may have been rotated: retrieve them again and repeat the request
+			credentialsRefreshed = true
+			refreshed, refreshErr := b.Splunk.WithCredentials(ctx)
+			if refreshErr != nil {
+				return statusCode, responseBody, retryAfter, refreshErr
+			}
+			client.BearerToken, client.User, client.Password = refreshed.BearerToken, refreshed.User, refreshed.Password
+			statusCode, responseBody, retryAfter, err = sendOnce()
+		}
+		return
+	}
+
+	// retries stop after the max elapsed time, without interrupting the request in flight
+	retryCtx := ctx
+	if b.Splunk.RetryMaxElapsedTime > 0 {
+		var cancelRetries context.CancelFunc
+		retryCtx, cancelRetries = context.WithTimeout(ctx, b.Splunk.RetryMaxElapsedTime)
+		defer cancelRetries()
+	}
+	bo := b.Splunk.RetryPolicy.Start(retryCtx)
 
 	attempt := 1
+	var retryAfter time.Duration
 
 	for backoff.Continue(bo) {
-		start := time.Now()
-		statusCode, responseBody, requestErr = b.request(ctx, method, url, body)
-		tflog.Trace(ctx, fmt.Sprintf("%v %v (%v): %v %v [%s]", method, url, attempt, statusCode, http.StatusText(statusCode), time.Since(start).String()))
+		for failovers := 0; ; failovers++ {
+			start := time.Now()
+			statusCode, responseBody, retryAfter, requestErr = send()
+			if loginErr != nil {
+				// a failed login is handled like a failed request: the member is failed over or the login is retried
+				tflog.Warn(ctx, fmt.Sprintf("%v %v (%v): %s", method, endpointURL, attempt, loginErr.Error()))
+			} else {
+				tflog.Trace(ctx, fmt.Sprintf("%v %v (%v): %v %v [%s]", method, endpointURL, attempt, statusCode, http.StatusText(statusCode), time.Since(start).String()))
+			}
+			if !EndpointFailed(statusCode, requestErr) || !client.MarkEndpointDown(ctx, requestErr) || failovers >= len(client.Hosts)-1 {
+				break
+			}
+			// another search head member is available: fail over to it right away, without waiting for the retry backoff
+			client = client.StickyEndpoint(ctx)
+			attempt++
+		}
 		if requestErr != nil {
+			retryFunc := b.RetryFunc
+			if loginErr != nil {
+				// the object specific error handling only applies to the responses of the request itself
+				retryFunc = b.retryLogin
+			}
 
-			if shouldRetry, newStatus, newBody, err := b.RetryFunc(ctx, method, statusCode, responseBody, requestErr); !shouldRetry {
+			if shouldRetry, newStatus, newBody, err := retryFunc(ctx, method, statusCode, responseBody, requestErr); !shouldRetry {
 				if err == nil {
 					statusCode = newStatus
 					responseBody = newBody
@@ -46,7 +122,16 @@ func (b *Base) requestWithRetry(ctx context.Context, method string, url string,
 				return
 			}
 
-			if ctx.Err() == nil {
+			if deadline, ok := retryCtx.Deadline(); ok && b.Splunk.RetryMaxElapsedTime > 0 && time.Now().Add(retryAfter).After(deadline) {
+				tflog.Error(ctx, fmt.Sprintf("%v %v (%v): giving up after %s", method, url, attempt, b.Splunk.RetryMaxElapsedTime))
+				break
+			}
+			if retryAfter > 0 {
+				// the server asked to wait before retrying
+				tflog.Trace(ctx, fmt.Sprintf("%v %v (%v): retrying after %s", method, url, attempt, retryAfter))
+				util.Sleep(retryCtx, retryAfter)
+			}
+			if ctx.Err() == nil && retryCtx.Err() == nil {
 				attempt++
 				continue
 			}
@@ -65,7 +150,12 @@ func (b *Base) requestWithRetry(ctx context.Context, method string, url string,
 	return
 }
 
-func (b *Base) request(ctx context.Context, method string, url string, body []byte) (statusCode int, responseBody []byte, err error) {
+// retryLogin returns whether a failed login should be retried. Logins failing with a transport error are always retried.
+func (b *Base) retryLogin(ctx context.Context, method string, statusCode int, responseBody []byte, requestErr error) (shouldRetry bool, newStatusCode int, newBody []byte, err error) {
+	return statusCode == 0 || b.Splunk.RetryableStatus(statusCode), statusCode, responseBody, requestErr
+}
+
+func (b *Base) request(ctx context.Context, method string, url string, body []byte, sessionKey string) (statusCode int, responseBody []byte, retryAfter time.Duration, err error) {
 	client := clients.Get(b.Splunk)
 	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
 	if err != nil {
@@ -80,18 +170,22 @@ func (b *Base) request(ctx context.Context, method string, url string, body []by
 
 	if b.Splunk.BearerToken != "" {
 		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", b.Splunk.BearerToken))
+	} else if sessionKey != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Splunk %s", sessionKey))
 	} else {
 		req.SetBasicAuth(b.Splunk.User, b.Splunk.Password)
 	}
 	req.Header.Add("Content-Type", "application/json")
 
-	itsiLimiter.Acquire()
-	defer itsiLimiter.Release()
+	limiter := b.Splunk.itsiApiLimiter()
+	limiter.Acquire()
+	defer limiter.Release()
 
 	resp, err := client.Do(req)
 	if resp != nil {
 		statusCode = resp.StatusCode
 	}
+	limiter.Feedback(Throttled(statusCode))
 	if err != nil {
 		return
 	}
@@ -103,7 +197,7 @@ func (b *Base) request(ctx context.Context, method string, url string, body []by
 	}
 	responseBody, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return resp.StatusCode, nil, fmt.Errorf("%v error: %v", method, resp.Status)
+		return resp.StatusCode, nil, 0, fmt.Errorf("%v error: %v", method, resp.Status)
 	}
 
 	success := false
@@ -126,7 +220,7 @@ func (b *Base) request(ctx context.Context, method string, url string, body []by
 	}
 
 	if !success {
-		return resp.StatusCode, nil, fmt.Errorf("%v error: %v \n%s", method, resp.Status, responseBody)
+		return resp.StatusCode, nil, util.RetryAfter(resp.Header.Get("Retry-After"), time.Now()), fmt.Errorf("%v error: %v \n%s", method, resp.Status, responseBody)
 	}
 
 	return
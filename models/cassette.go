@@ -0,0 +1,360 @@
+//go:build !test_setup
+// +build !test_setup
+
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+/*
+	HTTP cassettes record the Splunk/ITSI API interactions to a file, and replay them instead of accessing the network,
+	so that acceptance tests can run without a live ITSI instance:
+
+		ITSI_HTTP_CASSETTE_MODE=record|replay
+		ITSI_HTTP_CASSETTE=path of the cassette file (default: testdata/http_cassette.jsonl)
+
+	Cassettes are sanitized when recorded: credentials, cookies and session keys are not written.
+	Recorded requests are matched by method, path, query and body, regardless of the host. Identical requests are
+	replayed in the order they were recorded, the last response being repeated once they are exhausted.
+
+	Some values of the requests differ between runs: the keys generated by the provider (UUIDs), and the _tf_hash of
+	the objects, which includes those keys. A value that is new, i.e. found in none of the earlier requests and responses,
+	is matched regardless of its value. It is then mapped to the value recorded in its place: later requests are matched
+	with the recorded value, and the recorded value is replaced with the actual one in the replayed responses.
+
+	A request without recorded interaction gets a 400 Bad Request response, so that it fails the test right away,
+	instead of being retried like a connection error.
+*/
+
+const (
+	envHttpCassetteMode = "ITSI_HTTP_CASSETTE_MODE"
+	envHttpCassette     = "ITSI_HTTP_CASSETTE"
+
+	defaultHttpCassette = "testdata/http_cassette.jsonl"
+
+	cassetteModeRecord = "record"
+	cassetteModeReplay = "replay"
+
+	redacted = "REDACTED"
+)
+
+var (
+	cassetteOnce sync.Once
+	cassette     *httpCassette
+	cassetteErr  error
+)
+
+var (
+	sessionKeyJSONRegexp = regexp.MustCompile(`("sessionKey"\s*:\s*")[^"]*(")`)
+	sessionKeyXMLRegexp  = regexp.MustCompile(`(<sessionKey>)[^<]*(</sessionKey>)`)
+	passwordFormRegexp   = regexp.MustCompile(`((?:^|&)password=)[^&]*`)
+
+	// volatileValueRegexp matches the values of requests that differ between runs: UUIDs and _tf_hash values
+	volatileValueRegexp = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|"` + resourceHashField + `"\s*:\s*"([0-9a-f]{64})"`)
+)
+
+type cassetteInteraction struct {
+	Method   string           `json:"method"`
+	URI      string           `json:"uri"`
+	Body     string           `json:"body,omitempty"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type httpCassette struct {
+	mode string
+	path string
+
+	mu sync.Mutex
+	// record mode
+	file *os.File
+	// replay mode: recorded interactions of identical requests, and the number of them already replayed
+	interactions map[string][]recordedInteraction
+	replayed     map[string]int
+	// replay mode: volatile values of the replayed requests and responses, recorded value by actual value and vice versa
+	recordedValues, actualValues map[string]string
+}
+
+type recordedInteraction struct {
+	response cassetteResponse
+	// new volatile values of the request, see interactionKey
+	values []string
+}
+
+// cassetteTransport returns the transport recording to or replaying from the HTTP cassette configured in the environment,
+// or the base transport if there is none.
+func cassetteTransport(base http.RoundTripper) http.RoundTripper {
+	cassetteOnce.Do(func() {
+		cassette, cassetteErr = newHttpCassette(os.Getenv(envHttpCassetteMode), os.Getenv(envHttpCassette))
+	})
+	switch {
+	case cassetteErr != nil:
+		return errTransport{cassetteErr}
+	case cassette == nil:
+		return base
+	case cassette.mode == cassetteModeReplay:
+		return cassette
+	default:
+		return recordTransport{base, cassette}
+	}
+}
+
+func newHttpCassette(mode, path string) (c *httpCassette, err error) {
+	if mode == "" {
+		return nil, nil
+	}
+	if path == "" {
+		path = defaultHttpCassette
+	}
+	c = &httpCassette{mode: mode, path: path}
+
+	switch mode {
+	case cassetteModeRecord:
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			c.file, err = os.Create(path)
+		}
+	case cassetteModeReplay:
+		err = c.load()
+	default:
+		err = fmt.Errorf("invalid %s %q: must be %s or %s", envHttpCassetteMode, mode, cassetteModeRecord, cassetteModeReplay)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("http cassette %s: %w", path, err)
+	}
+	return
+}
+
+func (c *httpCassette) load() error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	c.interactions, c.replayed = map[string][]recordedInteraction{}, map[string]int{}
+	c.recordedValues, c.actualValues = map[string]string{}, map[string]string{}
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var i cassetteInteraction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		key, values := interactionKey(i.Method, i.URI, i.Body, func(v string) (string, bool) { return v, seen[v] })
+		c.interactions[key] = append(c.interactions[key], recordedInteraction{i.Response, values})
+		for _, s := range []string{i.URI, i.Body, i.Response.Body} {
+			replaceVolatileValues(s, func(v string) string {
+				seen[v] = true
+				return v
+			})
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *httpCassette) record(i cassetteInteraction) error {
+	line, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.file.Write(append(line, '\n'))
+	return err
+}
+
+// RoundTrip replays the recorded response of the request.
+func (c *httpCassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request
+	req = req.Clone(req.Context())
+	uri, body, err := sanitizedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, values := interactionKey(req.Method, uri, body, func(v string) (string, bool) {
+		recorded, ok := c.recordedValues[v]
+		return recorded, ok
+	})
+	interactions := c.interactions[key]
+	if len(interactions) == 0 {
+		msg := fmt.Sprintf("http cassette %s: no recorded response for %s %s", c.path, req.Method, uri)
+		tflog.Error(req.Context(), msg, map[string]any{"body": body})
+		return cassetteHttpResponse(req, http.StatusBadRequest, http.Header{"Content-Type": {"text/plain"}}, msg), nil
+	}
+	n := c.replayed[key]
+	c.replayed[key]++
+	i := interactions[min(n, len(interactions)-1)]
+
+	// the new values of the request take the place of the recorded ones
+	for j, v := range values {
+		c.recordedValues[v], c.actualValues[i.values[j]] = i.values[j], v
+	}
+	actual := func(recorded string) string {
+		v, ok := c.actualValues[recorded]
+		if !ok {
+			// a value generated by the server is the same in the cassette and the replay
+			v = recorded
+			c.recordedValues[v], c.actualValues[v] = v, v
+		}
+		return v
+	}
+
+	header := i.response.Header.Clone()
+	for _, vs := range header {
+		for j, v := range vs {
+			vs[j] = replaceVolatileValues(v, actual)
+		}
+	}
+	return cassetteHttpResponse(req, i.response.StatusCode, header, replaceVolatileValues(i.response.Body, actual)), nil
+}
+
+func cassetteHttpResponse(req *http.Request, statusCode int, header http.Header, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+type recordTransport struct {
+	base     http.RoundTripper
+	cassette *httpCassette
+}
+
+func (t recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request
+	req = req.Clone(req.Context())
+	uri, body, err := sanitizedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		// connection errors are not recorded
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	if err = t.cassette.record(cassetteInteraction{
+		Method: req.Method,
+		URI:    uri,
+		Body:   body,
+		Response: cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       sanitizeResponseBody(string(respBody)),
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// sanitizedRequest returns the URI and the body of the request, without credentials.
+// The body of the request is replaced with a copy, so that the request can still be sent.
+func sanitizedRequest(req *http.Request) (uri string, body string, err error) {
+	u := *req.URL
+	u.Scheme, u.Host, u.User = "", "", nil
+	uri = u.RequestURI()
+
+	if req.Body != nil && req.Body != http.NoBody {
+		var b []byte
+		if b, err = io.ReadAll(req.Body); err != nil {
+			return
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		body = string(b)
+	}
+
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") || strings.HasSuffix(u.Path, "/auth/login") {
+		body = passwordFormRegexp.ReplaceAllString(body, "${1}"+url.QueryEscape(redacted))
+	}
+	return
+}
+
+func sanitizeResponseBody(body string) string {
+	body = sessionKeyJSONRegexp.ReplaceAllString(body, "${1}"+redacted+"${2}")
+	return sessionKeyXMLRegexp.ReplaceAllString(body, "${1}"+redacted+"${2}")
+}
+
+// interactionKey returns the key matching a request to the recorded interactions, and the new volatile values of the
+// request, in order of appearance. New values are replaced with placeholders numbered by their order, while the values
+// known from earlier requests and responses are replaced with their recorded value.
+func interactionKey(method, uri, body string, known func(string) (string, bool)) (key string, values []string) {
+	placeholders := map[string]string{}
+	translate := func(v string) string {
+		if recorded, ok := known(v); ok {
+			return recorded
+		}
+		if _, ok := placeholders[v]; !ok {
+			values = append(values, v)
+			placeholders[v] = fmt.Sprintf("{{volatile_%d}}", len(values))
+		}
+		return placeholders[v]
+	}
+	uri = replaceVolatileValues(uri, translate)
+	body = replaceVolatileValues(body, translate)
+	return fmt.Sprintf("%s %s\n%s", method, uri, body), values
+}
+
+// replaceVolatileValues replaces the volatile values of s, in order of appearance.
+func replaceVolatileValues(s string, replace func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range volatileValueRegexp.FindAllStringSubmatchIndex(s, -1) {
+		start, end := m[0], m[1]
+		if m[2] >= 0 {
+			// the hash of a _tf_hash field
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(replace(s[start:end]))
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
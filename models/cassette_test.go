@@ -0,0 +1,297 @@
+//go:build !test_setup
+// +build !test_setup
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/backoff/v2"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+// cassetteClients sends the requests of every client config with the same HTTP client.
+type cassetteClients struct {
+	client *http.Client
+}
+
+func (c cassetteClients) Get(ClientConfig) IHttpClient {
+	return c.client
+}
+
+func useCassetteTransport(t *testing.T, transport http.RoundTripper) {
+	clients_, cache := clients, Cache
+	clients, Cache = cassetteClients{&http.Client{Transport: transport}}, NewCache(10)
+	t.Cleanup(func() { clients, Cache = clients_, cache })
+}
+
+func writeCassette(t *testing.T, interactions ...cassetteInteraction) string {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	var b strings.Builder
+	for _, i := range interactions {
+		line, err := json.Marshal(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(line)
+		b.WriteString("\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func replayCassette(t *testing.T, path string) *httpCassette {
+	c, err := newHttpCassette(cassetteModeReplay, path)
+	if err != nil {
+		t.Fatalf("failed to load the cassette: %v", err)
+	}
+	return c
+}
+
+func roundTrip(t *testing.T, transport http.RoundTripper, method, uri, body string) (int, string) {
+	req, err := http.NewRequest(method, "https://itsi.replay.invalid:8089"+uri, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, uri, err)
+	}
+	defer resp.Body.Close()
+	by, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(by)
+}
+
+func TestCassetteRecordRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "splunkd_8089", Value: "cookie"})
+		if strings.HasSuffix(r.URL.Path, "/auth/login") {
+			fmt.Fprint(w, `{"sessionKey": "secret-session-key"}`)
+			return
+		}
+		fmt.Fprint(w, `<response><sessionKey>secret-session-key</sessionKey></response>`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	c, err := newHttpCassette(cassetteModeRecord, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := recordTransport{http.DefaultTransport, c}
+
+	for _, r := range []struct{ uri, contentType, body string }{
+		{"/services/auth/login?output_mode=json", "application/x-www-form-urlencoded", "username=admin&password=secret-password"},
+		{"/services/auth/login", "text/plain", "password=secret-password&username=admin"},
+		{"/services/search/jobs", "application/x-www-form-urlencoded", "search=search+index%3D_internal&password=secret-password"},
+	} {
+		req, err := http.NewRequest(http.MethodPost, server.URL+r.uri, strings.NewReader(r.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", r.contentType)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("%s: %v", r.uri, err)
+		}
+		by, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(by), "secret-session-key") || len(resp.Cookies()) != 1 {
+			t.Errorf("%s: expected the caller to get the actual response, got %s", r.uri, by)
+		}
+	}
+	c.file.Close()
+
+	recorded, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-password", "secret-session-key", "cookie", "Set-Cookie"} {
+		if strings.Contains(string(recorded), secret) {
+			t.Errorf("expected %s not to be recorded, got:\n%s", secret, recorded)
+		}
+	}
+	var requests, responses []string
+	for _, line := range strings.Split(strings.TrimSpace(string(recorded)), "\n") {
+		var i cassetteInteraction
+		if err := json.Unmarshal([]byte(line), &i); err != nil {
+			t.Fatal(err)
+		}
+		requests, responses = append(requests, i.Body), append(responses, i.Response.Body)
+	}
+	expectedRequests := []string{"username=admin&password=REDACTED", "password=REDACTED&username=admin", "search=search+index%3D_internal&password=REDACTED"}
+	expectedResponses := []string{`{"sessionKey": "REDACTED"}`, `{"sessionKey": "REDACTED"}`, `<response><sessionKey>REDACTED</sessionKey></response>`}
+	if !slices.Equal(requests, expectedRequests) || !slices.Equal(responses, expectedResponses) {
+		t.Errorf("expected the requests %q and responses %q to be recorded, got %q and %q", expectedRequests, expectedResponses, requests, responses)
+	}
+}
+
+func TestCassetteReplayMatching(t *testing.T) {
+	const search = "/servicesNS/nobody/SA-ITOA/itoa_interface/entity?filter=%7B%22title%22%3A%22a%22%7D"
+	c := replayCassette(t, writeCassette(t,
+		cassetteInteraction{Method: http.MethodGet, URI: search, Response: cassetteResponse{StatusCode: 200, Body: `[]`}},
+		cassetteInteraction{Method: http.MethodGet, URI: search, Response: cassetteResponse{StatusCode: 200, Body: `[{"title":"a"}]`}},
+		cassetteInteraction{Method: http.MethodPost, URI: "/entity", Body: `{"title":"a"}`, Response: cassetteResponse{StatusCode: 200, Body: `{"_key":"a"}`}},
+	))
+
+	// identical requests are replayed in order, the last response being repeated
+	for _, expected := range []string{`[]`, `[{"title":"a"}]`, `[{"title":"a"}]`} {
+		if status, body := roundTrip(t, c, http.MethodGet, search, ""); status != 200 || body != expected {
+			t.Errorf("expected %s, got %d %s", expected, status, body)
+		}
+	}
+
+	if status, body := roundTrip(t, c, http.MethodPost, "/entity", `{"title":"a"}`); status != 200 || body != `{"_key":"a"}` {
+		t.Errorf("expected the recorded response, got %d %s", status, body)
+	}
+
+	// requests are not matched by URI only
+	for _, r := range []struct{ method, uri, body string }{
+		{http.MethodPost, "/entity", `{"title":"b"}`},
+		{http.MethodPut, "/entity", `{"title":"a"}`},
+		{http.MethodGet, "/entity", ""},
+	} {
+		status, body := roundTrip(t, c, r.method, r.uri, r.body)
+		if expected := fmt.Sprintf("no recorded response for %s %s", r.method, r.uri); status != http.StatusBadRequest || !strings.Contains(body, expected) {
+			t.Errorf("expected a 400 Bad Request response for an unrecorded request, got %d %s", status, body)
+		}
+	}
+}
+
+func TestCassetteReplayVolatileValues(t *testing.T) {
+	const (
+		recordedKey  = "5b7e1a3c-7f2d-4c1e-9a8b-0d6e2f4a1b3c"
+		serverKey    = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+		recordedHash = "1111111111111111111111111111111111111111111111111111111111111111"
+	)
+	c := replayCassette(t, writeCassette(t,
+		cassetteInteraction{
+			Method:   http.MethodPost,
+			URI:      "/entity",
+			Body:     `{"_key":"` + recordedKey + `","_tf_hash":"` + recordedHash + `","title":"a"}`,
+			Response: cassetteResponse{StatusCode: 200, Header: http.Header{"Location": {"/entity/" + recordedKey}}, Body: `{"_key":"` + recordedKey + `"}`},
+		},
+		cassetteInteraction{
+			Method:   http.MethodGet,
+			URI:      "/entity/" + recordedKey,
+			Response: cassetteResponse{StatusCode: 200, Body: `{"_key":"` + recordedKey + `","_tf_hash":"` + recordedHash + `","parent":"` + serverKey + `"}`},
+		},
+		cassetteInteraction{
+			Method:   http.MethodGet,
+			URI:      "/entity/" + serverKey,
+			Response: cassetteResponse{StatusCode: 200, Body: `{"_key":"` + serverKey + `"}`},
+		},
+	))
+
+	// the key generated by the client and the hash of the body differ from the recorded ones
+	key, hash := uuid.NewString(), util.Sha256([]byte("a"))
+	req, _ := http.NewRequest(http.MethodPost, "https://itsi.replay.invalid:8089/entity", strings.NewReader(`{"_key":"`+key+`","_tf_hash":"`+hash+`","title":"a"}`))
+	resp, err := c.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	by, _ := io.ReadAll(resp.Body)
+	if expected := `{"_key":"` + key + `"}`; resp.StatusCode != 200 || string(by) != expected {
+		t.Errorf("expected %s, got %d %s", expected, resp.StatusCode, by)
+	}
+	if location := resp.Header.Get("Location"); location != "/entity/"+key {
+		t.Errorf("expected the recorded key to be replaced in the headers, got %s", location)
+	}
+
+	// the actual key is then matched with the recorded one, and the values known from the responses are matched literally
+	if status, body := roundTrip(t, c, http.MethodGet, "/entity/"+key, ""); status != 200 || body != `{"_key":"`+key+`","_tf_hash":"`+hash+`","parent":"`+serverKey+`"}` {
+		t.Errorf("expected the actual key and hash in the replayed response, got %d %s", status, body)
+	}
+	if status, body := roundTrip(t, c, http.MethodGet, "/entity/"+serverKey, ""); status != 200 || body != `{"_key":"`+serverKey+`"}` {
+		t.Errorf("expected the key generated by the server to be matched, got %d %s", status, body)
+	}
+
+	// a known value is not matched in place of another one
+	if status, _ := roundTrip(t, c, http.MethodGet, "/entity/"+uuid.NewString(), ""); status != http.StatusBadRequest {
+		t.Errorf("expected a 400 Bad Request response for an unrecorded key, got %d", status)
+	}
+}
+
+func TestInteractionKey(t *testing.T) {
+	a, b := "5b7e1a3c-7f2d-4c1e-9a8b-0d6e2f4a1b3c", "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	hash := util.Sha256([]byte("a"))
+	known := func(v string) (string, bool) { return strings.ToUpper(v), v == b }
+
+	key, values := interactionKey(http.MethodPut, "/entity/"+a, `{"_key":"`+a+`","parent":"`+b+`","_tf_hash": "`+hash+`","sha":"`+hash+`"}`, known)
+	expected := "PUT /entity/{{volatile_1}}\n" + `{"_key":"{{volatile_1}}","parent":"` + strings.ToUpper(b) + `","_tf_hash": "{{volatile_2}}","sha":"` + hash + `"}`
+	if key != expected {
+		t.Errorf("expected key:\n%s\ngot:\n%s", expected, key)
+	}
+	if len(values) != 2 || values[0] != a || values[1] != hash {
+		t.Errorf("expected the new values %s and %s, got %v", a, hash, values)
+	}
+}
+
+// TestCassetteReplayItsiObj replays the lifecycle of an entity, with another generated key than the recorded one.
+func TestCassetteReplayItsiObj(t *testing.T) {
+	useCassetteTransport(t, replayCassette(t, "testdata/entity_lifecycle.jsonl"))
+	testEntityLifecycle(t, ClientConfig{Host: "itsi.replay.invalid", Port: 8089, BearerToken: "replay"})
+}
+
+func testEntityLifecycle(t *testing.T, config ClientConfig) {
+	ctx := context.Background()
+	config.RetryPolicy = backoff.Null()
+	config.ItsiLimiter = util.NewLimiter(1)
+
+	entity := func(key, description string) *ItsiObj {
+		obj := NewItsiObj(config, key, "cassette-entity", "entity")
+		if err := obj.PopulateRawJSON(ctx, map[string]any{
+			"title":         "cassette-entity",
+			"description":   description,
+			"object_type":   "entity",
+			"identifier":    map[string]any{"fields": []string{"host"}, "values": []string{"cassette-host"}},
+			"informational": map[string]any{"fields": []string{}, "values": []string{}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return obj
+	}
+
+	created, err := entity("", "created").Create(ctx)
+	if err != nil {
+		t.Fatalf("failed to create the entity: %v", err)
+	}
+	key := created.RESTKey
+
+	read, err := NewItsiObj(config, key, "", "entity").Read(ctx)
+	if err != nil || read == nil {
+		t.Fatalf("failed to read the entity: %v", err)
+	}
+	m, err := read.RawJson.ToInterfaceMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["_key"] != key || m[resourceHashField] != created.Hash || m["description"] != "created" {
+		t.Errorf("unexpected entity: %v", m)
+	}
+
+	// the update is confirmed by the _tf_hash read back from ITSI
+	if diags := entity(key, "updated").UpdateAsync(ctx); len(diags) > 0 {
+		t.Fatalf("failed to update the entity: %v", diags)
+	}
+
+	if diags := NewItsiObj(config, key, "", "entity").Delete(ctx); diags.HasError() {
+		t.Fatalf("failed to delete the entity: %v", diags)
+	}
+}
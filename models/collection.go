@@ -198,13 +198,8 @@ func (c *CollectionApi) body() (body []byte, err error) {
 
 func (c *CollectionApi) handleRequestError(ctx context.Context, method string, statusCode int, responseBody []byte, requestErr error) (shouldRetry bool, newStatusCode int, newBody []byte, err error) {
 	newStatusCode, newBody, err = statusCode, responseBody, requestErr
-
-	switch {
-	case statusCode == 400 || statusCode == 401 || statusCode == 403 || statusCode == 404 || statusCode == 409: //do not retry
-	default:
-		shouldRetry = true
-	}
-
+	// transport errors have no status code and are always retried
+	shouldRetry = statusCode == 0 || c.Splunk.RetryableStatus(statusCode)
 	return
 }
 
@@ -0,0 +1,282 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+/*
+	With several search head cluster members configured in ClientConfig.Hosts, requests are sent to a healthy member,
+	and members are marked down for a while on connection errors and 5xx responses.
+
+	ITOA requests stick to one member until it fails, so that objects written during an apply are read back
+	from the member they were written to, rather than from a member the KV store has not replicated them to yet.
+	Searches are spread across the healthy members.
+
+	A member that has been marked down is probed before it is used again.
+*/
+
+const (
+	endpointDownTime     = 30 * time.Second
+	endpointProbeTimeout = 5 * time.Second
+)
+
+var endpointPools = &endpointRegistry{pools: map[string]*endpointPool{}}
+
+type endpointRegistry struct {
+	mu    sync.Mutex
+	pools map[string]*endpointPool
+}
+
+type endpoint struct {
+	host      string
+	port      int
+	downUntil time.Time
+}
+
+func (e *endpoint) String() string {
+	return net.JoinHostPort(e.host, strconv.Itoa(e.port))
+}
+
+type endpointPool struct {
+	mu      sync.Mutex
+	members []*endpoint
+	// index of the member ITOA requests are sent to
+	current int
+	// index of the member the next search is sent to
+	next int
+}
+
+// HasFailover returns whether the client config has a list of search head members to fail over between.
+func (c ClientConfig) HasFailover() bool {
+	return len(c.Hosts) > 0
+}
+
+// PrimaryHost returns the host of the first search head member, without its port.
+func PrimaryHost(hosts []string) string {
+	if len(hosts) == 0 {
+		return ""
+	}
+	h := strings.TrimSpace(hosts[0])
+	if host, _, err := net.SplitHostPort(h); err == nil {
+		return host
+	}
+	return h
+}
+
+// StickyEndpoint returns a copy of the client config targeting the member ITOA requests are currently sent to.
+func (c ClientConfig) StickyEndpoint(ctx context.Context) ClientConfig {
+	if !c.HasFailover() {
+		return c
+	}
+	return c.withEndpoint(endpointPools.get(c).sticky(ctx, c))
+}
+
+// AnyEndpoint returns a copy of the client config targeting a healthy member, rotating between the members.
+func (c ClientConfig) AnyEndpoint(ctx context.Context) ClientConfig {
+	if !c.HasFailover() {
+		return c
+	}
+	return c.withEndpoint(endpointPools.get(c).any(ctx, c))
+}
+
+// MarkEndpointDown marks the member targeted by the client config as down, so that it is not used for a while.
+// It returns whether another member is available to fail over to.
+func (c ClientConfig) MarkEndpointDown(ctx context.Context, reason error) bool {
+	if !c.HasFailover() {
+		return false
+	}
+	return endpointPools.get(c).markDown(ctx, c.Host, c.Port, reason)
+}
+
+// EndpointFailed returns whether a response status code or request error indicates that the member is unavailable.
+func EndpointFailed(statusCode int, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return statusCode >= 500 || (statusCode == 0 && err != nil)
+}
+
+// EndpointURL returns the URL with its host replaced by the member targeted by the client config.
+func (c ClientConfig) EndpointURL(rawURL string) string {
+	if !c.HasFailover() {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Host = net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	return u.String()
+}
+
+func (c ClientConfig) withEndpoint(e endpoint) ClientConfig {
+	c.Host, c.Port = e.host, e.port
+	return c
+}
+
+func (r *endpointRegistry) get(config ClientConfig) *endpointPool {
+	members := make([]*endpoint, 0, len(config.Hosts))
+	addresses := make([]string, 0, len(config.Hosts))
+	for _, h := range config.Hosts {
+		h = strings.TrimSpace(h)
+		e := &endpoint{host: h, port: config.Port}
+		if host, port, err := net.SplitHostPort(h); err == nil {
+			if p, err := strconv.Atoi(port); err == nil {
+				e.host, e.port = host, p
+			}
+		}
+		members = append(members, e)
+		addresses = append(addresses, e.String())
+	}
+	key := strings.Join(addresses, ",")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pool, ok := r.pools[key]
+	if !ok {
+		pool = &endpointPool{members: members}
+		r.pools[key] = pool
+	}
+	return pool
+}
+
+func (p *endpointPool) sticky(ctx context.Context, config ClientConfig) endpoint {
+	p.mu.Lock()
+	current := p.current
+	p.mu.Unlock()
+
+	i := p.healthy(ctx, config, current)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if i != p.current {
+		tflog.Warn(ctx, fmt.Sprintf("failing over ITOA requests from %s to %s", p.members[p.current], p.members[i]))
+		p.current = i
+	}
+	return *p.members[i]
+}
+
+func (p *endpointPool) any(ctx context.Context, config ClientConfig) endpoint {
+	p.mu.Lock()
+	next := p.next
+	p.next = (next + 1) % len(p.members)
+	p.mu.Unlock()
+
+	i := p.healthy(ctx, config, next)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if i != next {
+		p.next = (i + 1) % len(p.members)
+	}
+	return *p.members[i]
+}
+
+func (p *endpointPool) markDown(ctx context.Context, host string, port int, reason error) (failover bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for _, e := range p.members {
+		if e.host == host && e.port == port {
+			tflog.Warn(ctx, fmt.Sprintf("marking %s down for %s: %v", e, endpointDownTime, reason))
+			e.downUntil = now.Add(endpointDownTime)
+		} else if !now.Before(e.downUntil) {
+			failover = true
+		}
+	}
+	return
+}
+
+// healthy returns the index of the first healthy member, starting from the given one.
+// Members whose down time has elapsed are probed first; if no member is healthy, the starting one is returned.
+// The members are probed without holding p.mu, so that requests to healthy members are not held up by a probe.
+func (p *endpointPool) healthy(ctx context.Context, config ClientConfig, start int) int {
+	type candidate struct {
+		index    int
+		endpoint endpoint
+	}
+
+	// the members to probe, in order, up to the first member that is up
+	p.mu.Lock()
+	candidates := []candidate{}
+	up := -1
+	now := time.Now()
+	for n := range p.members {
+		i := (start + n) % len(p.members)
+		e := p.members[i]
+		if e.downUntil.IsZero() {
+			up = i
+			break
+		}
+		if now.Before(e.downUntil) {
+			continue
+		}
+		// other requests skip the member until its probe completes
+		e.downUntil = now.Add(endpointProbeTimeout)
+		candidates = append(candidates, candidate{i, *e})
+	}
+	p.mu.Unlock()
+
+	healthy := -1
+	for _, c := range candidates {
+		if healthy >= 0 {
+			// the member is probed by the next request instead
+			p.setDownUntil(c.index, c.endpoint.downUntil)
+			continue
+		}
+		if err := probe(ctx, config.withEndpoint(c.endpoint)); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("%s is still down: %v", &c.endpoint, err))
+			p.setDownUntil(c.index, time.Now().Add(endpointDownTime))
+			continue
+		}
+		tflog.Info(ctx, fmt.Sprintf("%s is up again", &c.endpoint))
+		p.setDownUntil(c.index, time.Time{})
+		healthy = c.index
+	}
+
+	switch {
+	case healthy >= 0:
+		return healthy
+	case up >= 0:
+		return up
+	default:
+		return start
+	}
+}
+
+func (p *endpointPool) setDownUntil(i int, downUntil time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.members[i].downUntil = downUntil
+}
+
+// probe checks that the member targeted by the client config responds; authentication is not required.
+func probe(ctx context.Context, config ClientConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, endpointProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/services/server/info", net.JoinHostPort(config.Host, strconv.Itoa(config.Port))), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := clients.Get(config).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
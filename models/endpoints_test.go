@@ -0,0 +1,219 @@
+//go:build !test_setup
+// +build !test_setup
+
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testMember is a search head member answering the health probes with its status.
+type testMember struct {
+	*httptest.Server
+	status atomic.Int32
+	probes atomic.Int32
+}
+
+func newTestMember(t *testing.T) *testMember {
+	m := &testMember{}
+	m.status.Store(http.StatusOK)
+	m.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/server/info" {
+			m.probes.Add(1)
+		}
+		w.WriteHeader(int(m.status.Load()))
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *testMember) address() string {
+	u, _ := url.Parse(m.URL)
+	return u.Host
+}
+
+func testEndpointConfig(members ...*testMember) ClientConfig {
+	config := ClientConfig{SkipTLS: true, Port: 8089}
+	for _, m := range members {
+		config.Hosts = append(config.Hosts, m.address())
+	}
+	return config
+}
+
+func endpointAddress(c ClientConfig) string {
+	return (&endpoint{host: c.Host, port: c.Port}).String()
+}
+
+func TestEndpointPoolMarkDown(t *testing.T) {
+	ctx := context.Background()
+	a, b := newTestMember(t), newTestMember(t)
+	config := testEndpointConfig(a, b)
+
+	if got := endpointAddress(config.StickyEndpoint(ctx)); got != a.address() {
+		t.Fatalf("expected ITOA requests to be sent to the first member %s, got %s", a.address(), got)
+	}
+
+	if !config.StickyEndpoint(ctx).MarkEndpointDown(ctx, errors.New("503 Service Unavailable")) {
+		t.Fatal("expected another member to fail over to")
+	}
+	for range 3 {
+		if got := endpointAddress(config.StickyEndpoint(ctx)); got != b.address() {
+			t.Errorf("expected ITOA requests to fail over to %s, got %s", b.address(), got)
+		}
+		if got := endpointAddress(config.AnyEndpoint(ctx)); got != b.address() {
+			t.Errorf("expected searches to skip the member marked down, got %s", got)
+		}
+	}
+	if a.probes.Load() != 0 {
+		t.Errorf("expected the member marked down not to be probed before its down time has elapsed, got %d probes", a.probes.Load())
+	}
+
+	// once its down time has elapsed, the member is probed and used again
+	pool := endpointPools.get(config)
+	pool.members[0].downUntil = time.Now().Add(-time.Second)
+	if got := endpointAddress(config.AnyEndpoint(ctx)); got != a.address() {
+		t.Errorf("expected searches to be sent to %s again, got %s", a.address(), got)
+	}
+	if a.probes.Load() != 1 || !pool.members[0].downUntil.IsZero() {
+		t.Errorf("expected the member to be probed once and marked up, got %d probes, down until %s", a.probes.Load(), pool.members[0].downUntil)
+	}
+
+	// searches are spread across the healthy members, while ITOA requests stick to the member they failed over to
+	if got := endpointAddress(config.AnyEndpoint(ctx)); got != b.address() {
+		t.Errorf("expected searches to rotate to %s, got %s", b.address(), got)
+	}
+	if got := endpointAddress(config.StickyEndpoint(ctx)); got != b.address() {
+		t.Errorf("expected ITOA requests to stick to %s, got %s", b.address(), got)
+	}
+}
+
+func TestEndpointPoolProbeInterval(t *testing.T) {
+	ctx := context.Background()
+	a, b := newTestMember(t), newTestMember(t)
+	config := testEndpointConfig(a, b)
+	pool := endpointPools.get(config)
+
+	a.status.Store(http.StatusServiceUnavailable)
+	pool.markDown(ctx, pool.members[0].host, pool.members[0].port, errors.New("503 Service Unavailable"))
+
+	for range 3 {
+		if i := pool.healthy(ctx, config, 0); i != 1 {
+			t.Errorf("expected the second member, got %d", i)
+		}
+	}
+	if a.probes.Load() != 0 {
+		t.Errorf("expected no probe within the down time, got %d", a.probes.Load())
+	}
+
+	// a failed probe marks the member down for another down time
+	pool.members[0].downUntil = time.Now().Add(-time.Second)
+	if i := pool.healthy(ctx, config, 0); i != 1 {
+		t.Errorf("expected the second member, got %d", i)
+	}
+	if a.probes.Load() != 1 {
+		t.Errorf("expected 1 probe, got %d", a.probes.Load())
+	}
+	if until := time.Until(pool.members[0].downUntil); until <= endpointDownTime-time.Second || until > endpointDownTime {
+		t.Errorf("expected the member to be marked down for %s after a failed probe, got %s", endpointDownTime, until)
+	}
+	pool.healthy(ctx, config, 0)
+	if a.probes.Load() != 1 {
+		t.Errorf("expected no probe within the down time, got %d", a.probes.Load())
+	}
+
+	a.status.Store(http.StatusOK)
+	pool.members[0].downUntil = time.Now().Add(-time.Second)
+	if i := pool.healthy(ctx, config, 0); i != 0 {
+		t.Errorf("expected the first member to be up again, got %d", i)
+	}
+	if a.probes.Load() != 2 {
+		t.Errorf("expected 2 probes, got %d", a.probes.Load())
+	}
+	if b.probes.Load() != 0 {
+		t.Errorf("expected the member that is up not to be probed, got %d probes", b.probes.Load())
+	}
+}
+
+func TestEndpointPoolAllDown(t *testing.T) {
+	ctx := context.Background()
+	a, b := newTestMember(t), newTestMember(t)
+	config := testEndpointConfig(a, b)
+	pool := endpointPools.get(config)
+
+	a.status.Store(http.StatusServiceUnavailable)
+	b.status.Store(http.StatusServiceUnavailable)
+
+	if !pool.markDown(ctx, pool.members[0].host, pool.members[0].port, errors.New("503")) {
+		t.Error("expected the second member to fail over to")
+	}
+	if pool.markDown(ctx, pool.members[1].host, pool.members[1].port, errors.New("503")) {
+		t.Error("expected no member to fail over to")
+	}
+
+	// with every member down, the starting member is used rather than failing the request
+	for start := range pool.members {
+		if i := pool.healthy(ctx, config, start); i != start {
+			t.Errorf("expected the starting member %d, got %d", start, i)
+		}
+	}
+
+	for _, e := range pool.members {
+		e.downUntil = time.Now().Add(-time.Second)
+	}
+	if i := pool.healthy(ctx, config, 1); i != 1 {
+		t.Errorf("expected the starting member, got %d", i)
+	}
+	if a.probes.Load() != 1 || b.probes.Load() != 1 {
+		t.Errorf("expected every member to be probed once, got %d and %d probes", a.probes.Load(), b.probes.Load())
+	}
+	for _, e := range pool.members {
+		if !time.Now().Before(e.downUntil) {
+			t.Errorf("expected %s to stay down", e)
+		}
+	}
+}
+
+func TestEndpointPoolProbeUnlocked(t *testing.T) {
+	ctx := context.Background()
+
+	probing, release := make(chan struct{}), make(chan struct{})
+	slow := &testMember{}
+	slow.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(probing)
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	b := newTestMember(t)
+
+	config := testEndpointConfig(slow, b)
+	pool := endpointPools.get(config)
+	pool.members[0].downUntil = time.Now().Add(-time.Second)
+
+	probed := make(chan int)
+	go func() { probed <- pool.healthy(ctx, config, 0) }()
+	<-probing
+
+	// requests are not held up by the probe in flight, and skip the member being probed
+	done := make(chan string)
+	go func() { done <- endpointAddress(config.AnyEndpoint(ctx)) }()
+	select {
+	case got := <-done:
+		if got != b.address() {
+			t.Errorf("expected the member being probed to be skipped, got %s", got)
+		}
+	case <-time.After(endpointProbeTimeout / 2):
+		t.Error("expected the endpoint to be selected while a member is probed")
+	}
+
+	close(release)
+	if i := <-probed; i != 0 {
+		t.Errorf("expected the probed member to be up again, got %d", i)
+	}
+}
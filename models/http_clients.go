@@ -4,9 +4,11 @@
 package models
 
 import (
-	"crypto/tls"
+	"fmt"
 	"net"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,7 +20,7 @@ func InitHttpClients() IHttpClients {
 }
 
 type HttpClients struct {
-	clientsByConfig map[ClientConfig]*http.Client
+	clientsByConfig map[httpClientKey]*http.Client
 	mu              sync.Mutex
 }
 
@@ -27,10 +29,11 @@ func (hc *HttpClients) Get(config ClientConfig) IHttpClient {
 	defer hc.mu.Unlock()
 
 	if hc.clientsByConfig == nil {
-		hc.clientsByConfig = make(map[ClientConfig]*http.Client)
+		hc.clientsByConfig = make(map[httpClientKey]*http.Client)
 	}
 
-	if client, ok := hc.clientsByConfig[config]; ok {
+	key := newHttpClientKey(config)
+	if client, ok := hc.clientsByConfig[key]; ok {
 		return client
 	}
 
@@ -45,9 +48,76 @@ func (hc *HttpClients) Get(config ClientConfig) IHttpClient {
 	}
 	tr.MaxIdleConnsPerHost = tr.MaxIdleConns
 
-	tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: (config.Host == "localhost" || config.SkipTLS)}
-	client := &http.Client{Transport: tr, Timeout: time.Duration(time.Duration(config.Timeout) * time.Second)}
-	hc.clientsByConfig[config] = client
+	var transport http.RoundTripper = tr
+	if len(config.ExtraHeaders) > 0 {
+		transport = headerTransport{tr, config.ExtraHeaders}
+	}
+
+	tlsConfig, err := config.TLSConfig()
+	if err == nil {
+		tr.TLSClientConfig = tlsConfig
+		tr.Proxy, err = config.ProxyFunc()
+	}
+	if err != nil {
+		// fail every request, instead of falling back to a configuration the user did not ask for
+		transport = errTransport{err}
+	}
+	// record or replay the interactions, if an HTTP cassette is configured (see cassette.go)
+	transport = cassetteTransport(transport)
+
+	client := &http.Client{Transport: transport, Timeout: time.Duration(time.Duration(config.Timeout) * time.Second)}
+	hc.clientsByConfig[key] = client
 
 	return client
 }
+
+type errTransport struct {
+	err error
+}
+
+func (t errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// httpClientKey identifies the client config settings that HTTP clients are built from.
+type httpClientKey struct {
+	skipTLS                       bool
+	concurrency, timeout          int
+	caCert, clientCert, clientKey string
+	proxyURL, noProxy             string
+	extraHeaders                  string
+}
+
+func newHttpClientKey(config ClientConfig) httpClientKey {
+	headers := []string{}
+	for k, v := range config.ExtraHeaders {
+		headers = append(headers, fmt.Sprintf("%s: %s", k, v))
+	}
+	slices.Sort(headers)
+
+	return httpClientKey{
+		skipTLS:      config.SkipTLS,
+		concurrency:  config.Concurrency,
+		timeout:      config.Timeout,
+		caCert:       config.CACert,
+		clientCert:   config.ClientCert,
+		clientKey:    config.ClientKey,
+		proxyURL:     config.ProxyURL,
+		noProxy:      config.NoProxy,
+		extraHeaders: strings.Join(headers, "\n"),
+	}
+}
@@ -151,7 +151,7 @@ func (obj *ItsiObj) handleConflictOnCreate(ctx context.Context) (responseBody []
 }
 
 func (obj *ItsiObj) handleRequestError(ctx context.Context, method string, statusCode int, responseBody []byte, requestErr error) (shouldRetry bool, newStatusCode int, newBody []byte, err error) {
-	//Common unretriable errors
+	//Common unretriable errors, unless retryable status codes are configured
 	//400: Bad Request
 	//401: Unauthorized
 	//403: Forbidden
@@ -167,9 +167,11 @@ func (obj *ItsiObj) handleRequestError(ctx context.Context, method string, statu
 		}
 	case method == http.MethodDelete && statusCode == http.StatusInternalServerError:
 		shouldRetry, err = obj.exists(ctx)
-	case statusCode == 400 || statusCode == 401 || statusCode == 403 || statusCode == 404 || statusCode == 409: //do not retry
-	default:
+	case statusCode == 0:
+		// transport errors, e.g. connection resets or client-side timeouts, have no status code and are always retried
 		shouldRetry = true
+	default:
+		shouldRetry = obj.Splunk.RetryableStatus(statusCode)
 	}
 
 	return
@@ -197,7 +199,8 @@ func (obj *ItsiObj) PopulateRawJSON(ctx context.Context, body map[string]any) er
 		body[obj.RestKeyField] = key
 		obj.RESTKey = key
 	}
-	//compute body hash
+	//compute body hash, regardless of the hash of a body read from ITSI
+	delete(body, resourceHashField)
 	by, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -578,17 +581,18 @@ func (obj *ItsiObj) Dump(ctx context.Context, queryParams *Parameters) ([]*ItsiO
 func (obj *ItsiObj) Iter(ctx context.Context, queryParams *Parameters) iter.Seq2[*ItsiObj, error] {
 	return func(yield func(*ItsiObj, error) bool) {
 		filter := ""
+		var fields []string
 		offset := 0
 		limit := obj.GetPageSize()
 		if queryParams != nil {
-			filter = queryParams.Filter
+			filter, fields = queryParams.Filter, queryParams.Fields
 			if queryParams.Count > 0 {
 				limit = queryParams.Count
 			}
 		}
 
 		for ; offset >= 0; offset += limit {
-			items, err := obj.Dump(ctx, &Parameters{Offset: offset, Count: limit, Filter: filter})
+			items, err := obj.Dump(ctx, &Parameters{Offset: offset, Count: limit, Fields: fields, Filter: filter})
 			if err != nil {
 				yield(nil, err)
 				return
@@ -761,3 +765,73 @@ func (obj *ItsiObj) getOriginHash(ctx context.Context) (string, error) {
 
 	return "", nil
 }
+
+// BulkUpdate creates or updates the given objects of the same object type via the ITSI bulk_update endpoint.
+// Objects are sent in batches, no larger than the object type's max page size.
+func (obj *ItsiObj) BulkUpdate(ctx context.Context, items []*ItsiObj) error {
+	batchSize := obj.GetPageSize()
+	if batchSize <= 0 {
+		batchSize = len(items)
+	}
+
+	for start := 0; start < len(items); start += batchSize {
+		batch := items[start:min(start+batchSize, len(items))]
+		raw := make([]RawJson, len(batch))
+		for i, item := range batch {
+			raw[i] = item.RawJson
+		}
+
+		reqBody, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Bulk updating %d %s objects", len(batch), obj.ObjectType))
+		if _, _, err = obj.requestWithRetry(ctx, http.MethodPost, obj.urlBase()+"/bulk_update", reqBody); err != nil {
+			return err
+		}
+
+		for _, item := range batch {
+			item.storeCache()
+		}
+	}
+	return nil
+}
+
+// DeleteByFilter deletes all objects of the given object type that match the filter.
+// An empty filter is rejected to prevent accidental deletion of all objects.
+func (obj *ItsiObj) DeleteByFilter(ctx context.Context, filter string) error {
+	if filter == "" {
+		return fmt.Errorf("refusing to delete %s objects: filter was not provided", obj.ObjectType)
+	}
+
+	params := url.Values{}
+	params.Add("filter", filter)
+
+	_, _, err := obj.requestWithRetry(ctx, http.MethodDelete, fmt.Sprintf("%s?%s", obj.urlBase(), params.Encode()), nil)
+	return err
+}
+
+// Count returns the number of objects of the given object type that match the filter.
+func (obj *ItsiObj) Count(ctx context.Context, filter string) (int, error) {
+	params := url.Values{}
+	if filter != "" {
+		params.Add("filter", filter)
+	}
+
+	_, respBody, err := obj.requestWithRetry(ctx, http.MethodGet, fmt.Sprintf("%s/count?%s", obj.urlBase(), params.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+	if respBody == nil {
+		return 0, fmt.Errorf("unexpected response while counting %s objects", obj.ObjectType)
+	}
+
+	var r struct {
+		Count int `json:"count"`
+	}
+	if err = json.Unmarshal(respBody, &r); err != nil {
+		return 0, err
+	}
+	return r.Count, nil
+}
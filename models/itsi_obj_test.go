@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPopulateRawJSONHash(t *testing.T) {
+	ctx := context.Background()
+	hash := func(body map[string]any) string {
+		obj := NewItsiObj(ClientConfig{}, "svc1", "service", "service")
+		if err := obj.PopulateRawJSON(ctx, body); err != nil {
+			t.Fatal(err)
+		}
+		m, err := obj.RawJson.ToInterfaceMap()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m[resourceHashField] != obj.Hash {
+			t.Errorf("expected %s to be set to %s, got %v", resourceHashField, obj.Hash, m[resourceHashField])
+		}
+		return obj.Hash
+	}
+
+	expected := hash(map[string]any{"title": "service", "kpis": []any{}})
+	// a body read from ITSI still holds the hash of its previous content
+	if actual := hash(map[string]any{"title": "service", "kpis": []any{}, resourceHashField: "previous"}); actual != expected {
+		t.Errorf("expected the hash of the content %s, got %s", expected, actual)
+	}
+}
+
+func TestHandleRequestErrorRetries(t *testing.T) {
+	obj := NewItsiObj(ClientConfig{RetryStatusCodes: []int{http.StatusServiceUnavailable}}, "svc1", "service", "service")
+	for _, test := range []struct {
+		statusCode int
+		expected   bool
+	}{
+		{0, true}, // transport error
+		{http.StatusServiceUnavailable, true},
+		{http.StatusInternalServerError, false},
+		{http.StatusNotFound, false},
+	} {
+		shouldRetry, _, _, _ := obj.handleRequestError(context.Background(), http.MethodGet, test.statusCode, nil, errors.New("failed"))
+		if shouldRetry != test.expected {
+			t.Errorf("status %d: expected retry %t, got %t", test.statusCode, test.expected, shouldRetry)
+		}
+	}
+}
@@ -22,6 +22,13 @@ entity:
     max_page_size: 1000
     generate_key: true
 
+entity_management_policy:
+    rest_interface: itoa_interface
+    object_type: entity_management_policies
+    rest_key_field: _key
+    tfid_field: title
+    generate_key: true
+
 entity_type:
     rest_interface: itoa_interface
     object_type: entity_type
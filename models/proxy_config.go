@@ -0,0 +1,29 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyFunc returns the proxy selection function of the Splunk API clients.
+// ProxyURL and NoProxy take precedence over the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func (c ClientConfig) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if c.ProxyURL != "" {
+		if _, err := url.Parse(c.ProxyURL); err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxyConfig.HTTPProxy, proxyConfig.HTTPSProxy = c.ProxyURL, c.ProxyURL
+	}
+	if c.NoProxy != "" {
+		proxyConfig.NoProxy = c.NoProxy
+	}
+
+	proxy := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/lestrrat-go/backoff/v2"
+)
+
+// NewRetryPolicy returns an exponential backoff policy making at most maxAttempts attempts in total, or unlimited attempts if 0.
+func NewRetryPolicy(minInterval, maxInterval time.Duration, maxAttempts int) backoff.Policy {
+	if maxAttempts == 1 {
+		return backoff.Null()
+	}
+	return backoff.Exponential(
+		backoff.WithMinInterval(minInterval),
+		backoff.WithMaxInterval(maxInterval),
+		backoff.WithJitterFactor(0.05),
+		backoff.WithMaxRetries(max(maxAttempts-1, 0)),
+	)
+}
+
+// RetryableStatus returns whether a request that failed with the HTTP status code should be retried.
+// By default, all statuses but 400, 401, 403, 404 and 409 are retried.
+// Transport errors, which have no status code, are not covered: the callers always retry them.
+func (c ClientConfig) RetryableStatus(statusCode int) bool {
+	if len(c.RetryStatusCodes) > 0 {
+		return slices.Contains(c.RetryStatusCodes, statusCode)
+	}
+	switch statusCode {
+	case 400, 401, 403, 404, 409:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+/*
+	With user/password authentication, the Splunk API is accessed using session keys:
+	the client logs in once per set of credentials, and logs in again when the session key expires.
+*/
+
+var sessions = &sessionKeys{keys: map[sessionKeyID]*sessionKey{}}
+
+type sessionKeyID struct {
+	host           string
+	port           int
+	user, password string
+}
+
+type sessionKey struct {
+	mu    sync.Mutex
+	value string
+}
+
+type sessionKeys struct {
+	mu   sync.Mutex
+	keys map[sessionKeyID]*sessionKey
+}
+
+// LoginError is returned when logging in to Splunk fails.
+type LoginError struct {
+	// StatusCode of the login response, or 0 if Splunk could not be reached.
+	StatusCode int
+	Err        error
+}
+
+func (e *LoginError) Error() string {
+	return fmt.Sprintf("Splunk login failed: %s", e.Err)
+}
+
+func (e *LoginError) Unwrap() error {
+	return e.Err
+}
+
+// LoginStatusCode returns the status code of the failed login, or 0 if the error is not a login error.
+func LoginStatusCode(err error) int {
+	var loginErr *LoginError
+	if errors.As(err, &loginErr) {
+		return loginErr.StatusCode
+	}
+	return 0
+}
+
+// UsesSessionAuth returns whether the Splunk API is accessed using session keys, rather than a bearer token.
+func (c ClientConfig) UsesSessionAuth() bool {
+	return c.BearerToken == "" && c.User != ""
+}
+
+// SessionKey returns the Splunk session key of the client config credentials, logging in if there is none yet.
+func SessionKey(ctx context.Context, config ClientConfig) (string, error) {
+	return sessions.get(ctx, config, "")
+}
+
+// RenewSessionKey logs in again after a request was rejected with the stale session key,
+// unless the session key has already been renewed meanwhile.
+func RenewSessionKey(ctx context.Context, config ClientConfig, stale string) (string, error) {
+	return sessions.get(ctx, config, stale)
+}
+
+func (s *sessionKeys) get(ctx context.Context, config ClientConfig, stale string) (string, error) {
+	id := sessionKeyID{config.Host, config.Port, config.User, config.Password}
+
+	s.mu.Lock()
+	key, ok := s.keys[id]
+	if !ok {
+		key = &sessionKey{}
+		s.keys[id] = key
+	}
+	s.mu.Unlock()
+
+	key.mu.Lock()
+	defer key.mu.Unlock()
+
+	if key.value != "" && key.value != stale {
+		return key.value, nil
+	}
+
+	value, err := login(ctx, config)
+	if err != nil {
+		return "", err
+	}
+	key.value = value
+	return value, nil
+}
+
+func login(ctx context.Context, config ClientConfig) (string, error) {
+	data := url.Values{
+		"username":    {config.User},
+		"password":    {config.Password},
+		"output_mode": {"json"},
+	}
+	loginURL := fmt.Sprintf("https://%s:%d/services/auth/login", config.Host, config.Port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := clients.Get(config).Do(req)
+	if err != nil {
+		return "", &LoginError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", &LoginError{StatusCode: resp.StatusCode, Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &LoginError{StatusCode: resp.StatusCode, Err: fmt.Errorf("%v \n%s", resp.Status, body)}
+	}
+
+	var key struct {
+		Value string `json:"sessionKey"`
+	}
+	if err := json.Unmarshal(body, &key); err != nil {
+		return "", &LoginError{StatusCode: resp.StatusCode, Err: err}
+	}
+	if key.Value == "" {
+		return "", &LoginError{StatusCode: resp.StatusCode, Err: errors.New("no session key received")}
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Logged in to Splunk as %s", config.User))
+	return key.Value, nil
+}
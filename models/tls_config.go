@@ -0,0 +1,58 @@
+package models
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadPEM returns PEM encoded data, given either the data itself or the path of a file containing it.
+func loadPEM(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// TLSConfig returns the TLS configuration of the Splunk API clients:
+// the CA bundle used to verify the server certificate and the client certificate, if any.
+func (c ClientConfig) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.SkipTLS}
+
+	if c.CACert != "" {
+		caCert, err := loadPEM(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		if tlsConfig.RootCAs, err = x509.SystemCertPool(); err != nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("failed to parse CA certificate: no PEM encoded certificates found")
+		}
+	}
+
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return nil, errors.New("client certificate and client key must be set together")
+	}
+	if c.ClientCert != "" {
+		clientCert, err := loadPEM(c.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+		clientKey, err := loadPEM(c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,325 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider/splunk"
+)
+
+const (
+	adaptiveThresholdsDefaultTrainingWindow = "-7d"
+	// app of ITSI's adaptive thresholds saved searches
+	adaptiveThresholdsSearchApp          = "SA-ITOA"
+	adaptiveThresholdsSearchPollInterval = 10 * time.Second
+)
+
+// adaptiveThresholdsTrainingWindows are the training windows ITSI ships adaptive thresholds saved searches for.
+var adaptiveThresholdsTrainingWindows = []string{"-7d", "-14d", "-30d", "-60d"}
+
+// AdaptiveThresholdLevel is an aggregate threshold level of a KPI adaptive threshold policy.
+type AdaptiveThresholdLevel struct {
+	Severity     string
+	DynamicParam float64
+	Threshold    float64
+}
+
+// AdaptiveThresholdPolicy holds the aggregate threshold levels of a KPI adaptive threshold policy.
+type AdaptiveThresholdPolicy struct {
+	Name   string
+	Type   string
+	Levels []AdaptiveThresholdLevel
+}
+
+// AdaptiveThresholds holds the recomputed adaptive thresholds of a KPI.
+type AdaptiveThresholds struct {
+	ServiceID    string
+	ServiceTitle string
+	KpiID        string
+	KpiTitle     string
+	Policies     []AdaptiveThresholdPolicy
+}
+
+// kpiThresholdPoliciesFromAPI returns the aggregate threshold policies of a KPI (or threshold template) API payload, sorted by policy name,
+// along with the API payload of every policy.
+func kpiThresholdPoliciesFromAPI(fields map[string]any) (policies []kpiThresholdPreviewPolicyModel, apiPolicies []map[string]any) {
+	timeVariate, _ := fields["time_variate_thresholds"].(bool)
+	spec, _ := fields["time_variate_thresholds_specification"].(map[string]any)
+	policiesByName, _ := spec["policies"].(map[string]any)
+
+	names := []string{}
+	for name := range policiesByName {
+		// policies other than the default one only apply to time variate thresholds
+		if timeVariate || name == kpiThresholdPreviewDefaultPolicy {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		apiPolicy, ok := policiesByName[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		policyType, _ := apiPolicy["policy_type"].(string)
+		policy := kpiThresholdPreviewPolicyModel{
+			PolicyName: types.StringValue(name),
+			PolicyType: types.StringValue(policyType),
+		}
+
+		timeBlocks, _ := UnpackSlice[[]any](apiPolicy["time_blocks"])
+		for _, tb := range timeBlocks {
+			if len(tb) != 2 {
+				continue
+			}
+			cron, _ := tb[0].(string)
+			interval, _ := splunkValueFloat64(tb[1])
+			policy.TimeBlocks = append(policy.TimeBlocks, TimeBlockModel{
+				Cron:     types.StringValue(cron),
+				Interval: types.Int64Value(int64(interval)),
+			})
+		}
+
+		aggregateThresholds, _ := apiPolicy["aggregate_thresholds"].(map[string]any)
+		if baseSeverity, ok := aggregateThresholds["baseSeverityLabel"].(string); ok {
+			policy.BaseSeverityLabel = types.StringValue(baseSeverity)
+		}
+		levels, _ := UnpackSlice[map[string]any](aggregateThresholds["thresholdLevels"])
+		for _, level := range levels {
+			severity, _ := level["severityLabel"].(string)
+			thresholdValue, _ := splunkValueFloat64(level["thresholdValue"])
+			dynamicParam, _ := splunkValueFloat64(level["dynamicParam"])
+			policy.ThresholdLevels = append(policy.ThresholdLevels, KpiThresholdLevelModel{
+				SeverityLabel:  types.StringValue(severity),
+				ThresholdValue: types.Float64Value(thresholdValue),
+				DynamicParam:   types.Float64Value(dynamicParam),
+			})
+		}
+
+		policies = append(policies, policy)
+		apiPolicies = append(apiPolicies, apiPolicy)
+	}
+	return
+}
+
+// AdaptiveThresholdsKpi is a service KPI with adaptive thresholds enabled.
+type AdaptiveThresholdsKpi struct {
+	ServiceID      string
+	ServiceTitle   string
+	KpiID          string
+	KpiTitle       string
+	TrainingWindow string
+}
+
+// AdaptiveThresholdsKpis returns the KPIs of the service that have adaptive thresholds enabled and are selected by match.
+func AdaptiveThresholdsKpis(service *models.ItsiObj, match func(kpi map[string]any) bool) (kpis []AdaptiveThresholdsKpi, err error) {
+	fields, err := service.RawJson.ToInterfaceMap()
+	if err != nil {
+		return
+	}
+	serviceTitle, _ := fields["title"].(string)
+
+	apiKpis, err := UnpackSlice[map[string]any](fields["kpis"])
+	if err != nil {
+		return
+	}
+	for _, kpi := range apiKpis {
+		kpiID, _ := kpi["_key"].(string)
+		if enabled, _ := kpi["adaptive_thresholds_is_enabled"].(bool); !enabled || strings.HasPrefix(kpiID, "SHKPI") || !match(kpi) {
+			continue
+		}
+		kpiTitle, _ := kpi["title"].(string)
+		trainingWindow, _ := kpi["adaptive_thresholding_training_window"].(string)
+		if trainingWindow == "" {
+			trainingWindow = adaptiveThresholdsDefaultTrainingWindow
+		}
+		kpis = append(kpis, AdaptiveThresholdsKpi{
+			ServiceID:      service.RESTKey,
+			ServiceTitle:   serviceTitle,
+			KpiID:          kpiID,
+			KpiTitle:       kpiTitle,
+			TrainingWindow: trainingWindow,
+		})
+	}
+	return
+}
+
+// adaptiveThresholdsSearch returns the name of the ITSI saved search that recomputes the adaptive thresholds of the KPIs
+// with the given training window, e.g. itsi_at_search_kpi_minus7d for -7d.
+func adaptiveThresholdsSearch(trainingWindow string) (string, error) {
+	if err := ValidateAdaptiveThresholdsTrainingWindow(trainingWindow); err != nil {
+		return "", err
+	}
+	return "itsi_at_search_kpi_minus" + strings.TrimPrefix(trainingWindow, "-"), nil
+}
+
+// ValidateAdaptiveThresholdsTrainingWindow returns an error if ITSI has no adaptive thresholds search for the training window,
+// so that its adaptive thresholds cannot be recomputed.
+func ValidateAdaptiveThresholdsTrainingWindow(trainingWindow string) error {
+	if !slices.Contains(adaptiveThresholdsTrainingWindows, trainingWindow) {
+		return fmt.Errorf("unsupported adaptive thresholding training window %q: adaptive thresholds can only be recomputed for %s",
+			trainingWindow, strings.Join(adaptiveThresholdsTrainingWindows, ", "))
+	}
+	return nil
+}
+
+// RecomputeAdaptiveThresholds triggers ITSI's adaptive thresholds searches for the training windows of the KPIs,
+// instead of waiting for their nightly schedule, and polls the search jobs until they complete.
+// ITSI recomputes the thresholds of every KPI with adaptive thresholds enabled and the same training window,
+// applying the KPI outlier exclusion settings and the service time zone, and saves them itself.
+// The new aggregate thresholds of the adaptive policies of the KPIs are then read back from ITSI.
+// If dryrun is set, no search is triggered and the current thresholds are returned.
+func RecomputeAdaptiveThresholds(ctx context.Context, client models.ClientConfig, kpis []AdaptiveThresholdsKpi, dryrun bool) (results []AdaptiveThresholds, diags diag.Diagnostics) {
+	searches := []string{}
+	for _, kpi := range kpis {
+		search, err := adaptiveThresholdsSearch(kpi.TrainingWindow)
+		if err != nil {
+			diags.AddError("Failed to recompute adaptive thresholds", fmt.Sprintf("KPI %q of service %q: %s", kpi.KpiTitle, kpi.ServiceTitle, err))
+			return
+		}
+		if !slices.Contains(searches, search) {
+			searches = append(searches, search)
+		}
+	}
+	sort.Strings(searches)
+
+	if !dryrun {
+		for _, search := range searches {
+			if diags.Append(runAdaptiveThresholdsSearch(ctx, client, search)...); diags.HasError() {
+				return
+			}
+		}
+	}
+
+	kpisByService := map[string][]AdaptiveThresholdsKpi{}
+	serviceIDs := []string{}
+	for _, kpi := range kpis {
+		if _, ok := kpisByService[kpi.ServiceID]; !ok {
+			serviceIDs = append(serviceIDs, kpi.ServiceID)
+		}
+		kpisByService[kpi.ServiceID] = append(kpisByService[kpi.ServiceID], kpi)
+	}
+
+	for _, serviceID := range serviceIDs {
+		service, err := models.NewItsiObj(client, serviceID, "", "service").Read(ctx)
+		if err != nil {
+			diags.AddError("Failed to read recomputed adaptive thresholds", err.Error())
+			return
+		}
+		if service == nil {
+			continue
+		}
+		fields, err := service.RawJson.ToInterfaceMap()
+		if err != nil {
+			diags.AddError("Failed to read recomputed adaptive thresholds", err.Error())
+			return
+		}
+		apiKpis, err := UnpackSlice[map[string]any](fields["kpis"])
+		if err != nil {
+			diags.AddError("Failed to read recomputed adaptive thresholds", err.Error())
+			return
+		}
+
+		for _, kpi := range kpisByService[serviceID] {
+			i := slices.IndexFunc(apiKpis, func(apiKpi map[string]any) bool { return apiKpi["_key"] == kpi.KpiID })
+			if i < 0 {
+				continue
+			}
+			results = append(results, adaptiveThresholdsOf(kpi, apiKpis[i]))
+		}
+	}
+	return
+}
+
+// runAdaptiveThresholdsSearch dispatches an ITSI adaptive thresholds saved search, and polls its job until it completes.
+func runAdaptiveThresholdsSearch(ctx context.Context, client models.ClientConfig, search string) (diags diag.Diagnostics) {
+	client, err := client.WithCredentials(ctx)
+	if err != nil {
+		diags.AddError("Couldn't retrieve Splunk credentials", err.Error())
+		return
+	}
+	client = client.AnyEndpoint(ctx)
+
+	var conn splunk.SplunkConnection
+	var sid string
+	var loginErr error
+	err = withRefreshedCredentials(ctx, client, func(client models.ClientConfig) (err error) {
+		var sessionKey string
+		if conn, sessionKey, loginErr = splunkConnection(ctx, client, adaptiveThresholdsSearchApp, searchDefaultUser); loginErr != nil {
+			return loginErr
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Dispatching adaptive thresholds search %s", search))
+		sid, err = conn.DispatchSavedSearch(ctx, search, nil)
+		if errors.Is(err, splunk.ErrUnauthorized) && sessionKey != "" {
+			// the session key has expired or has been revoked: log in again and repeat the request
+			if err = renewSplunkSession(ctx, client, &conn, sessionKey); err == nil {
+				sid, err = conn.DispatchSavedSearch(ctx, search, nil)
+			}
+		}
+		return err
+	})
+	if loginErr != nil {
+		diags.AddError("Couldn't login to Splunk", loginErr.Error())
+		return
+	}
+	if err != nil {
+		diags.AddError("Failed to trigger adaptive thresholds recomputation", err.Error())
+		return
+	}
+
+	ticker := time.NewTicker(adaptiveThresholdsSearchPollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := conn.JobStatus(ctx, sid)
+		if err != nil {
+			diags.AddError("Failed to check adaptive thresholds recomputation", err.Error())
+			return
+		}
+		if job.IsDone {
+			if err := job.Err(); err != nil {
+				diags.AddError("Adaptive thresholds recomputation failed", fmt.Sprintf("%s: %s", search, err))
+			}
+			return
+		}
+		tflog.Debug(ctx, fmt.Sprintf("Adaptive thresholds search %s (sid=%s) is %s", search, sid, job.DispatchState))
+
+		select {
+		case <-ctx.Done():
+			diags.AddError("Timed out waiting for adaptive thresholds recomputation",
+				fmt.Sprintf("Search %s (sid=%s) did not complete: %s", search, sid, ctx.Err()))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// adaptiveThresholdsOf returns the aggregate thresholds of the adaptive policies of a KPI API payload.
+func adaptiveThresholdsOf(kpi AdaptiveThresholdsKpi, apiKpi map[string]any) AdaptiveThresholds {
+	result := AdaptiveThresholds{ServiceID: kpi.ServiceID, ServiceTitle: kpi.ServiceTitle, KpiID: kpi.KpiID, KpiTitle: kpi.KpiTitle}
+	policies, _ := kpiThresholdPoliciesFromAPI(apiKpi)
+	for _, policy := range policies {
+		if policy.PolicyType.ValueString() == "static" {
+			continue
+		}
+		thresholds := AdaptiveThresholdPolicy{Name: policy.PolicyName.ValueString(), Type: policy.PolicyType.ValueString()}
+		for _, level := range policy.ThresholdLevels {
+			thresholds.Levels = append(thresholds.Levels, AdaptiveThresholdLevel{
+				Severity:     level.SeverityLabel.ValueString(),
+				DynamicParam: level.DynamicParam.ValueFloat64(),
+				Threshold:    level.ThresholdValue.ValueFloat64(),
+			})
+		}
+		result.Policies = append(result.Policies, thresholds)
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdaptiveThresholdsSearch(t *testing.T) {
+	for trainingWindow, expected := range map[string]string{
+		"-7d":  "itsi_at_search_kpi_minus7d",
+		"-14d": "itsi_at_search_kpi_minus14d",
+		"-60d": "itsi_at_search_kpi_minus60d",
+	} {
+		if actual, err := adaptiveThresholdsSearch(trainingWindow); err != nil || actual != expected {
+			t.Errorf("%s: expected %s, got %s (%v)", trainingWindow, expected, actual, err)
+		}
+	}
+
+	// ITSI has no saved search for other windows
+	for _, trainingWindow := range []string{"", "7d", "-12h", "-7d@d", "-1d", "-21d"} {
+		if _, err := adaptiveThresholdsSearch(trainingWindow); err == nil {
+			t.Errorf("%q: expected an error", trainingWindow)
+		}
+	}
+}
+
+func TestAdaptiveThresholdsOf(t *testing.T) {
+	kpi := AdaptiveThresholdsKpi{ServiceID: "s1", ServiceTitle: "service", KpiID: "k1", KpiTitle: "kpi", TrainingWindow: "-7d"}
+	apiKpi := map[string]any{
+		"time_variate_thresholds": true,
+		"time_variate_thresholds_specification": map[string]any{
+			"policies": map[string]any{
+				"default_policy": map[string]any{
+					"policy_type": "stdev",
+					"aggregate_thresholds": map[string]any{
+						"thresholdLevels": []any{
+							map[string]any{"severityLabel": "high", "thresholdValue": 12.5, "dynamicParam": 2.0},
+						},
+					},
+				},
+				"weekend": map[string]any{
+					"policy_type": "static",
+					"aggregate_thresholds": map[string]any{
+						"thresholdLevels": []any{
+							map[string]any{"severityLabel": "critical", "thresholdValue": 100.0, "dynamicParam": 0.0},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := AdaptiveThresholds{
+		ServiceID: "s1", ServiceTitle: "service", KpiID: "k1", KpiTitle: "kpi",
+		Policies: []AdaptiveThresholdPolicy{{
+			Name:   "default_policy",
+			Type:   "stdev",
+			Levels: []AdaptiveThresholdLevel{{Severity: "high", DynamicParam: 2, Threshold: 12.5}},
+		}},
+	}
+	if actual := adaptiveThresholdsOf(kpi, apiKpi); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected:\n%+v\ngot:\n%+v", expected, actual)
+	}
+}
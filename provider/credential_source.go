@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tivo/terraform-provider-splunk-itsi/credentials"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+/*
+	Credential sources retrieve the Splunk API credentials from external secret backends,
+	instead of the access_token, user and password provider attributes.
+*/
+
+type credentialSourceModel struct {
+	File  types.String                `tfsdk:"file"`
+	Exec  types.List                  `tfsdk:"exec"`
+	SSM   *credentialSourceSSMModel   `tfsdk:"ssm"`
+	Vault *credentialSourceVaultModel `tfsdk:"vault"`
+}
+
+type credentialSourceSSMModel struct {
+	Parameter types.String `tfsdk:"parameter"`
+	Profile   types.String `tfsdk:"profile"`
+	Region    types.String `tfsdk:"region"`
+}
+
+type credentialSourceVaultModel struct {
+	Address types.String `tfsdk:"address"`
+	Token   types.String `tfsdk:"token"`
+	Path    types.String `tfsdk:"path"`
+}
+
+func credentialSourceSchema() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: util.Dedent(`
+			Retrieves the API credentials from an external secret backend, instead of the access_token, user and password attributes.
+			Exactly one source must be configured. Credentials are either a bearer token, or a user and a password.
+			Credentials retrieved from AWS SSM and Vault are cached for 5 minutes, and credentials rejected by Splunk are retrieved again,
+			so that rotated secrets are picked up.
+		`),
+		Attributes: map[string]schema.Attribute{
+			"file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: util.Dedent(`
+					Path of a file containing a bearer token, or credentials as a JSON object with token, user and password keys.
+					The file is read again whenever it changes, so that rotated tokens are picked up.
+				`),
+			},
+			"exec": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: util.Dedent(`
+					Credential helper command and arguments. The command must print credentials to stdout as a JSON object
+					with token, user and password keys, and an optional RFC 3339 expiration, until which the credentials are cached.
+				`),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"ssm": schema.SingleNestedBlock{
+				MarkdownDescription: "Retrieves a bearer token from an AWS SSM (secure string) parameter.",
+				Attributes: map[string]schema.Attribute{
+					"parameter": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Name of the SSM parameter.",
+					},
+					"profile": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "AWS shared config profile. By default, the AWS SDK defaults apply.",
+					},
+					"region": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "AWS region. By default, the AWS SDK defaults apply.",
+					},
+				},
+			},
+			"vault": schema.SingleNestedBlock{
+				MarkdownDescription: "Retrieves the credentials from the token, user and password keys of a HashiCorp Vault KV secret.",
+				Attributes: map[string]schema.Attribute{
+					"address": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Vault address. Defaults to the VAULT_ADDR environment variable.",
+					},
+					"token": schema.StringAttribute{
+						Optional:            true,
+						Sensitive:           true,
+						MarkdownDescription: "Vault token. Defaults to the VAULT_TOKEN environment variable, or the ~/.vault-token file.",
+					},
+					"path": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "API path of the secret, e.g. secret/data/splunk/itsi for a KV v2 secret, or kv/splunk/itsi for a KV v1 secret.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (m *credentialSourceModel) config(ctx context.Context) (c credentials.Config, diags diag.Diagnostics) {
+	c.File = m.File.ValueString()
+	if !m.Exec.IsNull() {
+		diags.Append(m.Exec.ElementsAs(ctx, &c.Exec, false)...)
+	}
+	if m.SSM != nil {
+		c.SSM = &credentials.SSMConfig{
+			Parameter: m.SSM.Parameter.ValueString(),
+			Profile:   m.SSM.Profile.ValueString(),
+			Region:    m.SSM.Region.ValueString(),
+		}
+	}
+	if m.Vault != nil {
+		c.Vault = &credentials.VaultConfig{
+			Address: m.Vault.Address.ValueString(),
+			Token:   m.Vault.Token.ValueString(),
+			Path:    m.Vault.Path.ValueString(),
+		}
+	}
+	return
+}
+
+// source returns the configured credential source, checking that the credentials can be retrieved.
+func (m *credentialSourceModel) source(ctx context.Context) (source credentials.Source, diags diag.Diagnostics) {
+	const summary = "Invalid credential_source configuration"
+
+	c, diags := m.config(ctx)
+	if diags.HasError() {
+		return
+	}
+
+	source, err := c.Source()
+	if err != nil {
+		diags.AddError(summary, err.Error())
+		return
+	}
+	if source == nil {
+		diags.AddError(summary, "one of file, exec, ssm or vault must be set")
+		return
+	}
+
+	if _, err := source.Credentials(ctx); err != nil {
+		diags.AddError("Unable to retrieve Splunk API credentials", err.Error())
+	}
+	return
+}
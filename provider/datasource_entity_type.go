@@ -23,8 +23,12 @@ type dataSourceEntityType struct {
 }
 
 type dataSourceEntityTypeModel struct {
-	ID    types.String `tfsdk:"id"`
-	Title types.String `tfsdk:"title"`
+	ID                 types.String                        `tfsdk:"id"`
+	Title              types.String                        `tfsdk:"title"`
+	Description        types.String                        `tfsdk:"description"`
+	DashboardDrilldown []entityTypeDashboardDrilldownModel `tfsdk:"dashboard_drilldown"`
+	DataDrilldown      []entityTypeDataDrilldownModel      `tfsdk:"data_drilldown"`
+	VitalMetric        []entityTypeVitalMetricModel        `tfsdk:"vital_metric"`
 
 	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
@@ -47,10 +51,14 @@ func (d *dataSourceEntityType) Metadata(_ context.Context, req datasource.Metada
 }
 
 func (d *dataSourceEntityType) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	et := new(resourceEntityType)
 	resp.Schema = schema.Schema{
-		Description: "Use this data source to get the ID of an available entity type.",
+		Description: "Use this data source to get the ID and configuration of an available entity type.",
 		Blocks: map[string]schema.Block{
-			"timeouts": timeouts.Block(ctx),
+			"timeouts":            timeouts.Block(ctx),
+			"dashboard_drilldown": computedSetNestedBlock(et.dashboardDrilldownSchema()),
+			"data_drilldown":      computedSetNestedBlock(et.dataDrilldownSchema()),
+			"vital_metric":        computedSetNestedBlock(et.vitalMetricSchema()),
 		},
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -61,6 +69,10 @@ func (d *dataSourceEntityType) Schema(ctx context.Context, _ datasource.SchemaRe
 				Description: "The name of the entity type",
 				Required:    true,
 			},
+			"description": schema.StringAttribute{
+				Description: "A description of the entity type",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -95,10 +107,19 @@ func (d *dataSourceEntityType) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
+	entityType, diags := newAPIParser(b, new(entityTypeParseWorkflow)).parse(ctx, b)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
 	state := dataSourceEntityTypeModel{
-		ID:       types.StringValue(b.RESTKey),
-		Title:    types.StringValue(title),
-		Timeouts: timeouts,
+		ID:                 types.StringValue(b.RESTKey),
+		Title:              types.StringValue(title),
+		Description:        entityType.Description,
+		DashboardDrilldown: entityType.DashboardDrilldown,
+		DataDrilldown:      entityType.DataDrilldown,
+		VitalMetric:        entityType.VitalMetric,
+		Timeouts:           timeouts,
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -24,6 +24,21 @@ func TestAccDataSourceEntityTypeLifecycle(t *testing.T) {
 			{
 				ProtoV6ProviderFactories: providerFactories,
 				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.itsi_entity_type.test", "id", "itsi_entity_type.test", "id"),
+					resource.TestCheckResourceAttr("data.itsi_entity_type.test", "description", "TestAcc EXAMPLE"),
+					resource.TestCheckResourceAttr("data.itsi_entity_type.test", "vital_metric.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.itsi_entity_type.test", "vital_metric.*", map[string]string{
+						"metric_name":                     "Average CPU Usage",
+						"is_key":                          "true",
+						"unit":                            "%",
+						"matching_entity_fields.host":     "host",
+						"alert_rule.#":                    "1",
+						"alert_rule.0.critical_threshold": "90",
+						"alert_rule.0.warning_threshold":  "75",
+						"alert_rule.0.entity_filter.0.field_type": "alias",
+					}),
+				),
 			},
 		},
 	})
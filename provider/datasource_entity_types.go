@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+var (
+	_ datasource.DataSource              = &dataSourceEntityTypes{}
+	_ datasource.DataSourceWithConfigure = &dataSourceEntityTypes{}
+)
+
+type dataSourceEntityTypes struct {
+	client models.ClientConfig
+}
+
+type dataSourceEntityTypesModel struct {
+	Title       types.String                      `tfsdk:"title"`
+	EntityTypes []dataSourceEntityTypesEntryModel `tfsdk:"entity_types"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type dataSourceEntityTypesEntryModel struct {
+	ID          types.String `tfsdk:"id"`
+	Title       types.String `tfsdk:"title"`
+	Description types.String `tfsdk:"description"`
+}
+
+func NewDataSourceEntityTypes() datasource.DataSource {
+	return &dataSourceEntityTypes{}
+}
+
+func (d *dataSourceEntityTypes) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	configureDataSourceClient(ctx, datasourceNameEntityTypes, req, &d.client, resp)
+}
+
+func (d *dataSourceEntityTypes) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	configureDataSourceMetadata(req, resp, datasourceNameEntityTypes)
+}
+
+func (d *dataSourceEntityTypes) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Use this data source to list the available entity types, optionally filtered by title.",
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+		Attributes: map[string]schema.Attribute{
+			"title": schema.StringAttribute{
+				MarkdownDescription: util.Dedent(`
+					Case-insensitive title filter. The wildcard character * matches any sequence of characters.
+					If omitted, all entity types are returned.
+				`),
+				Optional: true,
+			},
+			"entity_types": schema.ListNestedAttribute{
+				Description: "Entity types matching the title filter, sorted by title.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Identifier for this entity type",
+							Computed:    true,
+						},
+						"title": schema.StringAttribute{
+							Description: "The name of the entity type",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "A description of the entity type",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *dataSourceEntityTypes) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read entity types data source")
+	var config dataSourceEntityTypesModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	timeouts := config.Timeouts
+	readTimeout, diags := timeouts.Read(ctx, tftimeout.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	var filter string
+	if pattern := config.Title.ValueString(); pattern != "" {
+		f, err := json.Marshal(map[string]any{"title": map[string]string{"$regex": util.WildcardToRegexpStr(pattern)}})
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to render entity type title filter", err.Error())
+			return
+		}
+		filter = string(f)
+	}
+
+	entries := []dataSourceEntityTypesEntryModel{}
+	base := entityTypeBase(d.client, "", "")
+	for item, err := range base.Iter(ctx, &models.Parameters{Filter: filter}) {
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to read entity types", err.Error())
+			return
+		}
+
+		fields, err := item.RawJson.ToInterfaceMap()
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to read entity types", err.Error())
+			return
+		}
+		stringMap, err := unpackMap[string](mapSubset(fields, []string{"title", "description"}))
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to read entity types", err.Error())
+			return
+		}
+
+		entries = append(entries, dataSourceEntityTypesEntryModel{
+			ID:          types.StringValue(item.RESTKey),
+			Title:       types.StringValue(stringMap["title"]),
+			Description: types.StringValue(stringMap["description"]),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Title.ValueString() < entries[j].Title.ValueString()
+	})
+
+	state := dataSourceEntityTypesModel{
+		Title:       config.Title,
+		EntityTypes: entries,
+		Timeouts:    timeouts,
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading entity types data source", map[string]any{"success": true})
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDataSourceEntityTypesSchema(t *testing.T) {
+	testDataSourceSchema(t, new(dataSourceEntityTypes))
+}
+
+func TestAccDataSourceEntityTypesLifecycle(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckResourceDestroy(resourceNameEntityType, "TestAcc_DataSourceEntityTypesLifecycle_pod"),
+			testAccCheckResourceDestroy(resourceNameEntityType, "TestAcc_DataSourceEntityTypesLifecycle_node"),
+		),
+		Steps: []resource.TestStep{
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+			},
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.itsi_entity_types.all", "entity_types.#", "2"),
+					resource.TestCheckResourceAttr("data.itsi_entity_types.all", "entity_types.0.title", "TestAcc_DataSourceEntityTypesLifecycle_node"),
+					resource.TestCheckResourceAttrPair("data.itsi_entity_types.all", "entity_types.0.id", "itsi_entity_type.node", "id"),
+					resource.TestCheckResourceAttr("data.itsi_entity_types.all", "entity_types.1.title", "TestAcc_DataSourceEntityTypesLifecycle_pod"),
+					resource.TestCheckResourceAttr("data.itsi_entity_types.pod", "entity_types.#", "1"),
+					resource.TestCheckResourceAttrPair("data.itsi_entity_types.pod", "entity_types.0.id", "itsi_entity_type.pod", "id"),
+				),
+			},
+		},
+	})
+}
@@ -47,19 +47,35 @@ func (d *dataSourceKpiBaseSearch) Configure(ctx context.Context, req datasource.
 
 // computedSetNestedBlock converts a resource SetNestedBlock into a datasource
 // SetNestedBlock where every attribute is Computed-only, preserving descriptions.
+// Nested blocks are converted recursively.
 func computedSetNestedBlock(b rsschema.SetNestedBlock) schema.SetNestedBlock {
 	attrs := make(map[string]schema.Attribute, len(b.NestedObject.Attributes))
 	for name, attr := range b.NestedObject.Attributes {
 		switch a := attr.(type) {
 		case rsschema.StringAttribute:
-			attrs[name] = schema.StringAttribute{Computed: true, Description: a.Description}
+			attrs[name] = schema.StringAttribute{Computed: true, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
 		case rsschema.Float64Attribute:
-			attrs[name] = schema.Float64Attribute{Computed: true, Description: a.Description}
+			attrs[name] = schema.Float64Attribute{Computed: true, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+		case rsschema.Int64Attribute:
+			attrs[name] = schema.Int64Attribute{Computed: true, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+		case rsschema.BoolAttribute:
+			attrs[name] = schema.BoolAttribute{Computed: true, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+		case rsschema.MapAttribute:
+			attrs[name] = schema.MapAttribute{Computed: true, ElementType: a.ElementType, Description: a.Description, MarkdownDescription: a.MarkdownDescription}
+		}
+	}
+	blocks := make(map[string]schema.Block, len(b.NestedObject.Blocks))
+	for name, block := range b.NestedObject.Blocks {
+		if nested, ok := block.(rsschema.SetNestedBlock); ok {
+			blocks[name] = computedSetNestedBlock(nested)
 		}
 	}
 	return schema.SetNestedBlock{
+		Description:         b.Description,
+		MarkdownDescription: b.MarkdownDescription,
 		NestedObject: schema.NestedBlockObject{
 			Attributes: attrs,
+			Blocks:     blocks,
 		},
 	}
 }
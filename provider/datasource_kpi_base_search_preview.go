@@ -0,0 +1,351 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider/splunk"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+const (
+	kpiBaseSearchPreviewDefaultEarliestTime = "-15m"
+	kpiBaseSearchPreviewDefaultMaxEvents    = 10000
+	kpiBaseSearchPreviewMaxEventsLimit      = 1000000
+
+	kpiBaseSearchPreviewEntityField      = "_preview_entity"
+	kpiBaseSearchPreviewEntityCountField = "entity_count"
+)
+
+var (
+	_ datasource.DataSource              = &dataSourceKpiBaseSearchPreview{}
+	_ datasource.DataSourceWithConfigure = &dataSourceKpiBaseSearchPreview{}
+)
+
+type dataSourceKpiBaseSearchPreview struct {
+	client models.ClientConfig
+}
+
+type dataSourceKpiBaseSearchPreviewModel struct {
+	BaseSearchID            types.String                             `tfsdk:"base_search_id"`
+	BaseSearch              types.String                             `tfsdk:"base_search"`
+	EntityBreakdownIDFields types.String                             `tfsdk:"entity_breakdown_id_fields"`
+	Metric                  []kpiBaseSearchPreviewMetricModel        `tfsdk:"metric"`
+	EarliestTime            types.String                             `tfsdk:"earliest_time"`
+	LatestTime              types.String                             `tfsdk:"latest_time"`
+	MaxEvents               types.Int64                              `tfsdk:"max_events"`
+	Query                   types.String                             `tfsdk:"query"`
+	EntityCount             types.Int64                              `tfsdk:"entity_count"`
+	Results                 []kpiBaseSearchPreviewMetricResultsModel `tfsdk:"results"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type kpiBaseSearchPreviewMetricModel struct {
+	Title           types.String `tfsdk:"title"`
+	ThresholdField  types.String `tfsdk:"threshold_field"`
+	AggregateStatOp types.String `tfsdk:"aggregate_statop"`
+	EntityStatOp    types.String `tfsdk:"entity_statop"`
+}
+
+type kpiBaseSearchPreviewMetricResultsModel struct {
+	Title types.String  `tfsdk:"title"`
+	Value types.Float64 `tfsdk:"value"`
+}
+
+func NewDataSourceKpiBaseSearchPreview() datasource.DataSource {
+	return &dataSourceKpiBaseSearchPreview{}
+}
+
+func (d *dataSourceKpiBaseSearchPreview) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	configureDataSourceClient(ctx, datasourceNameKPIBaseSearchPreview, req, &d.client, resp)
+}
+
+func (d *dataSourceKpiBaseSearchPreview) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	configureDataSourceMetadata(req, resp, datasourceNameKPIBaseSearchPreview)
+}
+
+func (d *dataSourceKpiBaseSearchPreview) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	statopValidators := []validator.String{
+		stringvalidator.RegexMatches(kpiBaseSearchStatOpRegexp, ""),
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: util.Dedent(`
+			Use this data source to preview what a KPI base search returns before linking services to it.
+			The base search, either an existing one or an inline definition, is run over a bounded time window;
+			each metric's threshold_field is aggregated the way ITSI would aggregate it, and the number of distinct entities is counted.
+		`),
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+			"metric": schema.ListNestedBlock{
+				MarkdownDescription: "Inline metric definitions. Required when `base_search` is set; ignored otherwise.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"title": schema.StringAttribute{
+							MarkdownDescription: "Name of this metric.",
+							Required:            true,
+						},
+						"threshold_field": schema.StringAttribute{
+							MarkdownDescription: "The field on which the statistical operation runs.",
+							Required:            true,
+						},
+						"aggregate_statop": schema.StringAttribute{
+							MarkdownDescription: "Statistical operation (avg, max, median, stdev, and so on) used to combine data for the aggregate value.",
+							Required:            true,
+							Validators:          statopValidators,
+						},
+						"entity_statop": schema.StringAttribute{
+							MarkdownDescription: "Statistical operation used to combine data on a per entity basis. Defaults to `aggregate_statop`.",
+							Optional:            true,
+							Validators:          statopValidators,
+						},
+					},
+				},
+			},
+		},
+		Attributes: map[string]schema.Attribute{
+			"base_search_id": schema.StringAttribute{
+				MarkdownDescription: "ID of an existing KPI base search to preview.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("base_search")),
+				},
+			},
+			"base_search": schema.StringAttribute{
+				MarkdownDescription: "Inline KPI base search to preview.",
+				Optional:            true,
+				Validators:          []validator.String{baseSearchValidator{}},
+			},
+			"entity_breakdown_id_fields": schema.StringAttribute{
+				MarkdownDescription: "Comma-separated fields the inline base search is split by entity on. If empty, the search is not split by entity.",
+				Optional:            true,
+			},
+			"earliest_time": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Earliest time of the preview window. Defaults to `%s`.", kpiBaseSearchPreviewDefaultEarliestTime),
+				Optional:            true,
+				Validators:          []validator.String{stringvalidatorIsSplunkRelativeTime()},
+			},
+			"latest_time": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Latest time of the preview window. Defaults to `%s`.", searchDefaultLatestTime),
+				Optional:            true,
+				Validators:          []validator.String{stringvalidatorIsSplunkRelativeTime()},
+			},
+			"max_events": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of base search events to aggregate, to keep plans fast. Defaults to %d.", kpiBaseSearchPreviewDefaultMaxEvents),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, kpiBaseSearchPreviewMaxEventsLimit),
+				},
+			},
+			"query": schema.StringAttribute{
+				MarkdownDescription: "The generated preview search.",
+				Computed:            true,
+			},
+			"entity_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of distinct entities returned by the base search. 0 if the search is not split by entity.",
+				Computed:            true,
+			},
+			"results": schema.ListNestedAttribute{
+				MarkdownDescription: "Aggregate value of every metric.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"title": schema.StringAttribute{
+							MarkdownDescription: "Name of the metric.",
+							Computed:            true,
+						},
+						"value": schema.Float64Attribute{
+							MarkdownDescription: "Aggregate value of the metric. Null if the search returned no numeric value.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// kpiBaseSearchPreviewQuery generates a search that aggregates the base search results the way ITSI does for KPIs:
+// per entity first (if split by entity), then across entities. The number of base search events is bounded by maxEvents.
+func kpiBaseSearchPreviewQuery(baseSearch string, splitFields []string, metrics []kpiBaseSearchPreviewMetricModel, maxEvents int64) string {
+	quote := func(field string) string {
+		return strconv.Quote(field)
+	}
+
+	var query strings.Builder
+	baseSearch = strings.TrimSpace(baseSearch)
+	if !strings.HasPrefix(baseSearch, "|") {
+		query.WriteString("search ")
+	}
+	query.WriteString(baseSearch)
+	fmt.Fprintf(&query, " | head %d", maxEvents)
+
+	if len(splitFields) == 0 {
+		aggregates := []string{}
+		for i, m := range metrics {
+			aggregates = append(aggregates, fmt.Sprintf("%s(%s) as metric_%d", m.AggregateStatOp.ValueString(), quote(m.ThresholdField.ValueString()), i))
+		}
+		fmt.Fprintf(&query, " | stats %s | eval %s=0", strings.Join(aggregates, ", "), kpiBaseSearchPreviewEntityCountField)
+		return query.String()
+	}
+
+	entityKey := []string{}
+	for _, f := range splitFields {
+		entityKey = append(entityKey, fmt.Sprintf("'%s'", f))
+	}
+	fmt.Fprintf(&query, " | eval %s=%s", kpiBaseSearchPreviewEntityField, strings.Join(entityKey, `."|".`))
+
+	entityAggregates, aggregates := []string{}, []string{}
+	for i, m := range metrics {
+		entityStatOp := m.EntityStatOp.ValueString()
+		if entityStatOp == "" {
+			entityStatOp = m.AggregateStatOp.ValueString()
+		}
+		entityAggregates = append(entityAggregates, fmt.Sprintf("%s(%s) as metric_%d", entityStatOp, quote(m.ThresholdField.ValueString()), i))
+		aggregates = append(aggregates, fmt.Sprintf("%s(metric_%d) as metric_%d", m.AggregateStatOp.ValueString(), i, i))
+	}
+	fmt.Fprintf(&query, " | stats %s by %s", strings.Join(entityAggregates, ", "), kpiBaseSearchPreviewEntityField)
+	fmt.Fprintf(&query, " | stats %s", strings.Join(append(aggregates, "count as "+kpiBaseSearchPreviewEntityCountField), ", "))
+	return query.String()
+}
+
+func (d *dataSourceKpiBaseSearchPreview) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read KPI base search preview data source")
+	var config dataSourceKpiBaseSearchPreviewModel
+
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &config)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, tftimeout.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	baseSearch, splitFields, metrics := config.BaseSearch.ValueString(), config.EntityBreakdownIDFields.ValueString(), config.Metric
+	if !config.BaseSearchID.IsNull() {
+		b, err := kpiBaseSearchBase(d.client, config.BaseSearchID.ValueString(), "").Find(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to read KPI BS object", err.Error())
+			return
+		}
+		if b == nil {
+			resp.Diagnostics.AddAttributeError(path.Root("base_search_id"), "KPI BS not found",
+				fmt.Sprintf("KPI BS with id %q not found", config.BaseSearchID.ValueString()))
+			return
+		}
+
+		kbs, diags := newAPIParser(b, new(kpiBaseSearchParseWorkflow)).parse(ctx, b)
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+			return
+		}
+
+		baseSearch, splitFields, metrics = kbs.BaseSearch.ValueString(), "", nil
+		if kbs.IsEntityBreakdown.ValueBool() {
+			splitFields = kbs.EntityBreakdownIDFields.ValueString()
+		}
+		for _, m := range kbs.Metrics {
+			metrics = append(metrics, kpiBaseSearchPreviewMetricModel{
+				Title:           m.Title,
+				ThresholdField:  m.ThresholdField,
+				AggregateStatOp: m.AggregateStatOp,
+				EntityStatOp:    m.EntityStatOp,
+			})
+		}
+	}
+
+	if len(metrics) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("metric"), "No metrics to preview",
+			"At least one metric is required to preview a KPI base search.")
+		return
+	}
+
+	fields := []string{}
+	for _, f := range strings.Split(splitFields, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	maxEvents := int64(kpiBaseSearchPreviewDefaultMaxEvents)
+	if !config.MaxEvents.IsNull() {
+		maxEvents = config.MaxEvents.ValueInt64()
+	}
+	earliestTime, latestTime := kpiBaseSearchPreviewDefaultEarliestTime, searchDefaultLatestTime
+	if !config.EarliestTime.IsNull() {
+		earliestTime = config.EarliestTime.ValueString()
+	}
+	if !config.LatestTime.IsNull() {
+		latestTime = config.LatestTime.ValueString()
+	}
+
+	query := kpiBaseSearchPreviewQuery(baseSearch, fields, metrics, maxEvents)
+	splunkreq := NewSplunkRequest(d.client, []SplunkSearch{{
+		Query:          query,
+		User:           searchDefaultUser,
+		App:            searchDefaultApp,
+		EarliestTime:   earliestTime,
+		LatestTime:     latestTime,
+		AllowNoResults: true,
+		Timeout:        int(readTimeout.Seconds()),
+	}}, 1, nil, true, " ")
+
+	rows, diags := splunkreq.Run(ctx)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	row := map[string]splunk.Value{}
+	if len(rows) > 0 {
+		row = rows[0]
+	}
+
+	state := config
+	state.Query = types.StringValue(query)
+	state.EntityCount = types.Int64Value(0)
+	if v, ok := splunkValueFloat64(row[kpiBaseSearchPreviewEntityCountField]); ok {
+		state.EntityCount = types.Int64Value(int64(v))
+	}
+
+	state.Results = []kpiBaseSearchPreviewMetricResultsModel{}
+	for i, m := range metrics {
+		value := types.Float64Null()
+		if v, ok := splunkValueFloat64(row[fmt.Sprintf("metric_%d", i)]); ok {
+			value = types.Float64Value(v)
+		}
+		state.Results = append(state.Results, kpiBaseSearchPreviewMetricResultsModel{
+			Title: m.Title,
+			Value: value,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading KPI base search preview data source", map[string]any{"success": true})
+}
+
+func splunkValueFloat64(v splunk.Value) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+func TestDataSourceKPIBaseSearchPreviewSchema(t *testing.T) {
+	testDataSourceSchema(t, new(dataSourceKpiBaseSearchPreview))
+}
+
+func TestKpiBaseSearchPreviewQuery(t *testing.T) {
+	metrics := []kpiBaseSearchPreviewMetricModel{
+		{
+			Title:           types.StringValue("latency"),
+			ThresholdField:  types.StringValue("response time"),
+			AggregateStatOp: types.StringValue("perc95"),
+			EntityStatOp:    types.StringValue("avg"),
+		},
+		{
+			Title:           types.StringValue("errors"),
+			ThresholdField:  types.StringValue("error_count"),
+			AggregateStatOp: types.StringValue("sum"),
+			EntityStatOp:    types.StringNull(),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		baseSearch  string
+		splitFields []string
+		expected    string
+	}{
+		{
+			name:       "no entity breakdown",
+			baseSearch: "index=web sourcetype=access",
+			expected: `search index=web sourcetype=access | head 100` +
+				` | stats perc95("response time") as metric_0, sum("error_count") as metric_1 | eval entity_count=0`,
+		},
+		{
+			name:        "entity breakdown",
+			baseSearch:  "  | makeresults count=10\n",
+			splitFields: []string{"host", "pod"},
+			expected: `| makeresults count=10 | head 100 | eval _preview_entity='host'."|".'pod'` +
+				` | stats avg("response time") as metric_0, sum("error_count") as metric_1 by _preview_entity` +
+				` | stats perc95(metric_0) as metric_0, sum(metric_1) as metric_1, count as entity_count`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := kpiBaseSearchPreviewQuery(test.baseSearch, test.splitFields, metrics, 100); actual != test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDataSourceKPIBaseSearchPreviewValidation(t *testing.T) {
+	providerBlock := `
+		provider "itsi" {
+			host     = "itsi.example.com"
+			user     = "user"
+			password = "password"
+			port     = 8089
+			timeout  = 20
+		}`
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: util.Dedent(providerBlock + `
+					data "itsi_kpi_base_search_preview" "test" {}
+				`),
+				ExpectError: regexp.MustCompile(`No attribute specified when one \(and only one\) of`),
+			},
+			{
+				Config: util.Dedent(providerBlock + `
+					data "itsi_kpi_base_search_preview" "test" {
+						base_search    = "| makeresults"
+						max_events     = 0
+
+						metric {
+							title            = "count"
+							threshold_field  = "count"
+							aggregate_statop = "sum"
+						}
+					}
+				`),
+				ExpectError: regexp.MustCompile(`Attribute max_events value must be between`),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceKPIBaseSearchPreviewLifecycle(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckResourceDestroy(resourceNameKPIBaseSearch, "TestAcc_DataSourceKPIBaseSearchPreviewLifecycle"),
+		Steps: []resource.TestStep{
+			// Step 1: preview an inline base search
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "entity_count", "0"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "results.#", "1"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "results.0.title", "events"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "results.0.value", "10"),
+				),
+			},
+			// Step 2: preview an existing base search split by entity
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "entity_count", "2"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "results.#", "1"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "results.0.title", "value"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_base_search_preview.test", "results.0.value", "30"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,518 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+const (
+	kpiThresholdPreviewDefaultEarliestTime = "-7d"
+	kpiThresholdPreviewDefaultTimeZone     = "UTC"
+	kpiThresholdPreviewMaxDataPoints       = 100000
+
+	kpiThresholdPreviewDefaultPolicy = "default_policy"
+)
+
+var (
+	_ datasource.DataSource              = &dataSourceKpiThresholdPreview{}
+	_ datasource.DataSourceWithConfigure = &dataSourceKpiThresholdPreview{}
+)
+
+type dataSourceKpiThresholdPreview struct {
+	client models.ClientConfig
+}
+
+type dataSourceKpiThresholdPreviewModel struct {
+	ThresholdTemplateID types.String                     `tfsdk:"threshold_template_id"`
+	Policy              []kpiThresholdPreviewPolicyModel `tfsdk:"policy"`
+	ServiceID           types.String                     `tfsdk:"service_id"`
+	KpiID               types.String                     `tfsdk:"kpi_id"`
+	EarliestTime        types.String                     `tfsdk:"earliest_time"`
+	LatestTime          types.String                     `tfsdk:"latest_time"`
+	TimeZone            types.String                     `tfsdk:"time_zone"`
+	DataPoints          types.Int64                      `tfsdk:"data_points"`
+	Results             []kpiThresholdPreviewResultModel `tfsdk:"results"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type kpiThresholdPreviewPolicyModel struct {
+	PolicyName        types.String             `tfsdk:"policy_name"`
+	PolicyType        types.String             `tfsdk:"policy_type"`
+	BaseSeverityLabel types.String             `tfsdk:"base_severity_label"`
+	TimeBlocks        []TimeBlockModel         `tfsdk:"time_blocks"`
+	ThresholdLevels   []KpiThresholdLevelModel `tfsdk:"threshold_levels"`
+}
+
+type kpiThresholdPreviewResultModel struct {
+	PolicyName     types.String             `tfsdk:"policy_name"`
+	DataPoints     types.Int64              `tfsdk:"data_points"`
+	Thresholds     map[string]types.Float64 `tfsdk:"thresholds"`
+	SeverityCounts map[string]types.Int64   `tfsdk:"severity_counts"`
+}
+
+type kpiThresholdPreviewPoint struct {
+	time  time.Time
+	value float64
+}
+
+func NewDataSourceKpiThresholdPreview() datasource.DataSource {
+	return &dataSourceKpiThresholdPreview{}
+}
+
+func (d *dataSourceKpiThresholdPreview) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	configureDataSourceClient(ctx, datasourceNameKPIThresholdPreview, req, &d.client, resp)
+}
+
+func (d *dataSourceKpiThresholdPreview) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	configureDataSourceMetadata(req, resp, datasourceNameKPIThresholdPreview)
+}
+
+func (d *dataSourceKpiThresholdPreview) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: util.Dedent(`
+			Use this data source to preview how often each severity level of a KPI threshold template would have triggered for a KPI.
+			The aggregate alert values of the KPI are read from the ITSI summary metrics index over the lookback window,
+			each data point is assigned to the policy whose time block covers it, and counted against the policy's aggregate threshold levels.
+			Adaptive policies (stdev, quantile, range and percentage) are trained on the data points of the same lookback window.
+		`),
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+			"policy": schema.ListNestedBlock{
+				MarkdownDescription: "Inline threshold policies to preview, instead of those of an existing threshold template.",
+				NestedObject: schema.NestedBlockObject{
+					Blocks: map[string]schema.Block{
+						"time_blocks": schema.ListNestedBlock{
+							MarkdownDescription: "Time blocks during which the policy applies. The default_policy applies whenever no other policy does.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"cron": schema.StringAttribute{
+										MarkdownDescription: "Cron expression of the start of the time block.",
+										Required:            true,
+										Validators:          []validator.String{stringvalidatorIsCron()},
+									},
+									"interval": schema.Int64Attribute{
+										MarkdownDescription: "Duration of the time block, in minutes.",
+										Required:            true,
+										Validators:          []validator.Int64{int64validator.AtLeast(1)},
+									},
+								},
+							},
+						},
+						"threshold_levels": schema.ListNestedBlock{
+							MarkdownDescription: "Aggregate threshold levels of the policy.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"severity_label": schema.StringAttribute{
+										MarkdownDescription: "Severity label assigned for this threshold level.",
+										Required:            true,
+										Validators:          []validator.String{stringvalidator.OneOf("info", "critical", "high", "medium", "low", "normal")},
+									},
+									"threshold_value": schema.Float64Attribute{
+										MarkdownDescription: "Value for threshold level. Only used by static policies.",
+										Optional:            true,
+									},
+									"dynamic_param": schema.Float64Attribute{
+										MarkdownDescription: "Value of the adaptive threshold parameter. Only used by adaptive policies.",
+										Optional:            true,
+									},
+								},
+							},
+						},
+					},
+					Attributes: map[string]schema.Attribute{
+						"policy_name": schema.StringAttribute{
+							MarkdownDescription: "Internal key value for policy.",
+							Required:            true,
+						},
+						"policy_type": schema.StringAttribute{
+							MarkdownDescription: "The algorithm of the policy: static, stdev, quantile, range or percentage.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("static", "stdev", "quantile", "range", "percentage"),
+							},
+						},
+						"base_severity_label": schema.StringAttribute{
+							MarkdownDescription: fmt.Sprintf("Severity of the data points below all threshold levels. Defaults to `%s`.", BASE_SEVERITY_LABEL_DEFAULT),
+							Optional:            true,
+							Validators:          []validator.String{stringvalidator.OneOf("info", "critical", "high", "medium", "low", "normal")},
+						},
+					},
+				},
+			},
+		},
+		Attributes: map[string]schema.Attribute{
+			"threshold_template_id": schema.StringAttribute{
+				MarkdownDescription: "ID of an existing KPI threshold template to preview. Exactly one of `threshold_template_id` and `policy` must be set.",
+				Optional:            true,
+			},
+			"service_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the service of the KPI.",
+				Required:            true,
+			},
+			"kpi_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the KPI whose historical data the thresholds are evaluated against.",
+				Required:            true,
+			},
+			"earliest_time": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Earliest time of the lookback window. Defaults to `%s`.", kpiThresholdPreviewDefaultEarliestTime),
+				Optional:            true,
+				Validators:          []validator.String{stringvalidatorIsSplunkRelativeTime()},
+			},
+			"latest_time": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Latest time of the lookback window. Defaults to `%s`.", searchDefaultLatestTime),
+				Optional:            true,
+				Validators:          []validator.String{stringvalidatorIsSplunkRelativeTime()},
+			},
+			"time_zone": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("IANA time zone the time block cron expressions are evaluated in. Defaults to `%s`.", kpiThresholdPreviewDefaultTimeZone),
+				Optional:            true,
+			},
+			"data_points": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Number of 1 minute data points of the KPI in the lookback window (at most %d).", kpiThresholdPreviewMaxDataPoints),
+				Computed:            true,
+			},
+			"results": schema.ListNestedAttribute{
+				MarkdownDescription: "Preview of every policy, sorted by policy name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"policy_name": schema.StringAttribute{
+							MarkdownDescription: "Internal key value for policy.",
+							Computed:            true,
+						},
+						"data_points": schema.Int64Attribute{
+							MarkdownDescription: "Number of data points the policy applies to.",
+							Computed:            true,
+						},
+						"thresholds": schema.MapAttribute{
+							MarkdownDescription: "Threshold value of every severity level. Null for adaptive policies without data points.",
+							ElementType:         types.Float64Type,
+							Computed:            true,
+						},
+						"severity_counts": schema.MapAttribute{
+							MarkdownDescription: "Number of data points that would have triggered every severity level, including the base severity.",
+							ElementType:         types.Int64Type,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *dataSourceKpiThresholdPreview) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read KPI threshold preview data source")
+	var config dataSourceKpiThresholdPreviewModel
+
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &config)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := config.Timeouts.Read(ctx, tftimeout.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	timeZone := kpiThresholdPreviewDefaultTimeZone
+	if !config.TimeZone.IsNull() {
+		timeZone = config.TimeZone.ValueString()
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("time_zone"), "Invalid time zone", err.Error())
+		return
+	}
+
+	if config.ThresholdTemplateID.IsNull() == (len(config.Policy) == 0) {
+		resp.Diagnostics.AddAttributeError(path.Root("threshold_template_id"), "Invalid threshold preview configuration",
+			"Exactly one of threshold_template_id and policy must be set.")
+		return
+	}
+
+	policies := config.Policy
+	if !config.ThresholdTemplateID.IsNull() {
+		policies, diags = d.thresholdTemplatePolicies(ctx, config.ThresholdTemplateID.ValueString())
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	earliestTime, latestTime := kpiThresholdPreviewDefaultEarliestTime, searchDefaultLatestTime
+	if !config.EarliestTime.IsNull() {
+		earliestTime = config.EarliestTime.ValueString()
+	}
+	if !config.LatestTime.IsNull() {
+		latestTime = config.LatestTime.ValueString()
+	}
+
+	points, diags := kpiSummaryDataPoints(ctx, d.client, config.ServiceID.ValueString(), config.KpiID.ValueString(), earliestTime, latestTime, int(readTimeout.Seconds()))
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := config
+	state.DataPoints = types.Int64Value(int64(len(points)))
+	state.Results, diags = kpiThresholdPreviewEvaluate(policies, points, loc)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading KPI threshold preview data source", map[string]any{"success": true})
+}
+
+// thresholdTemplatePolicies returns the aggregate threshold policies of an existing threshold template.
+func (d *dataSourceKpiThresholdPreview) thresholdTemplatePolicies(ctx context.Context, id string) (policies []kpiThresholdPreviewPolicyModel, diags diag.Diagnostics) {
+	b, err := kpiThresholdTemplateBase(d.client, id, "").Find(ctx)
+	if err != nil {
+		diags.AddError("Unable to read KPI Threshold template object", err.Error())
+		return
+	}
+	if b == nil {
+		diags.AddAttributeError(path.Root("threshold_template_id"), "KPI Threshold template not found",
+			fmt.Sprintf("KPI Threshold template with id %q not found", id))
+		return
+	}
+
+	var template modelKpiThresholdTemplate
+	if diags.Append(populateKpiThresholdTemplateModel(ctx, b, &template)...); diags.HasError() {
+		return
+	}
+
+	for _, policy := range template.TimeVariateThresholdsSpecification.Policies {
+		// policies other than the default one only apply to time variate thresholds
+		if !template.TimeVariateThresholds.ValueBool() && policy.PolicyName.ValueString() != kpiThresholdPreviewDefaultPolicy {
+			continue
+		}
+		policies = append(policies, kpiThresholdPreviewPolicyModel{
+			PolicyName:        policy.PolicyName,
+			PolicyType:        policy.PolicyType,
+			BaseSeverityLabel: policy.AggregateThresholds.BaseSeverityLabel,
+			TimeBlocks:        policy.TimeBlocks,
+			ThresholdLevels:   policy.AggregateThresholds.ThresholdLevels,
+		})
+	}
+	return
+}
+
+// kpiSummaryDataPoints returns the aggregate alert values of a KPI from the ITSI summary metrics index, at a 1 minute resolution.
+func kpiSummaryDataPoints(ctx context.Context, client models.ClientConfig, serviceID, kpiID, earliestTime, latestTime string, timeout int) (points []kpiThresholdPreviewPoint, diags diag.Diagnostics) {
+	query := fmt.Sprintf(util.Dedent(`
+		| mstats latest(alert_value) AS alert_value
+		WHERE `+"`get_itsi_summary_metrics_index`"+`
+		AND itsi_service_id=%s AND itsi_kpi_id=%s
+		AND is_filled_gap_event!=1 AND is_null_alert_value=0
+		`+"`metrics_service_level_kpi_only`"+` by itsi_kpi_id span=1m
+		| eval time=_time
+		| table time, alert_value
+		| head %d
+	`), strconv.Quote(serviceID), strconv.Quote(kpiID), kpiThresholdPreviewMaxDataPoints)
+
+	splunkreq := NewSplunkRequest(client, []SplunkSearch{{
+		Query:          query,
+		User:           searchDefaultUser,
+		App:            "itsi",
+		EarliestTime:   earliestTime,
+		LatestTime:     latestTime,
+		AllowNoResults: true,
+		Timeout:        timeout,
+	}}, 1, nil, false, " ")
+
+	rows, diags := splunkreq.Run(ctx)
+	if diags.HasError() {
+		return
+	}
+
+	for _, row := range rows {
+		t, okTime := splunkValueFloat64(row["time"])
+		value, okValue := splunkValueFloat64(row["alert_value"])
+		if !okTime || !okValue {
+			continue
+		}
+		sec, frac := math.Modf(t)
+		points = append(points, kpiThresholdPreviewPoint{time: time.Unix(int64(sec), int64(frac*1e9)), value: value})
+	}
+	return
+}
+
+// kpiThresholdPolicyValues assigns every data point to the policy whose time block covers it, or to the default policy.
+// It returns the values of the data points of every policy, in the order of the policies.
+func kpiThresholdPolicyValues(policies []kpiThresholdPreviewPolicyModel, points []kpiThresholdPreviewPoint, loc *time.Location) (values [][]float64, diags diag.Diagnostics) {
+	sort.Slice(points, func(i, j int) bool { return points[i].time.Before(points[j].time) })
+
+	// start times of the time blocks of every policy, from the earliest one that may still cover the first data point
+	type timeBlock struct {
+		starts   []time.Time
+		interval time.Duration
+	}
+	blocks := make([][]timeBlock, len(policies))
+	for i, policy := range policies {
+		if policy.PolicyName.ValueString() == kpiThresholdPreviewDefaultPolicy || len(points) == 0 {
+			continue
+		}
+		for _, tb := range policy.TimeBlocks {
+			schedule, err := util.ParseCron(tb.Cron.ValueString())
+			if err != nil {
+				diags.AddError(fmt.Sprintf("Invalid time block of policy %q", policy.PolicyName.ValueString()), err.Error())
+				return
+			}
+			block := timeBlock{interval: time.Duration(tb.Interval.ValueInt64()) * time.Minute}
+			for t := points[0].time.Truncate(time.Minute).Add(-block.interval); !t.After(points[len(points)-1].time); t = t.Add(time.Minute) {
+				if schedule.Matches(t.In(loc)) {
+					block.starts = append(block.starts, t)
+				}
+			}
+			blocks[i] = append(blocks[i], block)
+		}
+	}
+
+	activePolicy := func(t time.Time) int {
+		for i := range policies {
+			for _, block := range blocks[i] {
+				j := sort.Search(len(block.starts), func(j int) bool { return block.starts[j].After(t) })
+				if j > 0 && t.Before(block.starts[j-1].Add(block.interval)) {
+					return i
+				}
+			}
+		}
+		for i, policy := range policies {
+			if policy.PolicyName.ValueString() == kpiThresholdPreviewDefaultPolicy {
+				return i
+			}
+		}
+		return -1
+	}
+
+	values = make([][]float64, len(policies))
+	for _, p := range points {
+		if i := activePolicy(p.time); i >= 0 {
+			values[i] = append(values[i], p.value)
+		}
+	}
+	return
+}
+
+// kpiThresholdPreviewEvaluate assigns every data point to a policy and counts the severity levels the data points would have triggered.
+func kpiThresholdPreviewEvaluate(policies []kpiThresholdPreviewPolicyModel, points []kpiThresholdPreviewPoint, loc *time.Location) (results []kpiThresholdPreviewResultModel, diags diag.Diagnostics) {
+	policies = append([]kpiThresholdPreviewPolicyModel{}, policies...)
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].PolicyName.ValueString() < policies[j].PolicyName.ValueString()
+	})
+
+	values, diags := kpiThresholdPolicyValues(policies, points, loc)
+	if diags.HasError() {
+		return
+	}
+
+	results = []kpiThresholdPreviewResultModel{}
+	for i, policy := range policies {
+		result := kpiThresholdPreviewResultModel{
+			PolicyName:     policy.PolicyName,
+			DataPoints:     types.Int64Value(int64(len(values[i]))),
+			Thresholds:     map[string]types.Float64{},
+			SeverityCounts: map[string]types.Int64{},
+		}
+
+		baseSeverity := policy.BaseSeverityLabel.ValueString()
+		if baseSeverity == "" {
+			baseSeverity = BASE_SEVERITY_LABEL_DEFAULT
+		}
+		counts := map[string]int64{baseSeverity: 0}
+
+		type level struct {
+			severity  string
+			threshold float64
+		}
+		levels := []level{}
+		for _, l := range policy.ThresholdLevels {
+			counts[l.SeverityLabel.ValueString()] = 0
+			threshold, ok := kpiThresholdPreviewThreshold(policy.PolicyType.ValueString(), l, values[i])
+			if !ok {
+				result.Thresholds[l.SeverityLabel.ValueString()] = types.Float64Null()
+				continue
+			}
+			result.Thresholds[l.SeverityLabel.ValueString()] = types.Float64Value(threshold)
+			levels = append(levels, level{l.SeverityLabel.ValueString(), threshold})
+		}
+		sort.SliceStable(levels, func(i, j int) bool { return levels[i].threshold < levels[j].threshold })
+
+		for _, v := range values[i] {
+			severity := baseSeverity
+			for _, l := range levels {
+				if v >= l.threshold {
+					severity = l.severity
+				}
+			}
+			counts[severity]++
+		}
+		for severity, count := range counts {
+			result.SeverityCounts[severity] = types.Int64Value(count)
+		}
+
+		results = append(results, result)
+	}
+	return
+}
+
+// kpiThresholdPreviewThreshold returns the threshold value of a level of a policy, trained on the policy's data points for adaptive policies.
+func kpiThresholdPreviewThreshold(policyType string, l KpiThresholdLevelModel, values []float64) (float64, bool) {
+	if policyType == "static" {
+		return l.ThresholdValue.ValueFloat64(), true
+	}
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	param := l.DynamicParam.ValueFloat64()
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+
+	switch policyType {
+	case "stdev":
+		var variance float64
+		for _, v := range sorted {
+			variance += (v - mean) * (v - mean)
+		}
+		return mean + param*math.Sqrt(variance/float64(len(sorted))), true
+	case "quantile":
+		// quantiles may be given either as fractions or as percentiles
+		if param > 1 {
+			param /= 100
+		}
+		param = math.Max(0, math.Min(1, param))
+		return sorted[int(math.Round(param*float64(len(sorted)-1)))], true
+	case "range":
+		return sorted[0] + param*(sorted[len(sorted)-1]-sorted[0]), true
+	case "percentage":
+		return mean * (1 + param/100), true
+	}
+	return 0, false
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDataSourceKPIThresholdPreviewSchema(t *testing.T) {
+	testDataSourceSchema(t, new(dataSourceKpiThresholdPreview))
+}
+
+func TestKpiThresholdPreviewEvaluate(t *testing.T) {
+	level := func(severity string, thresholdValue, dynamicParam float64) KpiThresholdLevelModel {
+		return KpiThresholdLevelModel{
+			SeverityLabel:  types.StringValue(severity),
+			ThresholdValue: types.Float64Value(thresholdValue),
+			DynamicParam:   types.Float64Value(dynamicParam),
+		}
+	}
+
+	policies := []kpiThresholdPreviewPolicyModel{
+		{
+			PolicyName: types.StringValue("default_policy"),
+			PolicyType: types.StringValue("static"),
+			ThresholdLevels: []KpiThresholdLevelModel{
+				level("medium", 50, 0),
+				level("critical", 90, 0),
+			},
+		},
+		{
+			// 09:00-11:00 every day
+			PolicyName:        types.StringValue("business_hours"),
+			PolicyType:        types.StringValue("range"),
+			BaseSeverityLabel: types.StringValue("info"),
+			TimeBlocks: []TimeBlockModel{
+				{Cron: types.StringValue("0 9 * * *"), Interval: types.Int64Value(120)},
+			},
+			ThresholdLevels: []KpiThresholdLevelModel{
+				level("high", 0, 0.5),
+			},
+		},
+	}
+
+	start := time.Date(2024, time.January, 1, 8, 0, 0, 0, time.UTC)
+	points := []kpiThresholdPreviewPoint{}
+	// 08:00-08:59: 0..59, 09:00-10:59: 0..119, 11:00-11:59: 60..119
+	for i := 0; i < 60; i++ {
+		points = append(points, kpiThresholdPreviewPoint{time: start.Add(time.Duration(i) * time.Minute), value: float64(i)})
+	}
+	for i := 0; i < 120; i++ {
+		points = append(points, kpiThresholdPreviewPoint{time: start.Add(time.Duration(60+i) * time.Minute), value: float64(i)})
+	}
+	for i := 60; i < 120; i++ {
+		points = append(points, kpiThresholdPreviewPoint{time: start.Add(time.Duration(120+i) * time.Minute), value: float64(i)})
+	}
+
+	results, diags := kpiThresholdPreviewEvaluate(policies, points, time.UTC)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	expected := []struct {
+		policy     string
+		dataPoints int64
+		thresholds map[string]float64
+		counts     map[string]int64
+	}{
+		{
+			policy:     "business_hours",
+			dataPoints: 120,
+			thresholds: map[string]float64{"high": 59.5},
+			counts:     map[string]int64{"info": 60, "high": 60},
+		},
+		{
+			policy:     "default_policy",
+			dataPoints: 120,
+			thresholds: map[string]float64{"medium": 50, "critical": 90},
+			// 0..59: 50 normal, 10 medium; 60..119: 30 medium, 30 critical
+			counts: map[string]int64{"normal": 50, "medium": 40, "critical": 30},
+		},
+	}
+
+	for i, e := range expected {
+		r := results[i]
+		if r.PolicyName.ValueString() != e.policy {
+			t.Errorf("expected policy %s, got %s", e.policy, r.PolicyName.ValueString())
+		}
+		if r.DataPoints.ValueInt64() != e.dataPoints {
+			t.Errorf("%s: expected %d data points, got %d", e.policy, e.dataPoints, r.DataPoints.ValueInt64())
+		}
+		for severity, threshold := range e.thresholds {
+			if r.Thresholds[severity].ValueFloat64() != threshold {
+				t.Errorf("%s: expected %s threshold %v, got %v", e.policy, severity, threshold, r.Thresholds[severity])
+			}
+		}
+		if len(r.SeverityCounts) != len(e.counts) {
+			t.Errorf("%s: expected severity counts %v, got %v", e.policy, e.counts, r.SeverityCounts)
+		}
+		for severity, count := range e.counts {
+			if r.SeverityCounts[severity].ValueInt64() != count {
+				t.Errorf("%s: expected %d %s data points, got %v", e.policy, count, severity, r.SeverityCounts[severity])
+			}
+		}
+	}
+}
+
+func TestAccDataSourceKPIThresholdPreviewLifecycle(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckResourceDestroy(resourceNameService, "TestAcc_DataSourceKPIThresholdPreviewLifecycle_service"),
+			testAccCheckResourceDestroy(resourceNameKPIThresholdTemplate, "TestAcc_DataSourceKPIThresholdPreviewLifecycle_threshold_template"),
+			testAccCheckResourceDestroy(resourceNameKPIBaseSearch, "TestAcc_DataSourceKPIThresholdPreviewLifecycle_base_search"),
+		),
+		Steps: []resource.TestStep{
+			// Step 1: preview an existing threshold template
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.itsi_kpi_threshold_preview.test", "data_points"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_threshold_preview.test", "results.#", "1"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_threshold_preview.test", "results.0.policy_name", "default_policy"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_threshold_preview.test", "results.0.thresholds.critical", "90"),
+					resource.TestCheckResourceAttrSet("data.itsi_kpi_threshold_preview.test", "results.0.severity_counts.normal"),
+				),
+			},
+			// Step 2: preview inline policies
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.itsi_kpi_threshold_preview.test", "results.#", "2"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_threshold_preview.test", "results.0.policy_name", "default_policy"),
+					resource.TestCheckResourceAttr("data.itsi_kpi_threshold_preview.test", "results.1.policy_name", "weekend"),
+					resource.TestCheckResourceAttrSet("data.itsi_kpi_threshold_preview.test", "results.1.severity_counts.high"),
+				),
+			},
+		},
+	})
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -18,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/lestrrat-go/backoff/v2"
 
 	"maps"
 
@@ -42,7 +44,7 @@ var (
 	_ datasource.DataSource              = &dataSourceSplunkSearch{}
 	_ datasource.DataSourceWithConfigure = &dataSourceSplunkSearch{}
 
-	splunkSearchLimiter *util.Limiter
+	splunkSearchLimiter util.ConcurrencyLimiter
 	splunkSearchClients models.IHttpClients
 )
 
@@ -54,6 +56,19 @@ func InitSplunkSearchLimiter(concurrency int) {
 	splunkSearchLimiter = util.NewLimiter(concurrency)
 }
 
+// InitSplunkSearchAdaptiveLimiter bounds the concurrency of Splunk searches with a limit adapting to search head throttling.
+func InitSplunkSearchAdaptiveLimiter(minConcurrency, maxConcurrency int) {
+	splunkSearchLimiter = util.NewAdaptiveLimiter(minConcurrency, maxConcurrency)
+}
+
+// searchLimiter returns the limiter of the Splunk searches of the client config.
+func searchLimiter(client models.ClientConfig) util.ConcurrencyLimiter {
+	if client.SearchLimiter != nil {
+		return client.SearchLimiter
+	}
+	return splunkSearchLimiter
+}
+
 type searchModel struct {
 	Query               types.String `tfsdk:"query"`
 	SplunkUser          types.String `tfsdk:"splunk_user"`
@@ -347,25 +362,19 @@ func (sr *SplunkRequest) Run(ctx context.Context) (results []map[string]splunk.V
 }
 
 func (sr *SplunkRequest) Search(ctx context.Context, s SplunkSearch) (results []map[string]splunk.Value, diags diag.Diagnostics) {
-	splunkSearchLimiter.Acquire()
+	searchLimiter := searchLimiter(sr.client)
+	searchLimiter.Acquire()
 	sr.limiter.Acquire()
 
-	defer splunkSearchLimiter.Release()
+	defer searchLimiter.Release()
 	defer sr.limiter.Release()
 
-	client := sr.client
-	client.Timeout = s.Timeout
-
-	conn := splunk.SplunkConnection{
-		BearerToken: sr.client.BearerToken,
-		Username:    sr.client.User,
-		Password:    sr.client.Password,
-		BaseURL:     fmt.Sprintf("https://%s:%v", sr.client.Host, sr.client.Port),
-		SplunkApp:   s.App,
-		SplunkUser:  s.User,
-
-		HttpClient: splunkSearchClients.Get(client).(*http.Client),
+	client, err := sr.client.WithCredentials(ctx)
+	if err != nil {
+		diags.AddError("Couldn't retrieve Splunk credentials", err.Error())
+		return
 	}
+	client.Timeout = s.Timeout
 
 	params := map[string]string{
 		"earliest_time":         s.EarliestTime,
@@ -373,15 +382,62 @@ func (sr *SplunkRequest) Search(ctx context.Context, s SplunkSearch) (results []
 		"allow_partial_results": strconv.FormatBool(s.AllowPartialResults),
 	}
 
-	if sr.client.BearerToken == "" {
-		_, err := conn.Login()
-		if err != nil {
-			diags.AddError("Couldn't login to Splunk", err.Error())
-			return
-		}
+	var loginErr error
+	search := func(client models.ClientConfig) (rows []splunk.Row, err error) {
+		err = withRefreshedCredentials(ctx, client, func(client models.ClientConfig) error {
+			var conn splunk.SplunkConnection
+			var sessionKey string
+			if conn, sessionKey, loginErr = splunkConnection(ctx, client, s.App, s.User); loginErr != nil {
+				return loginErr
+			}
+
+			rows, _, err = conn.Search(ctx, client.RetryPolicy, s.Query, params)
+			if errors.Is(err, splunk.ErrUnauthorized) && sessionKey != "" {
+				// the session key has expired or has been revoked: log in again and repeat the search
+				if loginErr = renewSplunkSession(ctx, client, &conn, sessionKey); loginErr != nil {
+					return loginErr
+				}
+				rows, _, err = conn.Search(ctx, client.RetryPolicy, s.Query, params)
+			}
+			return err
+		})
+		return
 	}
 
-	rows, _, err := conn.Search(ctx, sr.client.RetryPolicy, s.Query, params)
+	// searches retry failed requests themselves; failed logins are retried here, like failed ITOA requests
+	retryCtx := ctx
+	if client.RetryMaxElapsedTime > 0 {
+		var cancelRetries context.CancelFunc
+		retryCtx, cancelRetries = context.WithTimeout(ctx, client.RetryMaxElapsedTime)
+		defer cancelRetries()
+	}
+	bo := client.RetryPolicy.Start(retryCtx)
+
+	var rows []splunk.Row
+	for backoff.Continue(bo) {
+		for failovers := 0; ; failovers++ {
+			// searches are spread across the healthy search head members
+			endpoint := client.AnyEndpoint(ctx)
+			rows, err = search(endpoint)
+			failed := errors.Is(err, splunk.ErrUnavailable)
+			if loginErr != nil {
+				tflog.Warn(ctx, fmt.Sprintf("Splunk search on %s:%d: %s", endpoint.Host, endpoint.Port, loginErr.Error()))
+				failed = models.EndpointFailed(models.LoginStatusCode(loginErr), loginErr)
+			}
+			if !failed || !endpoint.MarkEndpointDown(ctx, err) || failovers >= len(client.Hosts)-1 {
+				break
+			}
+		}
+		// logins failing with a transport error, without a status code, are always retried
+		if statusCode := models.LoginStatusCode(loginErr); loginErr == nil || (statusCode != 0 && !client.RetryableStatus(statusCode)) {
+			break
+		}
+	}
+	searchLimiter.Feedback(errors.Is(err, splunk.ErrThrottled))
+	if loginErr != nil {
+		diags.AddError("Couldn't login to Splunk", loginErr.Error())
+		return
+	}
 	if err != nil {
 		diags.AddError("Splunk search failed", err.Error())
 		return
@@ -416,6 +472,60 @@ func (sr *SplunkRequest) Search(ctx context.Context, s SplunkSearch) (results []
 	return
 }
 
+// splunkConnection returns a connection to the search head member targeted by the client config.
+// Unless a bearer token is configured, the connection is authenticated with the session key of the client config credentials,
+// which is also returned, so that it can be renewed once it expires.
+func splunkConnection(ctx context.Context, client models.ClientConfig, app, user string) (conn splunk.SplunkConnection, sessionKey string, err error) {
+	conn = splunk.SplunkConnection{
+		BearerToken: client.BearerToken,
+		Username:    client.User,
+		Password:    client.Password,
+		BaseURL:     fmt.Sprintf("https://%s:%v", client.Host, client.Port),
+		SplunkApp:   app,
+		SplunkUser:  user,
+
+		HttpClient: splunkSearchClients.Get(client).(*http.Client),
+
+		RetryableStatus:     client.RetryableStatus,
+		RetryMaxElapsedTime: client.RetryMaxElapsedTime,
+	}
+
+	if client.UsesSessionAuth() {
+		if sessionKey, err = models.SessionKey(ctx, client); err != nil {
+			return
+		}
+		conn.SetSessionKey(splunk.SessionKey{Value: sessionKey})
+	}
+	return
+}
+
+// renewSplunkSession logs in again after a request of the connection was rejected with the stale session key.
+func renewSplunkSession(ctx context.Context, client models.ClientConfig, conn *splunk.SplunkConnection, stale string) error {
+	sessionKey, err := models.RenewSessionKey(ctx, client, stale)
+	if err != nil {
+		return err
+	}
+	conn.SetSessionKey(splunk.SessionKey{Value: sessionKey})
+	return nil
+}
+
+// withRefreshedCredentials calls request with the client config, and once more with the credentials retrieved again
+// from its credential source if they are rejected: the credentials cached by the credential source may have been rotated.
+func withRefreshedCredentials(ctx context.Context, client models.ClientConfig, request func(models.ClientConfig) error) error {
+	err := request(client)
+	if !errors.Is(err, splunk.ErrUnauthorized) && models.LoginStatusCode(err) != http.StatusUnauthorized {
+		return err
+	}
+	if !client.InvalidateCredentials() {
+		return err
+	}
+	refreshed, refreshErr := client.WithCredentials(ctx)
+	if refreshErr != nil {
+		return refreshErr
+	}
+	return request(refreshed)
+}
+
 func (sr *SplunkRequest) ID() string {
 	b, _ := json.Marshal(sr.searches)
 	return util.Sha256(b)
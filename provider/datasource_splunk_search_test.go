@@ -1,9 +1,16 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/tivo/terraform-provider-splunk-itsi/credentials"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider/splunk"
 	"github.com/tivo/terraform-provider-splunk-itsi/util"
 )
 
@@ -11,6 +18,65 @@ func TestDataSourceSplunkSearchSchema(t *testing.T) {
 	testDataSourceSchema(t, new(dataSourceSplunkSearch))
 }
 
+// rotatingSource is a caching credential source, whose token has been rotated since it was cached.
+type rotatingSource struct {
+	cached, current string
+}
+
+func (s *rotatingSource) Credentials(context.Context) (credentials.Credentials, error) {
+	return credentials.Credentials{Token: s.cached}, nil
+}
+
+func (s *rotatingSource) Invalidate(rejected credentials.Credentials) {
+	if rejected.Token == s.cached {
+		s.cached = s.current
+	}
+}
+
+func TestWithRefreshedCredentials(t *testing.T) {
+	ctx := context.Background()
+	for _, rejected := range []error{
+		fmt.Errorf("splunk search error: %w", splunk.ErrUnauthorized),
+		&models.LoginError{StatusCode: http.StatusUnauthorized, Err: errors.New("401 Unauthorized")},
+	} {
+		source := &rotatingSource{cached: "old", current: "new"}
+		client, err := models.ClientConfig{CredentialSource: source}.WithCredentials(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tokens := []string{}
+		err = withRefreshedCredentials(ctx, client, func(client models.ClientConfig) error {
+			tokens = append(tokens, client.BearerToken)
+			if client.BearerToken != source.current {
+				return rejected
+			}
+			return nil
+		})
+		if err != nil || len(tokens) != 2 || tokens[1] != "new" {
+			t.Errorf("%v: expected the request to be repeated once with the rotated token, got %v (%v)", rejected, tokens, err)
+		}
+	}
+
+	// other errors, and credentials that are not retrieved from a caching source, are not retried
+	for _, tc := range []struct {
+		client models.ClientConfig
+		err    error
+	}{
+		{models.ClientConfig{CredentialSource: &rotatingSource{cached: "old", current: "new"}}, splunk.ErrUnavailable},
+		{models.ClientConfig{BearerToken: "static"}, splunk.ErrUnauthorized},
+	} {
+		calls := 0
+		err := withRefreshedCredentials(ctx, tc.client, func(models.ClientConfig) error {
+			calls++
+			return tc.err
+		})
+		if !errors.Is(err, tc.err) || calls != 1 {
+			t.Errorf("%v: expected 1 call, got %d (%v)", tc.err, calls, err)
+		}
+	}
+}
+
 func TestAccDataSplunkSearch(t *testing.T) {
 	t.Parallel()
 	resource.Test(t, resource.TestCase{
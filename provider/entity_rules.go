@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+/*
+	Entity rules are used by ITSI objects (e.g. services or entity management policies)
+	to define a set of entities. Rule groups are combined using OR operator,
+	while the rules within a group are combined using AND operator.
+*/
+
+func entityRulesSchema() schema.SetNestedBlock {
+	return schema.SetNestedBlock{
+		Description: "A set of rules within the rule group, which are combined using OR operator.",
+		NestedObject: schema.NestedBlockObject{
+			Blocks: map[string]schema.Block{
+				"rule": schema.SetNestedBlock{
+					Description: "A set of rules within the rule group, which are combined using AND operator.",
+					NestedObject: schema.NestedBlockObject{
+						Attributes: map[string]schema.Attribute{
+							"field": schema.StringAttribute{
+								Required:    true,
+								Description: "The field in the entity definition to compare values to evaluate this rule.",
+							},
+							"field_type": schema.StringAttribute{
+								Required:    true,
+								Description: "Takes values alias, info or title specifying in which category of fields the field attribute is located.",
+								Validators: []validator.String{
+									stringvalidator.OneOf("alias", "entity_type", "info", "title"),
+								},
+							},
+							"rule_type": schema.StringAttribute{
+								Required:    true,
+								Description: "Takes values not or matches to indicate whether it's an inclusion or exclusion rule.",
+								Validators: []validator.String{
+									stringvalidator.OneOf("matches", "not"),
+								},
+							},
+							"value": schema.StringAttribute{
+								Required:    true,
+								Description: "Values to evaluate in the rule. To specify multiple values, separate them with a comma. Values are not case sensitive.",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildEntityRules(entityRules []EntityRuleState) (itsiEntityRules []map[string]any, diags diag.Diagnostics) {
+	itsiEntityRules = []map[string]any{}
+	for _, entityRuleGroup := range entityRules {
+		itsiEntityGroupRules := []map[string]any{}
+		if len(entityRuleGroup.Rule) == 0 {
+			continue
+		}
+
+		for _, entityRule := range entityRuleGroup.Rule {
+			rule := map[string]any{}
+			diags.Append(marshalBasicTypesByTag("json", &entityRule, rule)...)
+			itsiEntityGroupRules = append(itsiEntityGroupRules, rule)
+		}
+
+		itsiEntityRuleGroup := map[string]any{"rule_condition": "AND", "rule_items": itsiEntityGroupRules}
+		itsiEntityRules = append(itsiEntityRules, itsiEntityRuleGroup)
+	}
+	return
+}
+
+func parseEntityRules(itsiEntityRules any) (res []EntityRuleState, diags diag.Diagnostics) {
+	res = []EntityRuleState{}
+	entityRules, err := UnpackSlice[map[string]any](itsiEntityRules)
+	if err != nil {
+		diags.AddError("Unable to unpack entity rules", err.Error())
+		return
+	}
+
+	for _, entityRuleAndSet := range entityRules {
+		ruleState := EntityRuleState{}
+		ruleSet := []RuleState{}
+		ruleItems, err := UnpackSlice[map[string]any](entityRuleAndSet["rule_items"])
+		if err != nil {
+			diags.AddError("Unable to unpack entity rule items", err.Error())
+			return
+		}
+		for _, ruleItem := range ruleItems {
+			ruleTF := RuleState{}
+			diags.Append(unmarshalBasicTypesByTag("json", ruleItem, &ruleTF)...)
+			ruleSet = append(ruleSet, ruleTF)
+		}
+		ruleState.Rule = ruleSet
+		res = append(res, ruleState)
+	}
+
+	return
+}
+
+// entityRulesFilter translates entity rules into an ITOA entity filter expression.
+// Entity type rules are resolved into entity type keys, using the entity type titles provided in the rule values.
+func entityRulesFilter(ctx context.Context, client models.ClientConfig, entityRules []EntityRuleState) (filter string, diags diag.Diagnostics) {
+	groups := []any{}
+	for _, entityRuleGroup := range entityRules {
+		conditions := []any{}
+		for _, rule := range entityRuleGroup.Rule {
+			var field string
+			var patterns []any
+
+			for _, value := range strings.Split(rule.Value.ValueString(), ",") {
+				patterns = append(patterns, map[string]string{"$regex": util.WildcardToRegexpStr(strings.TrimSpace(value))})
+			}
+
+			switch rule.FieldType.ValueString() {
+			case "title":
+				field = "title"
+			case "entity_type":
+				field = "entity_type_ids"
+				keys, d := entityTypeKeys(ctx, client, patterns)
+				if diags.Append(d...); diags.HasError() {
+					return
+				}
+				patterns = []any{map[string]any{"$in": keys}}
+			default:
+				field = rule.Field.ValueString()
+			}
+
+			valueConditions := make([]any, len(patterns))
+			for i, pattern := range patterns {
+				if rule.RuleType.ValueString() == "not" {
+					valueConditions[i] = map[string]any{field: map[string]any{"$not": pattern}}
+				} else {
+					valueConditions[i] = map[string]any{field: pattern}
+				}
+			}
+			if rule.RuleType.ValueString() == "not" {
+				// an entity must not match any of the values
+				conditions = append(conditions, map[string]any{"$and": valueConditions})
+			} else {
+				conditions = append(conditions, map[string]any{"$or": valueConditions})
+			}
+		}
+		if len(conditions) > 0 {
+			groups = append(groups, map[string]any{"$and": conditions})
+		}
+	}
+
+	if len(groups) == 0 {
+		return
+	}
+
+	by, err := json.Marshal(map[string]any{"$or": groups})
+	if err != nil {
+		diags.AddError("Unable to build entity filter", err.Error())
+		return
+	}
+	filter = string(by)
+	return
+}
+
+func entityTypeKeys(ctx context.Context, client models.ClientConfig, titlePatterns []any) (keys []string, diags diag.Diagnostics) {
+	titleConditions := make([]any, len(titlePatterns))
+	for i, pattern := range titlePatterns {
+		titleConditions[i] = map[string]any{"title": pattern}
+	}
+	filter, err := json.Marshal(map[string]any{"$or": titleConditions})
+	if err != nil {
+		diags.AddError("Unable to build entity type filter", err.Error())
+		return
+	}
+
+	keys = []string{}
+	base := entityTypeBase(client, "", "")
+	for item, err := range base.Iter(ctx, &models.Parameters{Filter: string(filter)}) {
+		if err != nil {
+			diags.AddError("Unable to look up entity types", fmt.Sprintf("failed to look up entity types matching %s: %s", filter, err.Error()))
+			return
+		}
+		keys = append(keys, item.RESTKey)
+	}
+	return
+}
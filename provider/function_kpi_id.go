@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &functionKpiID{}
+
+// functionKpiID is the kpi_id function, also available as kpi_match_key.
+type functionKpiID struct {
+	name string
+}
+
+func NewFunctionKpiID() function.Function {
+	return &functionKpiID{name: "kpi_id"}
+}
+
+func NewFunctionKpiMatchKey() function.Function {
+	return &functionKpiID{name: "kpi_match_key"}
+}
+
+func (f *functionKpiID) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f *functionKpiID) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	description := "Returns the key the `itsi_service` resource matches a KPI by across plans, derived from its KPI base search and metric: " +
+		"a KPI whose base search and metric are unchanged keeps its ID, and thereby its historical data. " +
+		"This is a deterministic matching key, not the ID ITSI stores for the KPI: new KPIs are assigned a random ID, " +
+		"available as the `id` attribute of the KPI once the service is created."
+	if f.name != "kpi_id" {
+		description = "Alias of the `kpi_id` function. " + description
+	}
+
+	resp.Definition = function.Definition{
+		Summary:             "Returns the key the itsi_service resource matches a KPI by across plans.",
+		MarkdownDescription: description,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "base_search_id",
+				MarkdownDescription: "ID of the KPI base search.",
+			},
+			function.StringParameter{
+				Name:                "metric_id",
+				MarkdownDescription: "KPI base search metric, as set in the `base_search_metric` attribute of the service KPI.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *functionKpiID) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var baseSearchID, metricID string
+	if resp.Error = req.Arguments.Get(ctx, &baseSearchID, &metricID); resp.Error != nil {
+		return
+	}
+
+	if baseSearchID == "" {
+		resp.Error = function.NewArgumentFuncError(0, "base_search_id must not be empty")
+		return
+	}
+	if metricID == "" {
+		resp.Error = function.NewArgumentFuncError(1, "metric_id must not be empty")
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, kpiInternalKey(baseSearchID, metricID))
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &functionParseRuleExpr{}
+
+var ruleExprAttributeTypes = map[string]attr.Type{
+	"entity_rules": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+		"rule": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+			"field":      types.StringType,
+			"field_type": types.StringType,
+			"rule_type":  types.StringType,
+			"value":      types.StringType,
+		}}},
+	}}},
+	"clauses": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+		"condition": types.StringType,
+		"notable_event_field": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+			"field":    types.StringType,
+			"operator": types.StringType,
+			"value":    types.StringType,
+		}}},
+	}}},
+}
+
+type functionParseRuleExpr struct{}
+
+type ruleExprModel struct {
+	EntityRules []EntityRuleState         `tfsdk:"entity_rules"`
+	Clauses     []neapCriteriaClauseModel `tfsdk:"clauses"`
+}
+
+func NewFunctionParseRuleExpr() function.Function {
+	return &functionParseRuleExpr{}
+}
+
+func (f *functionParseRuleExpr) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_rule_expr"
+}
+
+func (f *functionParseRuleExpr) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a compact rule expression into entity rules or NEAP criteria clauses.",
+		MarkdownDescription: `Parses a compact rule expression into entity rules or NEAP criteria clauses, to be used in ` + "`dynamic`" + ` blocks.
+
+An expression is a list of conditions combined using ` + "`AND`" + ` and ` + "`OR`" + ` (case insensitive), where ` + "`AND`" + ` takes precedence over ` + "`OR`" + `. Parentheses are not supported.
+
+- Conditions using the ` + "`matches`" + ` and ` + "`not`" + ` operators are entity rules, returned in ` + "`entity_rules`" + `: one rule group per ` + "`OR`" + ` operand. The field may be prefixed with its field type (` + "`alias:`, `entity_type:`, `info:` or `title:`" + `), ` + "`alias`" + ` being the default. Multiple values are separated with commas.
+- Conditions using the ` + "`=`, `!=`, `>=`, `>`" + ` and ` + "`<`" + ` operators are notable event field conditions, returned in ` + "`clauses`" + `: one NEAP criteria clause per ` + "`OR`" + ` operand.
+
+The two kinds of conditions cannot be combined in the same expression. Fields and values may be double quoted to include spaces, commas or operator characters. Syntax errors report the column where parsing failed.`,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "expr",
+				MarkdownDescription: "Rule expression, e.g. `host matches web-* AND info:env not prod`.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: ruleExprAttributeTypes,
+		},
+	}
+}
+
+func (f *functionParseRuleExpr) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var expr string
+	if resp.Error = req.Arguments.Get(ctx, &expr); resp.Error != nil {
+		return
+	}
+
+	entityRules, clauses, err := parseRuleExpr(expr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "invalid rule expression: "+err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, ruleExprModel{EntityRules: entityRules, Clauses: clauses})
+}
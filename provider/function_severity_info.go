@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+var _ function.Function = &functionSeverityInfo{}
+
+var severityInfoAttributeTypes = map[string]attr.Type{
+	"label":       types.StringType,
+	"value":       types.Int64Type,
+	"color":       types.StringType,
+	"color_light": types.StringType,
+}
+
+type functionSeverityInfo struct{}
+
+type severityInfoModel struct {
+	Label      types.String `tfsdk:"label"`
+	Value      types.Int64  `tfsdk:"value"`
+	Color      types.String `tfsdk:"color"`
+	ColorLight types.String `tfsdk:"color_light"`
+}
+
+func NewFunctionSeverityInfo() function.Function {
+	return &functionSeverityInfo{}
+}
+
+func (f *functionSeverityInfo) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "severity_info"
+}
+
+func (f *functionSeverityInfo) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Returns the ITSI severity value and colors of a severity label.",
+		MarkdownDescription: fmt.Sprintf("Returns the ITSI severity value and colors of a severity label. Supported labels are: %s.", supportedLabels(util.GetSupportedSeverities())),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "label",
+				MarkdownDescription: "Severity label (case insensitive).",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: severityInfoAttributeTypes,
+		},
+	}
+}
+
+func (f *functionSeverityInfo) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var label string
+	if resp.Error = req.Arguments.Get(ctx, &label); resp.Error != nil {
+		return
+	}
+
+	severity, ok := util.SeverityMap[strings.ToLower(label)]
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("unknown severity label %q: must be one of %s", label, supportedLabels(util.GetSupportedSeverities())))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, severityInfoModel{
+		Label:      types.StringValue(severity.SeverityLabel),
+		Value:      types.Int64Value(int64(severity.SeverityValue)),
+		Color:      types.StringValue(severity.SeverityColor),
+		ColorLight: types.StringValue(severity.SeverityColorLight),
+	})
+}
+
+func supportedLabels(labels []string) string {
+	slices.Sort(labels)
+	return strings.Join(labels, ", ")
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+var _ function.Function = &functionStatusValue{}
+
+type functionStatusValue struct{}
+
+func NewFunctionStatusValue() function.Function {
+	return &functionStatusValue{}
+}
+
+func (f *functionStatusValue) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "status_value"
+}
+
+func (f *functionStatusValue) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Returns the ITSI episode status value of a status label.",
+		MarkdownDescription: fmt.Sprintf("Returns the ITSI episode status value of a status label. Supported labels are: %s.", supportedLabels(util.GetSupportedStatuses())),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "label",
+				MarkdownDescription: "Status label (case insensitive).",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *functionStatusValue) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var label string
+	if resp.Error = req.Arguments.Get(ctx, &label); resp.Error != nil {
+		return
+	}
+
+	value, ok := util.StatusInfoMap[strings.ToLower(label)]
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("unknown status label %q: must be one of %s", label, supportedLabels(util.GetSupportedStatuses())))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, int64(value))
+}
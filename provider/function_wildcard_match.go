@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+var _ function.Function = &functionWildcardMatch{}
+
+type functionWildcardMatch struct{}
+
+func NewFunctionWildcardMatch() function.Function {
+	return &functionWildcardMatch{}
+}
+
+func (f *functionWildcardMatch) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "wildcard_match"
+}
+
+func (f *functionWildcardMatch) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether a string matches a wildcard expression.",
+		MarkdownDescription: "Checks whether a string matches a wildcard expression, where `*` matches any sequence of characters. " +
+			"The match is case insensitive and must cover the whole string, as in the title selectors of `itsictl`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "pattern",
+				MarkdownDescription: "Wildcard expression.",
+			},
+			function.StringParameter{
+				Name:                "s",
+				MarkdownDescription: "String to match.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *functionWildcardMatch) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var pattern, s string
+	if resp.Error = req.Arguments.Get(ctx, &pattern, &s); resp.Error != nil {
+		return
+	}
+
+	// util.WildcardToRegexp panics on invalid expressions, compile the pattern explicitly instead.
+	re, err := regexp.Compile(util.WildcardToRegexpStr(pattern))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid wildcard expression %q: %s", pattern, err))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, re.MatchString(s))
+}
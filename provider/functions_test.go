@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func runTestFunction(t *testing.T, f function.Function, result attr.Value, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+
+	definitionResponse := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, definitionResponse)
+	validateResponse := &function.DefinitionValidateResponse{}
+	definitionResponse.Definition.ValidateImplementation(ctx, function.DefinitionValidateRequest{}, validateResponse)
+	if validateResponse.Diagnostics.HasError() {
+		t.Fatalf("invalid function definition: %v", validateResponse.Diagnostics)
+	}
+
+	resp := &function.RunResponse{Result: function.NewResultData(result)}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func TestFunctionSeverityInfo(t *testing.T) {
+	result, err := runTestFunction(t, NewFunctionSeverityInfo(), types.ObjectUnknown(severityInfoAttributeTypes), types.StringValue("High"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := types.ObjectValueMust(severityInfoAttributeTypes, map[string]attr.Value{
+		"label":       types.StringValue("high"),
+		"value":       types.Int64Value(5),
+		"color":       types.StringValue("#F26A35"),
+		"color_light": types.StringValue("#FBCBB9"),
+	})
+	if !result.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	if _, err := runTestFunction(t, NewFunctionSeverityInfo(), types.ObjectUnknown(severityInfoAttributeTypes), types.StringValue("warning")); err == nil {
+		t.Error("expected an error for an unknown severity label")
+	}
+}
+
+func TestFunctionStatusValue(t *testing.T) {
+	result, err := runTestFunction(t, NewFunctionStatusValue(), types.Int64Unknown(), types.StringValue("In Progress"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Equal(types.Int64Value(2)) {
+		t.Errorf("expected 2, got %s", result)
+	}
+
+	if _, err := runTestFunction(t, NewFunctionStatusValue(), types.Int64Unknown(), types.StringValue("open")); err == nil {
+		t.Error("expected an error for an unknown status label")
+	}
+}
+
+func TestFunctionKpiID(t *testing.T) {
+	kpi := KpiState{BaseSearchID: types.StringValue("5f5a2d2e8a1b2c3d4e5f6a7b"), BaseSearchMetric: types.StringValue("metric_1")}
+	for _, f := range []function.Function{NewFunctionKpiID(), NewFunctionKpiMatchKey()} {
+		result, err := runTestFunction(t, f, types.StringUnknown(), kpi.BaseSearchID, kpi.BaseSearchMetric)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Equal(types.StringValue(kpi.internalKey())) {
+			t.Errorf("expected %s, got %s", kpi.internalKey(), result)
+		}
+
+		if _, err := runTestFunction(t, f, types.StringUnknown(), types.StringValue(""), kpi.BaseSearchMetric); err == nil {
+			t.Error("expected an error for an empty base search ID")
+		}
+	}
+}
+
+func TestFunctionWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		match      bool
+	}{
+		{"*", "anything", true},
+		{"sample*", "Sample Service", true},
+		{"*service", "sample service", true},
+		{"sample", "sample service", false},
+		{"a.c*", "abc", false},
+		{"a.c*", "a.c", true},
+	}
+	for _, test := range tests {
+		result, err := runTestFunction(t, NewFunctionWildcardMatch(), types.BoolUnknown(), types.StringValue(test.pattern), types.StringValue(test.s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Equal(types.BoolValue(test.match)) {
+			t.Errorf("wildcard_match(%q, %q): expected %t, got %s", test.pattern, test.s, test.match, result)
+		}
+	}
+
+	if _, err := runTestFunction(t, NewFunctionWildcardMatch(), types.BoolUnknown(), types.StringValue("a(b"), types.StringValue("a(b")); err == nil {
+		t.Error("expected an error for an invalid wildcard expression")
+	}
+}
+
+func TestFunctionParseRuleExpr(t *testing.T) {
+	result, err := runTestFunction(t, NewFunctionParseRuleExpr(), types.ObjectUnknown(ruleExprAttributeTypes), types.StringValue("host matches web-* AND info:env not prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entityRules := result.(types.Object).Attributes()["entity_rules"].(types.List)
+	if len(entityRules.Elements()) != 1 {
+		t.Fatalf("expected 1 rule group, got %s", entityRules)
+	}
+	rules := entityRules.Elements()[0].(types.Object).Attributes()["rule"].(types.List)
+	if len(rules.Elements()) != 2 {
+		t.Errorf("expected 2 rules, got %s", rules)
+	}
+
+	if _, err := runTestFunction(t, NewFunctionParseRuleExpr(), types.ObjectUnknown(ruleExprAttributeTypes), types.StringValue("host matches")); err == nil {
+		t.Error("expected an error for an invalid rule expression")
+	}
+}
@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// KpiThresholding is the thresholding configuration of a KPI.
+// Its YAML field names are the attribute names of the itsi_kpi_threshold_template resource,
+// so that an exported KPI thresholding configuration can be turned into a threshold template.
+type KpiThresholding struct {
+	AdaptiveThresholdsIsEnabled                     bool                         `yaml:"adaptive_thresholds_is_enabled" json:"adaptive_thresholds_is_enabled"`
+	AdaptiveThresholdingTrainingWindow              string                       `yaml:"adaptive_thresholding_training_window,omitempty" json:"adaptive_thresholding_training_window,omitempty"`
+	AdaptiveThresholdingOutlierExclusionAlgo        *string                      `yaml:"adaptive_thresholding_outlier_exclusion_algo,omitempty" json:"adaptive_thresholding_outlier_exclusion_algo,omitempty"`
+	AdaptiveThresholdingOutlierExclusionSensitivity *float64                     `yaml:"adaptive_thresholding_outlier_exclusion_sensitivity,omitempty" json:"adaptive_thresholding_outlier_exclusion_sensitivity,omitempty"`
+	TimeVariateThresholds                           bool                         `yaml:"time_variate_thresholds" json:"time_variate_thresholds"`
+	TimeVariateThresholdsSpecification              KpiThresholdingSpecification `yaml:"time_variate_thresholds_specification" json:"time_variate_thresholds_specification"`
+}
+
+type KpiThresholdingSpecification struct {
+	Policies []KpiThresholdingPolicy `yaml:"policies" json:"policies"`
+}
+
+type KpiThresholdingPolicy struct {
+	PolicyName          string                     `yaml:"policy_name" json:"policy_name"`
+	Title               string                     `yaml:"title" json:"title"`
+	PolicyType          string                     `yaml:"policy_type" json:"policy_type"`
+	TimeBlocks          []KpiThresholdingTimeBlock `yaml:"time_blocks,omitempty" json:"time_blocks,omitempty"`
+	AggregateThresholds KpiThresholdingSetting     `yaml:"aggregate_thresholds" json:"aggregate_thresholds"`
+	EntityThresholds    KpiThresholdingSetting     `yaml:"entity_thresholds" json:"entity_thresholds"`
+}
+
+type KpiThresholdingTimeBlock struct {
+	Cron     string `yaml:"cron" json:"cron"`
+	Interval int64  `yaml:"interval" json:"interval"`
+}
+
+// KpiThresholdingSetting mirrors ThresholdSettingModel.
+type KpiThresholdingSetting struct {
+	BaseSeverityLabel string                 `yaml:"base_severity_label" json:"base_severity_label"`
+	GaugeMax          float64                `yaml:"gauge_max" json:"gauge_max"`
+	GaugeMin          float64                `yaml:"gauge_min" json:"gauge_min"`
+	IsMaxStatic       bool                   `yaml:"is_max_static" json:"is_max_static"`
+	IsMinStatic       bool                   `yaml:"is_min_static" json:"is_min_static"`
+	MetricField       string                 `yaml:"metric_field" json:"metric_field"`
+	RenderBoundaryMax float64                `yaml:"render_boundary_max" json:"render_boundary_max"`
+	RenderBoundaryMin float64                `yaml:"render_boundary_min" json:"render_boundary_min"`
+	ThresholdLevels   []KpiThresholdingLevel `yaml:"threshold_levels" json:"threshold_levels"`
+}
+
+// KpiThresholdingLevel mirrors KpiThresholdLevelModel.
+type KpiThresholdingLevel struct {
+	SeverityLabel  string  `yaml:"severity_label" json:"severity_label"`
+	ThresholdValue float64 `yaml:"threshold_value" json:"threshold_value"`
+	DynamicParam   float64 `yaml:"dynamic_param" json:"dynamic_param"`
+}
+
+// KpiThresholdingFromAPI extracts the thresholding configuration of a KPI API payload.
+// Unless time variate thresholds are enabled, the default policy reports the KPI level aggregate and entity thresholds,
+// since those are the ones in effect.
+func KpiThresholdingFromAPI(kpi map[string]any) (t KpiThresholding, diags diag.Diagnostics) {
+	// work on a copy, since reading the payload into the model normalizes it in place
+	by, err := json.Marshal(mapSubset(kpi, kpiThresholdingConfigFields))
+	if err != nil {
+		diags.AddError("Failed to read KPI thresholding", err.Error())
+		return
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(by, &fields); err != nil {
+		diags.AddError("Failed to read KPI thresholding", err.Error())
+		return
+	}
+
+	spec, _ := fields["time_variate_thresholds_specification"].(map[string]any)
+	if policies, ok := spec["policies"].(map[string]any); !ok || len(policies) == 0 {
+		diags.AddError("Failed to read KPI thresholding", "KPI has no time_variate_thresholds_specification policies")
+		return
+	}
+	if timeVariate, _ := fields["time_variate_thresholds"].(bool); !timeVariate {
+		if defaultPolicy, ok := spec["policies"].(map[string]any)[kpiThresholdPreviewDefaultPolicy].(map[string]any); ok {
+			for _, setting := range []string{"aggregate_thresholds", "entity_thresholds"} {
+				if value, ok := fields[setting].(map[string]any); ok {
+					defaultPolicy[setting] = value
+				}
+			}
+		}
+	}
+
+	var model modelKpiThresholdTemplate
+	if diags.Append(populateKpiThresholdTemplateModelFromAPI(fields, &model)...); diags.HasError() {
+		return
+	}
+
+	t = KpiThresholding{
+		AdaptiveThresholdsIsEnabled:        model.AdaptiveThresholdsIsEnabled.ValueBool(),
+		AdaptiveThresholdingTrainingWindow: model.AdaptiveThresholdingTrainingWindow.ValueString(),
+		TimeVariateThresholds:              model.TimeVariateThresholds.ValueBool(),
+	}
+	if !model.AdaptiveThresholdingOutlierExclusionAlgo.IsNull() {
+		t.AdaptiveThresholdingOutlierExclusionAlgo = model.AdaptiveThresholdingOutlierExclusionAlgo.ValueStringPointer()
+	}
+	if !model.AdaptiveThresholdingOutlierExclusionSensitivity.IsNull() {
+		t.AdaptiveThresholdingOutlierExclusionSensitivity = model.AdaptiveThresholdingOutlierExclusionSensitivity.ValueFloat64Pointer()
+	}
+
+	for _, policy := range model.TimeVariateThresholdsSpecification.Policies {
+		p := KpiThresholdingPolicy{
+			PolicyName:          policy.PolicyName.ValueString(),
+			Title:               policy.Title.ValueString(),
+			PolicyType:          policy.PolicyType.ValueString(),
+			AggregateThresholds: kpiThresholdingSettingFromModel(policy.AggregateThresholds),
+			EntityThresholds:    kpiThresholdingSettingFromModel(policy.EntityThresholds),
+		}
+		for _, tb := range policy.TimeBlocks {
+			p.TimeBlocks = append(p.TimeBlocks, KpiThresholdingTimeBlock{Cron: tb.Cron.ValueString(), Interval: tb.Interval.ValueInt64()})
+		}
+		t.TimeVariateThresholdsSpecification.Policies = append(t.TimeVariateThresholdsSpecification.Policies, p)
+	}
+	sort.Slice(t.TimeVariateThresholdsSpecification.Policies, func(i, j int) bool {
+		return t.TimeVariateThresholdsSpecification.Policies[i].PolicyName < t.TimeVariateThresholdsSpecification.Policies[j].PolicyName
+	})
+	return
+}
+
+// ApplyToAPI writes the thresholding configuration into a KPI API payload.
+// If the thresholds change, the KPI is unlinked from its threshold template, if any, so that ITSI does not overwrite them.
+// The KPI level aggregate and entity thresholds are set to those of the default policy.
+func (t KpiThresholding) ApplyToAPI(ctx context.Context, kpi map[string]any) (diags diag.Diagnostics) {
+	model := modelKpiThresholdTemplate{
+		AdaptiveThresholdsIsEnabled:                     types.BoolValue(t.AdaptiveThresholdsIsEnabled),
+		AdaptiveThresholdingTrainingWindow:              types.StringValue(t.AdaptiveThresholdingTrainingWindow),
+		AdaptiveThresholdingOutlierExclusionAlgo:        types.StringPointerValue(t.AdaptiveThresholdingOutlierExclusionAlgo),
+		AdaptiveThresholdingOutlierExclusionSensitivity: types.Float64PointerValue(t.AdaptiveThresholdingOutlierExclusionSensitivity),
+		TimeVariateThresholds:                           types.BoolValue(t.TimeVariateThresholds),
+		TimeVariateThresholdsSpecification:              &TimeVariateThresholdsSpecificationModel{},
+	}
+	if t.AdaptiveThresholdingTrainingWindow == "" {
+		model.AdaptiveThresholdingTrainingWindow = types.StringNull()
+	}
+
+	hasDefaultPolicy := false
+	for _, p := range t.TimeVariateThresholdsSpecification.Policies {
+		hasDefaultPolicy = hasDefaultPolicy || p.PolicyName == kpiThresholdPreviewDefaultPolicy
+		policy := PolicyModel{
+			PolicyName:          types.StringValue(p.PolicyName),
+			Title:               types.StringValue(p.Title),
+			PolicyType:          types.StringValue(p.PolicyType),
+			TimeBlocks:          []TimeBlockModel{},
+			AggregateThresholds: p.AggregateThresholds.model(),
+			EntityThresholds:    p.EntityThresholds.model(),
+		}
+		for _, tb := range p.TimeBlocks {
+			policy.TimeBlocks = append(policy.TimeBlocks, TimeBlockModel{Cron: types.StringValue(tb.Cron), Interval: types.Int64Value(tb.Interval)})
+		}
+		model.TimeVariateThresholdsSpecification.Policies = append(model.TimeVariateThresholdsSpecification.Policies, policy)
+	}
+	if !hasDefaultPolicy {
+		diags.AddError("Failed to apply KPI thresholding", fmt.Sprintf("time_variate_thresholds_specification must have a %s policy", kpiThresholdPreviewDefaultPolicy))
+		return
+	}
+
+	body, d := kpiThresholdTemplatePayload(ctx, model)
+	if diags.Append(d...); diags.HasError() {
+		return
+	}
+
+	// KPIs without readable thresholds are considered changed
+	current, currentDiags := KpiThresholdingFromAPI(kpi)
+
+	for _, field := range kpiThresholdingConfigFields {
+		if value, ok := body[field]; ok {
+			kpi[field] = value
+		}
+	}
+	if t.AdaptiveThresholdingOutlierExclusionAlgo == nil {
+		delete(kpi, "outlier_detection_algo")
+	}
+	if t.AdaptiveThresholdingOutlierExclusionSensitivity == nil {
+		delete(kpi, "outlier_detection_sensitivity")
+	}
+
+	defaultPolicy := body["time_variate_thresholds_specification"].(map[string]any)["policies"].(map[string]any)[kpiThresholdPreviewDefaultPolicy].(map[string]any)
+	kpi["aggregate_thresholds"] = defaultPolicy["aggregate_thresholds"]
+	kpi["entity_thresholds"] = defaultPolicy["entity_thresholds"]
+
+	updated, updatedDiags := KpiThresholdingFromAPI(kpi)
+	if currentDiags.HasError() || updatedDiags.HasError() || !reflect.DeepEqual(current, updated) {
+		kpi["kpi_threshold_template_id"] = ""
+	}
+	return
+}
+
+func kpiThresholdingSettingFromModel(m ThresholdSettingModel) KpiThresholdingSetting {
+	s := KpiThresholdingSetting{
+		BaseSeverityLabel: m.BaseSeverityLabel.ValueString(),
+		GaugeMax:          m.GaugeMax.ValueFloat64(),
+		GaugeMin:          m.GaugeMin.ValueFloat64(),
+		IsMaxStatic:       m.IsMaxStatic.ValueBool(),
+		IsMinStatic:       m.IsMinStatic.ValueBool(),
+		MetricField:       m.MetricField.ValueString(),
+		RenderBoundaryMax: m.RenderBoundaryMax.ValueFloat64(),
+		RenderBoundaryMin: m.RenderBoundaryMin.ValueFloat64(),
+		ThresholdLevels:   []KpiThresholdingLevel{},
+	}
+	for _, l := range m.ThresholdLevels {
+		s.ThresholdLevels = append(s.ThresholdLevels, KpiThresholdingLevel{
+			SeverityLabel:  l.SeverityLabel.ValueString(),
+			ThresholdValue: l.ThresholdValue.ValueFloat64(),
+			DynamicParam:   l.DynamicParam.ValueFloat64(),
+		})
+	}
+	return s
+}
+
+func (s KpiThresholdingSetting) model() ThresholdSettingModel {
+	m := ThresholdSettingModel{
+		BaseSeverityLabel: types.StringValue(s.BaseSeverityLabel),
+		GaugeMax:          types.Float64Value(s.GaugeMax),
+		GaugeMin:          types.Float64Value(s.GaugeMin),
+		IsMaxStatic:       types.BoolValue(s.IsMaxStatic),
+		IsMinStatic:       types.BoolValue(s.IsMinStatic),
+		MetricField:       types.StringValue(s.MetricField),
+		RenderBoundaryMax: types.Float64Value(s.RenderBoundaryMax),
+		RenderBoundaryMin: types.Float64Value(s.RenderBoundaryMin),
+	}
+	if s.BaseSeverityLabel == "" {
+		m.BaseSeverityLabel = types.StringValue(BASE_SEVERITY_LABEL_DEFAULT)
+	}
+	for _, l := range s.ThresholdLevels {
+		m.ThresholdLevels = append(m.ThresholdLevels, KpiThresholdLevelModel{
+			SeverityLabel:  types.StringValue(l.SeverityLabel),
+			ThresholdValue: types.Float64Value(l.ThresholdValue),
+			DynamicParam:   types.Float64Value(l.DynamicParam),
+		})
+	}
+	return m
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestKpiThresholdingRoundTrip(t *testing.T) {
+	setting := func(baseSeverity string, levels ...any) map[string]any {
+		return map[string]any{
+			"baseSeverityLabel": baseSeverity,
+			"gaugeMax":          100.0,
+			"gaugeMin":          0.0,
+			"isMaxStatic":       false,
+			"isMinStatic":       true,
+			"metricField":       "count",
+			"renderBoundaryMax": 100.0,
+			"renderBoundaryMin": 0.0,
+			"thresholdLevels":   append([]any{}, levels...),
+		}
+	}
+	level := func(severity string, threshold, dynamicParam any) map[string]any {
+		return map[string]any{"severityLabel": severity, "thresholdValue": threshold, "dynamicParam": dynamicParam}
+	}
+
+	kpi := map[string]any{
+		"_key":                                  "kpi1",
+		"kpi_threshold_template_id":             "template1",
+		"adaptive_thresholds_is_enabled":        true,
+		"adaptive_thresholding_training_window": "-7d",
+		"aggregate_outlier_detection_enabled":   true,
+		"outlier_detection_algo":                "stdev",
+		"outlier_detection_sensitivity":         3.0,
+		"time_variate_thresholds":               false,
+		// the KPI level thresholds are in effect, since time variate thresholds are disabled
+		"aggregate_thresholds": setting("normal", level("high", 10.0, 2.0)),
+		"entity_thresholds":    setting("info"),
+		"time_variate_thresholds_specification": map[string]any{
+			"policies": map[string]any{
+				"weekend": map[string]any{
+					"title":                "Weekend",
+					"policy_type":          "stdev",
+					"time_blocks":          []any{[]any{"0 0 * * 6", 2880.0}},
+					"aggregate_thresholds": setting("normal", level("critical", 5.0, 3.0)),
+					"entity_thresholds":    setting("normal"),
+				},
+				"default_policy": map[string]any{
+					"title":                "Default",
+					"policy_type":          "stdev",
+					"time_blocks":          []any{},
+					"aggregate_thresholds": setting("normal", level("high", 1.0, 1.0)),
+					"entity_thresholds":    setting("normal"),
+				},
+			},
+		},
+	}
+
+	thresholding, diags := KpiThresholdingFromAPI(kpi)
+	if diags.HasError() {
+		t.Fatalf("KpiThresholdingFromAPI: %v", diags)
+	}
+
+	policies := thresholding.TimeVariateThresholdsSpecification.Policies
+	if len(policies) != 2 || policies[0].PolicyName != "default_policy" || policies[1].PolicyName != "weekend" {
+		t.Fatalf("expected policies sorted by name, got %+v", policies)
+	}
+	if got := policies[0].AggregateThresholds.ThresholdLevels; len(got) != 1 || got[0].ThresholdValue != 10 || got[0].DynamicParam != 2 {
+		t.Errorf("expected the default policy to report the KPI level aggregate thresholds, got %+v", got)
+	}
+	if policies[0].EntityThresholds.BaseSeverityLabel != "info" {
+		t.Errorf("expected the default policy to report the KPI level entity thresholds, got %+v", policies[0].EntityThresholds)
+	}
+	if thresholding.AdaptiveThresholdingOutlierExclusionAlgo == nil || *thresholding.AdaptiveThresholdingOutlierExclusionAlgo != "stdev" {
+		t.Errorf("unexpected outlier exclusion algo: %v", thresholding.AdaptiveThresholdingOutlierExclusionAlgo)
+	}
+	if kpi["time_variate_thresholds_specification"].(map[string]any)["policies"].(map[string]any)["default_policy"].(map[string]any)["entity_thresholds"].(map[string]any)["baseSeverityLabel"] != "normal" {
+		t.Errorf("KpiThresholdingFromAPI must not modify the KPI payload")
+	}
+
+	target := map[string]any{"_key": "kpi2", "kpi_threshold_template_id": "template2", "outlier_detection_algo": "iqr"}
+	if diags := thresholding.ApplyToAPI(context.Background(), target); diags.HasError() {
+		t.Fatalf("ApplyToAPI: %v", diags)
+	}
+	if target["kpi_threshold_template_id"] != "" {
+		t.Errorf("expected the KPI to be unlinked from its threshold template, got %v", target["kpi_threshold_template_id"])
+	}
+	if target["aggregate_outlier_detection_enabled"] != true || target["outlier_detection_algo"] != "stdev" {
+		t.Errorf("unexpected outlier exclusion settings: %v, %v", target["aggregate_outlier_detection_enabled"], target["outlier_detection_algo"])
+	}
+
+	roundTrip, diags := KpiThresholdingFromAPI(target)
+	if diags.HasError() {
+		t.Fatalf("KpiThresholdingFromAPI: %v", diags)
+	}
+	if !reflect.DeepEqual(thresholding, roundTrip) {
+		t.Errorf("round trip mismatch:\n%+v\n%+v", thresholding, roundTrip)
+	}
+
+	linked := maps.Clone(kpi)
+	if diags := thresholding.ApplyToAPI(context.Background(), linked); diags.HasError() {
+		t.Fatalf("ApplyToAPI: %v", diags)
+	}
+	if linked["kpi_threshold_template_id"] != "template1" {
+		t.Errorf("expected a KPI with unchanged thresholds to stay linked to its threshold template, got %v", linked["kpi_threshold_template_id"])
+	}
+
+	thresholding.TimeVariateThresholdsSpecification.Policies = policies[1:]
+	if diags := thresholding.ApplyToAPI(context.Background(), map[string]any{}); !diags.HasError() {
+		t.Errorf("expected an error for a thresholding configuration without a default policy")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	testingresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -22,7 +23,8 @@ import (
 )
 
 const (
-	accTestPrefix = "TestAcc_"
+	accTestPrefix       = "TestAcc_"
+	envHttpCassetteMode = "ITSI_HTTP_CASSETTE_MODE"
 )
 
 var providerFactories = map[string]func() (tfprotov6.ProviderServer, error){
@@ -32,6 +34,11 @@ var providerFactories = map[string]func() (tfprotov6.ProviderServer, error){
 }
 
 func TestMain(m *testing.M) {
+	// replayed acceptance tests run without network access, where the Terraform CLI cannot be downloaded
+	if os.Getenv(envHttpCassetteMode) == "replay" && os.Getenv("TF_ACC") != "" && os.Getenv("TF_ACC_TERRAFORM_PATH") == "" {
+		fmt.Fprintln(os.Stderr, "TF_ACC_TERRAFORM_PATH must be set to a Terraform CLI to replay the acceptance tests")
+		os.Exit(1)
+	}
 	testingresource.TestMain(m)
 }
 
@@ -60,6 +67,11 @@ func itsiClientConfig() (client models.ClientConfig) {
 
 	insecure := util.Atob(os.Getenv(envITSIInsecure))
 	client.SkipTLS = insecure
+	client.CACert = os.Getenv(envITSICACert)
+	client.ClientCert = os.Getenv(envITSIClientCert)
+	client.ClientKey = os.Getenv(envITSIClientKey)
+	client.ProxyURL = os.Getenv(envITSIProxyURL)
+	client.NoProxy = os.Getenv(envITSINoProxy)
 	client.RetryPolicy = retryPolicy
 	client.Concurrency = clientConcurrency
 	return
@@ -121,6 +133,39 @@ func TestProviderSchema(t *testing.T) {
 	}
 }
 
+func TestProviderConcurrencyLimiters(t *testing.T) {
+	adaptive := itsiProviderModel{MinConcurrency: types.Int64Value(2), MaxConcurrency: types.Int64Value(4)}
+	fixed := itsiProviderModel{MinConcurrency: types.Int64Null(), MaxConcurrency: types.Int64Null()}
+
+	// e.g. aliased providers, configured one after the other
+	var a, b models.ClientConfig
+	if diags := adaptive.configureConcurrency(&a); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := fixed.configureConcurrency(&b); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if l, ok := a.ItsiLimiter.(*util.AdaptiveLimiter); !ok || l.Limit() != 4 || a.Concurrency != 4 {
+		t.Errorf("expected an adaptive ITSI API limiter starting at 4, got %#v", a.ItsiLimiter)
+	}
+	if l, ok := a.SearchLimiter.(*util.AdaptiveLimiter); !ok || l.Limit() != 4 || l == a.ItsiLimiter {
+		t.Errorf("expected a distinct adaptive search limiter starting at 4, got %#v", a.SearchLimiter)
+	}
+	// without adaptive concurrency settings, the fixed process-wide limiters are shared
+	if b.ItsiLimiter != nil || b.SearchLimiter != nil {
+		t.Errorf("expected no limiters of the client config, got %#v and %#v", b.ItsiLimiter, b.SearchLimiter)
+	}
+	if searchLimiter(a) != a.SearchLimiter || searchLimiter(b) != splunkSearchLimiter {
+		t.Errorf("expected searches to use the limiter of their client config, or the process-wide limiter")
+	}
+
+	invalid := itsiProviderModel{MinConcurrency: types.Int64Value(8), MaxConcurrency: types.Int64Value(4)}
+	if diags := invalid.configureConcurrency(&models.ClientConfig{}); !diags.HasError() {
+		t.Errorf("expected an error for min_concurrency greater than max_concurrency")
+	}
+}
+
 func testAccCheckResourceExists(resourcetype resourceName, resourceTitle string) testingresource.TestCheckFunc {
 	return func(s *terraform.State) (err error) {
 		ok, err := checkResourceExists(s, resourcetype, resourceTitle)
@@ -257,6 +302,10 @@ func initSweepers() {
 		Name: "entity",
 		F:    sweepITSIResource(resourceNameEntity),
 	})
+	testingresource.AddTestSweepers("entity_management_policy", &testingresource.Sweeper{
+		Name: "entity_management_policy",
+		F:    sweepITSIResource(resourceNameEntityManagementPolicy),
+	})
 	testingresource.AddTestSweepers("entity_type", &testingresource.Sweeper{
 		Name: "entity_type",
 		F:    sweepITSIResource(resourceNameEntityType),
@@ -0,0 +1,630 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// entitiesScopeField is the entity field used to mark entities owned by an itsi_entities resource.
+	entitiesScopeField = "_tf_scope"
+	// entitiesFilterBatchSize is the max number of entity keys or titles used in a single filter expression.
+	entitiesFilterBatchSize = 100
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &resourceEntities{}
+	_ resource.ResourceWithImportState = &resourceEntities{}
+	_ resource.ResourceWithModifyPlan  = &resourceEntities{}
+	_ apibuildWorkflow[entityModel]    = &scopedEntityBuildWorkflow{}
+)
+
+// =================== [ Entities ] ===================
+
+type entitiesEntryModel struct {
+	ID types.String `tfsdk:"id"`
+
+	Title       types.String `tfsdk:"title"`
+	Description types.String `tfsdk:"description"`
+
+	Aliases types.Map `tfsdk:"aliases"`
+	Info    types.Map `tfsdk:"info"`
+
+	EntityTypeIDs types.Set `tfsdk:"entity_type_ids"`
+}
+
+func (e entitiesEntryModel) entityModel() entityModel {
+	return entityModel{
+		ID:            e.ID,
+		Title:         e.Title,
+		Description:   e.Description,
+		Aliases:       e.Aliases,
+		Info:          e.Info,
+		EntityTypeIDs: e.EntityTypeIDs,
+	}
+}
+
+func newEntitiesEntryModel(m entityModel) entitiesEntryModel {
+	return entitiesEntryModel{
+		ID:            m.ID,
+		Title:         m.Title,
+		Description:   m.Description,
+		Aliases:       m.Aliases,
+		Info:          m.Info,
+		EntityTypeIDs: m.EntityTypeIDs,
+	}
+}
+
+type entitiesModel struct {
+	ID       types.String `tfsdk:"id"`
+	Scope    types.String `tfsdk:"scope"`
+	Entities types.Set    `tfsdk:"entity"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (m *entitiesModel) entries(ctx context.Context) (entries []entitiesEntryModel, diags diag.Diagnostics) {
+	if !m.Entities.IsNull() && !m.Entities.IsUnknown() {
+		diags = m.Entities.ElementsAs(ctx, &entries, false)
+	}
+	return
+}
+
+func (m *entitiesModel) setEntries(ctx context.Context, entries []entitiesEntryModel) (diags diag.Diagnostics) {
+	m.Entities, diags = types.SetValueFrom(ctx, m.Entities.ElementType(ctx), entries)
+	return
+}
+
+type resourceEntities struct {
+	client models.ClientConfig
+}
+
+func NewResourceEntities() resource.Resource {
+	return &resourceEntities{}
+}
+
+// validations
+
+// entitiesSetValidator validates the entity set, ensuring that each entity has a unique title.
+
+type entitiesSetValidator struct{}
+
+const entitiesSetValidatorDescription = "Each entity managed by the resource must have a unique title."
+
+func (v entitiesSetValidator) Description(ctx context.Context) string {
+	return entitiesSetValidatorDescription
+}
+
+func (v entitiesSetValidator) MarkdownDescription(ctx context.Context) string {
+	return entitiesSetValidatorDescription
+}
+
+func (v entitiesSetValidator) ValidateTitleUniqueness(ctx context.Context, entries []entitiesEntryModel) (diags diag.Diagnostics) {
+	titles := util.NewSet[string]()
+	for _, entry := range entries {
+		if entry.Title.IsUnknown() || entry.Title.IsNull() {
+			continue
+		}
+
+		if titles.Contains(entry.Title.ValueString()) {
+			diags.AddError("Duplicate entity title", util.Dedent(fmt.Sprintf(`
+				Entity with title %q is defined more than once.
+				Please ensure that each entity has a unique title.
+			`, entry.Title.ValueString())))
+		}
+		titles.Add(entry.Title.ValueString())
+	}
+	return
+}
+
+func (v entitiesSetValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+	var entries []entitiesEntryModel
+	if diags := req.ConfigValue.ElementsAs(ctx, &entries, false); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	resp.Diagnostics = v.ValidateTitleUniqueness(ctx, entries)
+}
+
+// resource methods
+
+func (r *resourceEntities) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	configureResourceClient(ctx, resourceNameEntities, req, &r.client, resp)
+}
+
+func (r *resourceEntities) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	configureResourceMetadata(req, resp, resourceNameEntities)
+}
+
+func (r *resourceEntities) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: util.Dedent(`
+			Manages a set of Entity objects within ITSI using the bulk APIs.
+			All entities managed by the resource are marked with the resource's scope,
+			and only the entities within that scope are read, updated or deleted by the resource.
+		`),
+		Blocks: map[string]schema.Block{
+			"entity": schema.SetNestedBlock{
+				MarkdownDescription: "Block representing an entity owned by the resource.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "ID of the entity.",
+							Computed:            true,
+						},
+						"title": schema.StringAttribute{
+							MarkdownDescription: "Name of the entity. Can be any unique value.",
+							Required:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "User defined description of the entity.",
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString(""),
+						},
+						"aliases": schema.MapAttribute{
+							MarkdownDescription: "Map of fields to lists of values that identify the entity.",
+							ElementType:         entityFieldsType.ElemType,
+							Optional:            true,
+							Computed:            true,
+							Default:             mapdefault.StaticValue(types.MapValueMust(entityFieldsType.ElemType, map[string]attr.Value{})),
+						},
+						"info": schema.MapAttribute{
+							MarkdownDescription: "Map of fields to lists of values that provide information/description for the entity.",
+							ElementType:         entityFieldsType.ElemType,
+							Optional:            true,
+							Computed:            true,
+							Default:             mapdefault.StaticValue(types.MapValueMust(entityFieldsType.ElemType, map[string]attr.Value{})),
+						},
+						"entity_type_ids": schema.SetAttribute{
+							MarkdownDescription: "A set of _key values for each entity type associated with the entity.",
+							ElementType:         types.StringType,
+							Optional:            true,
+							Computed:            true,
+							Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+						},
+					},
+				},
+				Validators: []validator.Set{
+					new(entitiesSetValidator),
+				},
+			},
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID of the resource. Matches the scope.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"scope": schema.StringAttribute{
+				MarkdownDescription: util.Dedent(`
+					Scope of the entities managed by the resource.
+					Each itsi_entities resource must use a unique scope.
+				`),
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// =================== [ Entities API / Builder] ===================
+
+// scopedEntityBuildWorkflow extends the entity build workflow with the scope marker
+// and the entity _key, as required by the bulk update API.
+type scopedEntityBuildWorkflow struct {
+	entityBuildWorkflow
+	scope string
+}
+
+//lint:ignore U1000 used by apibuilder
+func (w *scopedEntityBuildWorkflow) buildSteps() []apibuildWorkflowStepFunc[entityModel] {
+	return append(w.entityBuildWorkflow.buildSteps(), w.scopeMarker)
+}
+
+func (w *scopedEntityBuildWorkflow) scopeMarker(ctx context.Context, obj entityModel) (map[string]any, diag.Diagnostics) {
+	res := map[string]any{entitiesScopeField: w.scope}
+	if id := obj.ID.ValueString(); id != "" {
+		res["_key"] = id
+	}
+	return res, nil
+}
+
+// =================== [ Entities API ] ===================
+
+func entitiesScopeFilter(scope string) (string, error) {
+	filter, err := json.Marshal(map[string]string{entitiesScopeField: scope})
+	return string(filter), err
+}
+
+// readEntities returns all entities that belong to the given scope, using paged requests.
+func (r *resourceEntities) readEntities(ctx context.Context, scope string) (entries []entitiesEntryModel, diags diag.Diagnostics) {
+	filter, err := entitiesScopeFilter(scope)
+	if err != nil {
+		diags.AddError("Unable to read entities", err.Error())
+		return
+	}
+
+	entries = []entitiesEntryModel{}
+	base := entityBase(r.client, "", "")
+	for item, err := range base.Iter(ctx, &models.Parameters{Filter: filter}) {
+		if err != nil {
+			diags.AddError("Unable to read entities", err.Error())
+			return
+		}
+
+		entity, d := newAPIParser(item, new(entityParseWorkflow)).parse(ctx, item)
+		if diags.Append(d...); diags.HasError() {
+			return
+		}
+		entries = append(entries, newEntitiesEntryModel(entity))
+	}
+	return
+}
+
+// validateScopeUniqueness validates that no entities have been marked with the given scope yet.
+func (r *resourceEntities) validateScopeUniqueness(ctx context.Context, scope string) (diags diag.Diagnostics) {
+	filter, err := entitiesScopeFilter(scope)
+	if err != nil {
+		diags.AddError("Unexpected error while validating scope uniqueness", err.Error())
+		return
+	}
+
+	base := entityBase(r.client, "", "")
+	items, err := base.Dump(ctx, &models.Parameters{Offset: 0, Count: 1, Fields: []string{"_key", "title", entitiesScopeField}, Filter: filter})
+	if err != nil {
+		diags.AddError("Unexpected error while validating scope uniqueness", err.Error())
+		return
+	}
+
+	if len(items) > 0 {
+		diags.AddError("Duplicate entities scope", util.Dedent(fmt.Sprintf(`
+			ITSI already contains entities with the '%s' scope, that are not managed by this instance of the itsi_entities resource.
+			Entities modification will be aborted to prevent data loss.
+			Consider changing the scope, or use 'terraform import' to manage the existing entities using this terraform resource.
+			Conflicting entity example: %s (%s)
+		`, scope, items[0].TFID, items[0].RESTKey)))
+	}
+	return
+}
+
+// validateTitleOwnership validates that the managed entity titles are not used by entities outside of the given scope.
+func (r *resourceEntities) validateTitleOwnership(ctx context.Context, scope string, entries []entitiesEntryModel) (diags diag.Diagnostics) {
+	const unexpectedErrorSummary = "Unexpected error while validating entity title/scope uniqueness"
+	base := entityBase(r.client, "", "")
+	conflicts := []map[string]string{}
+
+	for start := 0; start < len(entries); start += entitiesFilterBatchSize {
+		batch := entries[start:min(start+entitiesFilterBatchSize, len(entries))]
+		titleList := make([]map[string]string, len(batch))
+		for i, entry := range batch {
+			titleList[i] = map[string]string{"title": entry.Title.ValueString()}
+		}
+		filter, err := json.Marshal(map[string]any{
+			"$and": []any{
+				map[string]any{"$or": titleList},
+				map[string]any{entitiesScopeField: map[string]string{"$ne": scope}},
+			},
+		})
+		if err != nil {
+			diags.AddError(unexpectedErrorSummary, err.Error())
+			return
+		}
+
+		items, err := base.Dump(ctx, &models.Parameters{Fields: []string{"_key", "title"}, Filter: string(filter)})
+		if err != nil {
+			diags.AddError(unexpectedErrorSummary, err.Error())
+			return
+		}
+		for _, item := range items {
+			conflicts = append(conflicts, map[string]string{"_key": item.RESTKey, "title": item.TFID})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		conflictingEntities, err := yaml.Marshal(conflicts)
+		diags.AddError("Duplicate entities", util.Dedent(fmt.Sprintf(`
+			One or more entities specified in the resource already exist in ITSI and are not managed by this resource.
+			Entities modification will be aborted to prevent creating duplicate entities.
+			Conflicting entities:
+			%s
+		`, string(conflictingEntities))))
+		if err != nil {
+			diags.AddError(unexpectedErrorSummary, err.Error())
+		}
+	}
+	return
+}
+
+// deleteEntities deletes the entities with the given keys, as long as they belong to the given scope.
+func (r *resourceEntities) deleteEntities(ctx context.Context, scope string, keys []string) (diags diag.Diagnostics) {
+	base := entityBase(r.client, "", "")
+
+	for start := 0; start < len(keys); start += entitiesFilterBatchSize {
+		batch := keys[start:min(start+entitiesFilterBatchSize, len(keys))]
+		keyList := make([]map[string]string, len(batch))
+		for i, key := range batch {
+			keyList[i] = map[string]string{"_key": key}
+			models.Cache.Remove(entityBase(r.client, key, ""))
+		}
+		filter, err := json.Marshal(map[string]any{
+			"$and": []any{
+				map[string]any{"$or": keyList},
+				map[string]any{entitiesScopeField: scope},
+			},
+		})
+		if err != nil {
+			diags.AddError("Unable to delete entities", err.Error())
+			return
+		}
+
+		tflog.Trace(ctx, "itsi_entities - deleting entities", map[string]any{"scope": scope, "keys": batch})
+		if err = base.DeleteByFilter(ctx, string(filter)); err != nil {
+			diags.AddError("Unable to delete entities", err.Error())
+			return
+		}
+	}
+	return
+}
+
+// =================== [ Entities Resource CRUD ] ===================
+
+/*
+Custom plan handling for the entities resource:
+Populates planned entities' ID fields using respective IDs from the state, matching entities by title.
+This reduces the number of entities that are shown in the diff to only those that are actually changing.
+*/
+func (r *resourceEntities) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return // create or destroy plan, nothing to do
+	}
+
+	var state, plan entitiesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Entities.IsUnknown() {
+		resp.Diagnostics.AddWarning("unknown entities", "itsi_entities entities are known only after apply")
+		return
+	}
+
+	stateEntries, diags := state.entries(ctx)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	planEntries, diags := plan.entries(ctx)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	idByTitle := make(map[string]string)
+	for _, e := range stateEntries {
+		idByTitle[e.Title.ValueString()] = e.ID.ValueString()
+	}
+
+	for i := range planEntries {
+		if !planEntries[i].ID.IsUnknown() || planEntries[i].Title.IsUnknown() {
+			continue
+		}
+		if id, ok := idByTitle[planEntries[i].Title.ValueString()]; ok {
+			planEntries[i].ID = types.StringValue(id)
+		}
+	}
+
+	if resp.Diagnostics.Append(plan.setEntries(ctx, planEntries)...); resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+func (r *resourceEntities) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state entitiesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	timeouts := state.Timeouts
+	readTimeout, diags := timeouts.Read(ctx, tftimeout.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	entries, diags := r.readEntities(ctx, state.Scope.ValueString())
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if resp.Diagnostics.Append(state.setEntries(ctx, entries)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = state.Scope
+	state.Timeouts = timeouts
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *resourceEntities) createOrUpdate(ctx context.Context, plan, state entitiesModel, update bool) (newState entitiesModel, diags diag.Diagnostics) {
+	scope := plan.Scope.ValueString()
+	planEntries, d := plan.entries(ctx)
+	if diags.Append(d...); diags.HasError() {
+		return
+	}
+	stateEntries, d := state.entries(ctx)
+	if diags.Append(d...); diags.HasError() {
+		return
+	}
+
+	if diags.Append(new(entitiesSetValidator).ValidateTitleUniqueness(ctx, planEntries)...); diags.HasError() {
+		return
+	}
+	if !update {
+		if diags.Append(r.validateScopeUniqueness(ctx, scope)...); diags.HasError() {
+			return
+		}
+	}
+	if diags.Append(r.validateTitleOwnership(ctx, scope, planEntries)...); diags.HasError() {
+		return
+	}
+
+	builder := newAPIBuilder(r.client, &scopedEntityBuildWorkflow{scope: scope})
+
+	stateHashByID := make(map[string]string)
+	for _, e := range stateEntries {
+		obj, d := builder.build(ctx, e.entityModel())
+		if diags.Append(d...); diags.HasError() {
+			return
+		}
+		stateHashByID[obj.RESTKey] = obj.Hash
+	}
+
+	changed := []*models.ItsiObj{}
+	planIDs := util.NewSet[string]()
+	for i := range planEntries {
+		if planEntries[i].ID.IsUnknown() || planEntries[i].ID.ValueString() == "" {
+			planEntries[i].ID = types.StringValue(uuid.New().String())
+		}
+		obj, d := builder.build(ctx, planEntries[i].entityModel())
+		if diags.Append(d...); diags.HasError() {
+			return
+		}
+		planEntries[i].ID = types.StringValue(obj.RESTKey)
+		planIDs.Add(obj.RESTKey)
+
+		if hash, ok := stateHashByID[obj.RESTKey]; !ok || hash != obj.Hash {
+			changed = append(changed, obj)
+		}
+	}
+
+	removed := []string{}
+	for id := range stateHashByID {
+		if !planIDs.Contains(id) {
+			removed = append(removed, id)
+		}
+	}
+
+	tflog.Trace(ctx, "itsi_entities - applying changes", map[string]any{"scope": scope, "changed": len(changed), "removed": len(removed)})
+
+	if err := entityBase(r.client, "", "").BulkUpdate(ctx, changed); err != nil {
+		diags.AddError("Unable to bulk update entities", err.Error())
+		return
+	}
+
+	if diags.Append(r.deleteEntities(ctx, scope, removed)...); diags.HasError() {
+		return
+	}
+
+	newState = plan
+	newState.ID = plan.Scope
+	diags.Append(newState.setEntries(ctx, planEntries)...)
+	return
+}
+
+func (r *resourceEntities) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan entitiesModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, tftimeout.Create)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	state, diags := r.createOrUpdate(ctx, plan, entitiesModel{}, false)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *resourceEntities) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state entitiesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, tftimeout.Update)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	newState, diags := r.createOrUpdate(ctx, plan, state, true)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &newState)...)
+}
+
+func (r *resourceEntities) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state entitiesModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, tftimeout.Delete)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	entries, diags := state.entries(ctx)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if id := e.ID.ValueString(); id != "" {
+			keys = append(keys, id)
+		}
+	}
+
+	resp.Diagnostics.Append(r.deleteEntities(ctx, state.Scope.ValueString(), keys)...)
+}
+
+func (r *resourceEntities) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("scope"), req.ID)...)
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+func TestResourceEntitiesSchema(t *testing.T) {
+	testResourceSchema(t, new(resourceEntities))
+}
+
+func TestResourceEntitiesPlan(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: providerFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: util.Dedent(`
+					provider "itsi" {
+						host     = "itsi.example.com"
+						user     = "user"
+						password = "password"
+						port     = 8089
+						timeout  = 20
+					}
+
+					resource "itsi_entities" "test" {
+						scope = "example"
+
+						entity {
+							title       = "a.example.com"
+							description = "a.example.com host"
+							aliases = {
+								"host" = ["a.example.com"]
+							}
+						}
+
+						entity {
+							title = "b.example.com"
+							info = {
+								"env" = ["test"]
+							}
+						}
+					}
+				`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccCheckEntitiesExist(exist bool, titles ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, title := range titles {
+			ok, err := resourceExists(resourceNameEntity, title)
+			if err != nil {
+				return err
+			}
+			if ok != exist {
+				return fmt.Errorf("entity %s exists: %v, expected: %v", title, ok, exist)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccCheckEntitiesKeys checks that the entities of the resource were assigned distinct keys that exist in ITSI,
+// and collects the keys, so that their deletion can be checked once the resource is destroyed.
+func testAccCheckEntitiesKeys(resourceName string, count int, keys *[]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+
+		ids := util.NewSet[string]()
+		for attr, id := range rs.Primary.Attributes {
+			if !strings.HasPrefix(attr, "entity.") || !strings.HasSuffix(attr, ".id") {
+				continue
+			}
+			if id == "" {
+				return fmt.Errorf("%s: %s is empty", resourceName, attr)
+			}
+			if ids.Contains(id) {
+				return fmt.Errorf("%s: entity key %s is not unique", resourceName, id)
+			}
+			ids.Add(id)
+		}
+		if len(ids) != count {
+			return fmt.Errorf("%s: expected %d entity keys, got %d", resourceName, count, len(ids))
+		}
+
+		for id := range ids {
+			if err := testAccCheckEntityKeyExists(id, true); err != nil {
+				return err
+			}
+			*keys = append(*keys, id)
+		}
+		return nil
+	}
+}
+
+func testAccCheckEntityKeysExist(exist bool, keys *[]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, id := range *keys {
+			if err := testAccCheckEntityKeyExists(id, exist); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckEntityKeyExists(id string, exist bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	obj, err := models.NewItsiObj(clientConfig, id, "", string(resourceNameEntity)).Read(ctx)
+	if err != nil {
+		return err
+	}
+	if ok := obj != nil; ok != exist {
+		return fmt.Errorf("entity %s exists: %v, expected: %v", id, ok, exist)
+	}
+	return nil
+}
+
+func TestAccResourceEntitiesLifecycle(t *testing.T) {
+	t.Parallel()
+	var (
+		scope = testAccResourceTitle("ResourceEntitiesLifecycle")
+		hostA = testAccResourceTitle("ResourceEntitiesLifecycle_HostA")
+		hostB = testAccResourceTitle("ResourceEntitiesLifecycle_HostB")
+		hostC = testAccResourceTitle("ResourceEntitiesLifecycle_HostC")
+		keys  []string
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckEntitiesExist(false, hostA, hostB, hostC),
+			testAccCheckEntityKeysExist(false, &keys),
+		),
+		Steps: []resource.TestStep{
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("itsi_entities.test", "scope", scope),
+					resource.TestCheckResourceAttr("itsi_entities.test", "entity.#", "2"),
+					testAccCheckEntitiesExist(true, hostA, hostB),
+					testAccCheckEntitiesKeys("itsi_entities.test", 2, &keys),
+				),
+			},
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("itsi_entities.test", "entity.#", "2"),
+					testAccCheckEntitiesExist(true, hostA, hostC),
+					testAccCheckEntitiesExist(false, hostB),
+					testAccCheckEntitiesKeys("itsi_entities.test", 2, &keys),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
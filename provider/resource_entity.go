@@ -2,7 +2,9 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
@@ -11,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
@@ -27,8 +30,10 @@ const (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &resourceEntity{}
-	_ tfmodel           = &entityModel{}
+	_ resource.Resource                 = &resourceEntity{}
+	_ resource.ResourceWithUpgradeState = &resourceEntity{}
+	_ resource.ResourceWithModifyPlan   = &resourceEntity{}
+	_ tfmodel                           = &entityModel{}
 )
 
 // =================== [ Entity ] ===================
@@ -44,6 +49,8 @@ type entityModel struct {
 
 	EntityTypeIDs types.Set `tfsdk:"entity_type_ids"`
 
+	VitalMetrics types.List `tfsdk:"vital_metrics"`
+
 	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
@@ -55,6 +62,124 @@ func (m entityModel) title() string {
 	return m.Title.ValueString()
 }
 
+// entityFieldsType is the type of the entity aliases and info attributes: a map of field names to lists of values.
+var entityFieldsType = types.MapType{ElemType: types.ListType{ElemType: types.StringType}}
+
+// =================== [ Entity / Vital Metrics ] ===================
+
+type entityVitalMetricModel struct {
+	EntityTypeID types.String `tfsdk:"entity_type_id"`
+	MetricName   types.String `tfsdk:"metric_name"`
+	Search       types.String `tfsdk:"search"`
+	Filters      types.Map    `tfsdk:"filters"`
+}
+
+var entityVitalMetricType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"entity_type_id": types.StringType,
+	"metric_name":    types.StringType,
+	"search":         types.StringType,
+	"filters":        entityFieldsType,
+}}
+
+// resolveVitalMetrics populates the vital metrics of the entity's entity types,
+// whose matching_entity_fields can all be resolved using the entity aliases.
+func resolveVitalMetrics(ctx context.Context, client models.ClientConfig, m *entityModel) (diags diag.Diagnostics) {
+	var entityTypeIDs []string
+	var aliases map[string][]string
+	diags.Append(m.EntityTypeIDs.ElementsAs(ctx, &entityTypeIDs, false)...)
+	diags.Append(m.Aliases.ElementsAs(ctx, &aliases, false)...)
+	if diags.HasError() {
+		return
+	}
+	sort.Strings(entityTypeIDs)
+
+	entityTypes, d := entityTypesByKey(ctx, client, entityTypeIDs)
+	if diags.Append(d...); diags.HasError() {
+		return
+	}
+
+	vitalMetrics := []entityVitalMetricModel{}
+	for _, entityTypeID := range entityTypeIDs {
+		b, ok := entityTypes[entityTypeID]
+		if !ok || b.RawJson == nil {
+			continue
+		}
+
+		entityType, d := newAPIParser(b, new(entityTypeParseWorkflow)).parse(ctx, b)
+		if diags.Append(d...); diags.HasError() {
+			return
+		}
+
+		for _, vm := range entityType.VitalMetric {
+			matchingEntityFields, d := vm.getMatchingEntityFields(ctx)
+			if diags.Append(d...); diags.HasError() {
+				return
+			}
+
+			filters := map[string][]string{}
+			for alias, splitByField := range matchingEntityFields {
+				values, ok := aliases[alias]
+				if !ok {
+					break
+				}
+				filters[splitByField] = values
+			}
+			if len(filters) != len(matchingEntityFields) {
+				continue
+			}
+
+			vitalMetric := entityVitalMetricModel{
+				EntityTypeID: types.StringValue(entityTypeID),
+				MetricName:   vm.MetricName,
+				Search:       vm.Search,
+			}
+			vitalMetric.Filters, d = types.MapValueFrom(ctx, entityFieldsType.ElemType, filters)
+			if diags.Append(d...); diags.HasError() {
+				return
+			}
+			vitalMetrics = append(vitalMetrics, vitalMetric)
+		}
+	}
+
+	sort.SliceStable(vitalMetrics, func(i, j int) bool {
+		if vitalMetrics[i].EntityTypeID.ValueString() != vitalMetrics[j].EntityTypeID.ValueString() {
+			return vitalMetrics[i].EntityTypeID.ValueString() < vitalMetrics[j].EntityTypeID.ValueString()
+		}
+		return vitalMetrics[i].MetricName.ValueString() < vitalMetrics[j].MetricName.ValueString()
+	})
+
+	m.VitalMetrics, d = types.ListValueFrom(ctx, entityVitalMetricType, vitalMetrics)
+	diags.Append(d...)
+	return
+}
+
+// entityTypesByKey looks up the entity types with the given keys in a single request.
+func entityTypesByKey(ctx context.Context, client models.ClientConfig, keys []string) (entityTypes map[string]*models.ItsiObj, diags diag.Diagnostics) {
+	entityTypes = map[string]*models.ItsiObj{}
+	if len(keys) == 0 {
+		return
+	}
+
+	keyConditions := make([]any, len(keys))
+	for i, key := range keys {
+		keyConditions[i] = map[string]string{"_key": key}
+	}
+	filter, err := json.Marshal(map[string]any{"$or": keyConditions})
+	if err != nil {
+		diags.AddError("Unable to build entity type filter", err.Error())
+		return
+	}
+
+	for item, err := range entityTypeBase(client, "", "").Iter(ctx, &models.Parameters{Filter: string(filter)}) {
+		if err != nil {
+			diags.AddError("Unable to resolve entity vital metrics", err.Error())
+			return
+		}
+		entityTypes[item.RESTKey] = item
+	}
+	return
+}
+
 func entityBase(clientConfig models.ClientConfig, key string, title string) *models.ItsiObj {
 	base := models.NewItsiObj(clientConfig, key, title, itsiResourceTypeEntity)
 	return base
@@ -79,6 +204,7 @@ func (r *resourceEntity) Metadata(_ context.Context, req resource.MetadataReques
 func (r *resourceEntity) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manages an Entity object within ITSI.",
+		Version:             1,
 		Blocks: map[string]schema.Block{
 			"timeouts": timeouts.BlockAll(ctx),
 		},
@@ -101,18 +227,18 @@ func (r *resourceEntity) Schema(ctx context.Context, _ resource.SchemaRequest, r
 				Default:             stringdefault.StaticString(""),
 			},
 			"aliases": schema.MapAttribute{
-				MarkdownDescription: "Map of Field/Value pairs that identify the entity.",
-				ElementType:         types.StringType,
+				MarkdownDescription: "Map of fields to lists of values that identify the entity.",
+				ElementType:         entityFieldsType.ElemType,
 				Optional:            true,
 				Computed:            true,
-				Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				Default:             mapdefault.StaticValue(types.MapValueMust(entityFieldsType.ElemType, map[string]attr.Value{})),
 			},
 			"info": schema.MapAttribute{
-				MarkdownDescription: "Map of Field/Value pairs that provide information/description for the entity.",
-				ElementType:         types.StringType,
+				MarkdownDescription: "Map of fields to lists of values that provide information/description for the entity.",
+				ElementType:         entityFieldsType.ElemType,
 				Optional:            true,
 				Computed:            true,
-				Default:             mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				Default:             mapdefault.StaticValue(types.MapValueMust(entityFieldsType.ElemType, map[string]attr.Value{})),
 			},
 			"entity_type_ids": schema.SetAttribute{
 				MarkdownDescription: "A set of _key values for each entity type associated with the entity.",
@@ -121,10 +247,117 @@ func (r *resourceEntity) Schema(ctx context.Context, _ resource.SchemaRequest, r
 				Computed:            true,
 				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
 			},
+			"vital_metrics": schema.ListNestedAttribute{
+				MarkdownDescription: util.Dedent(`
+					Vital metrics of the entity's entity types that resolve for this entity,
+					i.e. all of the vital metric's matching_entity_fields are present in the entity aliases.
+				`),
+				Computed: true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"entity_type_id": schema.StringAttribute{
+							MarkdownDescription: "_key of the entity type that defines the vital metric.",
+							Computed:            true,
+						},
+						"metric_name": schema.StringAttribute{
+							MarkdownDescription: "The title of the vital metric.",
+							Computed:            true,
+						},
+						"search": schema.StringAttribute{
+							MarkdownDescription: "The search that computes the vital metric.",
+							Computed:            true,
+						},
+						"filters": schema.MapAttribute{
+							MarkdownDescription: "Map of the vital metric search split by fields to the respective entity alias values.",
+							ElementType:         entityFieldsType.ElemType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// =================== [ Entity / State Upgrade ] ===================
+
+type entityModelV0 struct {
+	ID            types.String   `tfsdk:"id"`
+	Title         types.String   `tfsdk:"title"`
+	Description   types.String   `tfsdk:"description"`
+	Aliases       types.Map      `tfsdk:"aliases"`
+	Info          types.Map      `tfsdk:"info"`
+	EntityTypeIDs types.Set      `tfsdk:"entity_type_ids"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *resourceEntity) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// v0: aliases and info were maps of comma separated values
+		0: {
+			PriorSchema: &schema.Schema{
+				Blocks: map[string]schema.Block{
+					"timeouts": timeouts.BlockAll(ctx),
+				},
+				Attributes: map[string]schema.Attribute{
+					"id":              schema.StringAttribute{Computed: true},
+					"title":           schema.StringAttribute{Required: true},
+					"description":     schema.StringAttribute{Optional: true, Computed: true},
+					"aliases":         schema.MapAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+					"info":            schema.MapAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+					"entity_type_ids": schema.SetAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior entityModelV0
+				if resp.Diagnostics.Append(req.State.Get(ctx, &prior)...); resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := entityModel{
+					ID:            prior.ID,
+					Title:         prior.Title,
+					Description:   prior.Description,
+					EntityTypeIDs: prior.EntityTypeIDs,
+					VitalMetrics:  types.ListNull(entityVitalMetricType),
+					Timeouts:      prior.Timeouts,
+				}
+
+				for v0, v1 := range map[*types.Map]*types.Map{&prior.Aliases: &state.Aliases, &prior.Info: &state.Info} {
+					fields, d := upgradeEntityFieldsV0(ctx, *v0)
+					if resp.Diagnostics.Append(d...); resp.Diagnostics.HasError() {
+						return
+					}
+					*v1 = fields
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			},
 		},
 	}
 }
 
+// upgradeEntityFieldsV0 converts a map of comma separated values into a map of lists.
+func upgradeEntityFieldsV0(ctx context.Context, v0 types.Map) (types.Map, diag.Diagnostics) {
+	if v0.IsNull() || v0.IsUnknown() {
+		return types.MapValueMust(entityFieldsType.ElemType, map[string]attr.Value{}), nil
+	}
+
+	var fields map[string]string
+	if diags := v0.ElementsAs(ctx, &fields, false); diags.HasError() {
+		return types.MapNull(entityFieldsType.ElemType), diags
+	}
+
+	res := make(map[string][]string, len(fields))
+	for k, v := range fields {
+		res[k] = strings.Split(v, ",")
+	}
+	return types.MapValueFrom(ctx, entityFieldsType.ElemType, res)
+}
+
 // =================== [ Entity API / Builder] ===================
 
 type entityBuildWorkflow struct{}
@@ -159,25 +392,21 @@ func (w *entityBuildWorkflow) entityTypes(ctx context.Context, obj entityModel)
 func (w *entityBuildWorkflow) fields(ctx context.Context, obj entityModel) (res map[string]any, diags diag.Diagnostics) {
 	idFields, infoFields := util.NewSet[string](), util.NewSet[string]()
 	idValues, infoValues := util.NewSetFromSlice([]string{obj.Title.ValueString()}), util.NewSet[string]()
-	var aliases, info map[string]string
+	var aliases, info map[string][]string
 	diags.Append(obj.Aliases.ElementsAs(ctx, &aliases, false)...)
 	diags.Append(obj.Info.ElementsAs(ctx, &info, false)...)
 
 	res = map[string]any{}
-	for k, v := range aliases {
-		res[k] = strings.Split(v, ",")
+	for k, values := range aliases {
+		res[k] = values
 		idFields.Add(k)
-		for _, value := range res[k].([]string) {
-			idValues.Add(value)
-		}
+		idValues.Add(values...)
 	}
 
-	for k, v := range info {
-		res[k] = strings.Split(v, ",")
+	for k, values := range info {
+		res[k] = values
 		infoFields.Add(k)
-		for _, value := range res[k].([]string) {
-			infoValues.Add(value)
-		}
+		infoValues.Add(values...)
 	}
 
 	res["identifier"] = map[string][]string{"fields": idFields.ToSlice(), "values": idValues.ToSlice()}
@@ -236,7 +465,7 @@ func (w *entityParseWorkflow) fields(ctx context.Context, fields map[string]any,
 	}
 
 	for tfField, itsiField := range map[*types.Map]string{&res.Aliases: "identifier", &res.Info: "informational"} {
-		tfMap := map[string]string{}
+		tfMap := map[string][]string{}
 
 		itsiObject := fieldsMap[itsiField]
 		for _, k := range itsiObject["fields"].([]any) {
@@ -254,10 +483,10 @@ func (w *entityParseWorkflow) fields(ctx context.Context, fields map[string]any,
 				diags.AddError("Unable to populate entity model", err.Error())
 				return
 			}
-			tfMap[k.(string)] = strings.Join(values, ",")
+			tfMap[k.(string)] = values
 		}
 
-		*tfField, d = types.MapValueFrom(ctx, types.StringType, tfMap)
+		*tfField, d = types.MapValueFrom(ctx, entityFieldsType.ElemType, tfMap)
 		if diags.Append(d...); diags.HasError() {
 			return
 		}
@@ -268,6 +497,24 @@ func (w *entityParseWorkflow) fields(ctx context.Context, fields map[string]any,
 
 // =================== [ Entity Resource CRUD ] ===================
 
+// ModifyPlan keeps the vital metrics known during plan, unless the aliases or the entity types they are resolved from change.
+func (r *resourceEntity) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return // create or destroy plan, nothing to do
+	}
+
+	var state, plan entityModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Aliases.Equal(state.Aliases) || !plan.EntityTypeIDs.Equal(state.EntityTypeIDs) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vital_metrics"), types.ListUnknown(entityVitalMetricType))...)
+	}
+}
+
 func (r *resourceEntity) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state entityModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -296,6 +543,9 @@ func (r *resourceEntity) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
+	if resp.Diagnostics.Append(resolveVitalMetrics(ctx, r.client, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
 
 	state.Timeouts = timeouts
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -326,6 +576,9 @@ func (r *resourceEntity) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	plan.ID = types.StringValue(base.RESTKey)
+	if resp.Diagnostics.Append(resolveVitalMetrics(ctx, r.client, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 
 }
@@ -361,6 +614,9 @@ func (r *resourceEntity) Update(ctx context.Context, req resource.UpdateRequest,
 		resp.Diagnostics.AddError("Unable to update entity", err.Error())
 		return
 	}
+	if resp.Diagnostics.Append(resolveVitalMetrics(ctx, r.client, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -407,6 +663,9 @@ func (r *resourceEntity) ImportState(ctx context.Context, req resource.ImportSta
 	if resp.Diagnostics.Append(diags...); diags.HasError() {
 		return
 	}
+	if resp.Diagnostics.Append(resolveVitalMetrics(ctx, r.client, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
 
 	var timeouts timeouts.Value
 	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("timeouts"), &timeouts)...)
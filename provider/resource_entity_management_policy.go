@@ -0,0 +1,521 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+var inactivityPeriodRegexp = regexp.MustCompile(`^([1-9]\d*)([mhd])$`)
+
+const (
+	itsiResourceTypeEntityManagementPolicy = "entity_management_policy"
+
+	entityManagementPolicyActionRetire = "retire"
+	entityManagementPolicyActionDelete = "delete"
+
+	entityManagementPolicyDefaultCronSchedule = "0 * * * *"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &resourceEntityManagementPolicy{}
+	_ resource.ResourceWithImportState = &resourceEntityManagementPolicy{}
+	_ resource.ResourceWithModifyPlan  = &resourceEntityManagementPolicy{}
+	_ tfmodel                          = &entityManagementPolicyModel{}
+)
+
+// =================== [ Entity Management Policy ] ===================
+
+type entityManagementPolicyModel struct {
+	ID          types.String `tfsdk:"id"`
+	Title       types.String `tfsdk:"title"`
+	Description types.String `tfsdk:"description"`
+	Enabled     types.Bool   `tfsdk:"enabled"`
+
+	EntityRules      []EntityRuleState `tfsdk:"entity_rules"`
+	InactivityPeriod types.String      `tfsdk:"inactivity_period"`
+	Action           types.String      `tfsdk:"action"`
+	CronSchedule     types.String      `tfsdk:"cron_schedule"`
+
+	MatchingEntityCount types.Int64 `tfsdk:"matching_entity_count"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (m entityManagementPolicyModel) objectype() string {
+	return itsiResourceTypeEntityManagementPolicy
+}
+
+func (m entityManagementPolicyModel) title() string {
+	return m.Title.ValueString()
+}
+
+func (m entityManagementPolicyModel) entityRulesKnown() bool {
+	for _, group := range m.EntityRules {
+		for _, rule := range group.Rule {
+			if rule.Field.IsUnknown() || rule.FieldType.IsUnknown() || rule.RuleType.IsUnknown() || rule.Value.IsUnknown() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// entityRulesHash returns an order-independent representation of the policy entity rules.
+func (m entityManagementPolicyModel) entityRulesHash() string {
+	groups := make([]string, 0, len(m.EntityRules))
+	for _, group := range m.EntityRules {
+		rules := make([]string, 0, len(group.Rule))
+		for _, rule := range group.Rule {
+			rules = append(rules, strings.Join([]string{rule.Field.String(), rule.FieldType.String(), rule.RuleType.String(), rule.Value.String()}, "|"))
+		}
+		slices.Sort(rules)
+		groups = append(groups, strings.Join(rules, "&"))
+	}
+	slices.Sort(groups)
+	return util.Sha256([]byte(strings.Join(groups, "||")))
+}
+
+func entityManagementPolicyBase(clientConfig models.ClientConfig, key string, title string) *models.ItsiObj {
+	base := models.NewItsiObj(clientConfig, key, title, itsiResourceTypeEntityManagementPolicy)
+	return base
+}
+
+type resourceEntityManagementPolicy struct {
+	client models.ClientConfig
+}
+
+func NewResourceEntityManagementPolicy() resource.Resource {
+	return &resourceEntityManagementPolicy{}
+}
+
+func (r *resourceEntityManagementPolicy) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	configureResourceClient(ctx, resourceNameEntityManagementPolicy, req, &r.client, resp)
+}
+
+func (r *resourceEntityManagementPolicy) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	configureResourceMetadata(req, resp, resourceNameEntityManagementPolicy)
+}
+
+func (r *resourceEntityManagementPolicy) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an Entity Management Policy object within ITSI, used to retire or delete inactive entities.",
+		Blocks: map[string]schema.Block{
+			"entity_rules": entityRulesSchema(),
+			"timeouts":     timeouts.BlockAll(ctx),
+		},
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID of the entity management policy.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "Name of the entity management policy.",
+				Required:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "User defined description of the entity management policy.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the policy is enabled. Defaults to `false`, so that `matching_entity_count` can be reviewed before the policy takes effect.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"inactivity_period": schema.StringAttribute{
+				MarkdownDescription: "Period of inactivity after which a matching entity is considered stale, e.g. `12h` or `7d`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(inactivityPeriodRegexp, "must be a positive number followed by a time unit: m (minutes), h (hours) or d (days)"),
+				},
+			},
+			"action": schema.StringAttribute{
+				MarkdownDescription: "Action applied to stale entities. Must be one of `retire` or `delete`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(entityManagementPolicyActionRetire, entityManagementPolicyActionDelete),
+				},
+			},
+			"cron_schedule": schema.StringAttribute{
+				MarkdownDescription: "Cron schedule for evaluating the policy.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(entityManagementPolicyDefaultCronSchedule),
+			},
+			"matching_entity_count": schema.Int64Attribute{
+				MarkdownDescription: "Preview of the number of entities that currently match the policy's entity rules and have been inactive for at least `inactivity_period`. " +
+					"Inactivity is assumed from the entity `mod_timestamp`, i.e. the last time the entity was updated, which approximates ITSI's own evaluation of the policy. " +
+					"The preview is computed when the policy is created, or its entity rules or inactivity period change, and is not refreshed otherwise.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// =================== [ Entity Management Policy API / Builder] ===================
+
+type entityManagementPolicyBuildWorkflow struct{}
+
+var _ apibuildWorkflow[entityManagementPolicyModel] = &entityManagementPolicyBuildWorkflow{}
+
+//lint:ignore U1000 used by apibuilder
+func (w *entityManagementPolicyBuildWorkflow) buildSteps() []apibuildWorkflowStepFunc[entityManagementPolicyModel] {
+	return []apibuildWorkflowStepFunc[entityManagementPolicyModel]{
+		w.basics,
+		w.entityRules,
+	}
+}
+
+func (w *entityManagementPolicyBuildWorkflow) basics(ctx context.Context, obj entityManagementPolicyModel) (map[string]any, diag.Diagnostics) {
+	return map[string]any{
+		"object_type":       itsiResourceTypeEntityManagementPolicy,
+		"sec_grp":           itsiDefaultSecurityGroup,
+		"title":             obj.Title.ValueString(),
+		"description":       obj.Description.ValueString(),
+		"disabled":          util.Btoi(!obj.Enabled.ValueBool()),
+		"inactivity_period": obj.InactivityPeriod.ValueString(),
+		"action":            obj.Action.ValueString(),
+		"cron_schedule":     obj.CronSchedule.ValueString(),
+	}, nil
+}
+
+func (w *entityManagementPolicyBuildWorkflow) entityRules(ctx context.Context, obj entityManagementPolicyModel) (map[string]any, diag.Diagnostics) {
+	itsiEntityRules, diags := buildEntityRules(obj.EntityRules)
+	return map[string]any{"entity_rules": itsiEntityRules}, diags
+}
+
+// =================== [ Entity Management Policy API / Parser ] ===================
+
+type entityManagementPolicyParseWorkflow struct{}
+
+var _ apiparseWorkflow[entityManagementPolicyModel] = &entityManagementPolicyParseWorkflow{}
+
+//lint:ignore U1000 used by apiparser
+func (w *entityManagementPolicyParseWorkflow) parseSteps() []apiparseWorkflowStepFunc[entityManagementPolicyModel] {
+	return []apiparseWorkflowStepFunc[entityManagementPolicyModel]{
+		w.basics,
+		w.entityRules,
+	}
+}
+
+func (w *entityManagementPolicyParseWorkflow) basics(ctx context.Context, fields map[string]any, res *entityManagementPolicyModel) (diags diag.Diagnostics) {
+	stringMap, err := unpackMap[string](mapSubset(fields, []string{"title", "description", "inactivity_period", "action", "cron_schedule"}))
+	if err != nil {
+		diags.AddError("Unable to populate entity management policy model", err.Error())
+		return
+	}
+	res.Title = types.StringValue(stringMap["title"])
+	res.Description = types.StringValue(stringMap["description"])
+	res.InactivityPeriod = types.StringValue(stringMap["inactivity_period"])
+	res.Action = types.StringValue(stringMap["action"])
+	res.CronSchedule = types.StringValue(stringMap["cron_schedule"])
+
+	switch disabled := fields["disabled"].(type) {
+	case bool:
+		res.Enabled = types.BoolValue(!disabled)
+	case float64:
+		res.Enabled = types.BoolValue(disabled == 0)
+	default:
+		res.Enabled = types.BoolValue(false)
+	}
+	return
+}
+
+func (w *entityManagementPolicyParseWorkflow) entityRules(ctx context.Context, fields map[string]any, res *entityManagementPolicyModel) (diags diag.Diagnostics) {
+	res.EntityRules, diags = parseEntityRules(fields["entity_rules"])
+	return
+}
+
+// =================== [ Entity Management Policy / Preview ] ===================
+
+// matchingEntityCount returns the number of entities that currently match the policy entity rules,
+// and have been inactive for at least the policy inactivity period.
+func (r *resourceEntityManagementPolicy) matchingEntityCount(ctx context.Context, m entityManagementPolicyModel) (count types.Int64, diags diag.Diagnostics) {
+	inactivityPeriod, err := parseInactivityPeriod(m.InactivityPeriod.ValueString())
+	if err != nil {
+		diags.AddError("Invalid inactivity period", err.Error())
+		return types.Int64Null(), diags
+	}
+
+	filter, diags := entityRulesFilter(ctx, r.client, m.EntityRules)
+	if diags.HasError() {
+		return types.Int64Null(), diags
+	}
+	if filter == "" {
+		// a policy with no entity rules doesn't match any entities
+		return types.Int64Value(0), diags
+	}
+
+	// counted by ITSI, rather than listing every matching entity on each plan
+	n, err := entityBase(r.client, "", "").Count(ctx, inactiveEntitiesFilter(filter, time.Now().Add(-inactivityPeriod)))
+	if err != nil {
+		diags.AddError("Unable to count matching entities", err.Error())
+		return types.Int64Null(), diags
+	}
+	return types.Int64Value(int64(n)), diags
+}
+
+// parseInactivityPeriod parses an inactivity period, e.g. 30m, 12h or 7d.
+func parseInactivityPeriod(period string) (time.Duration, error) {
+	m := inactivityPeriodRegexp.FindStringSubmatch(period)
+	if m == nil {
+		return 0, fmt.Errorf("invalid inactivity period %q: must be a positive number followed by a time unit: m (minutes), h (hours) or d (days)", period)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, err
+	}
+	unit := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}[m[2]]
+	return time.Duration(n) * unit, nil
+}
+
+// inactiveEntitiesFilter restricts an entity filter to the entities last updated before the given time.
+// The inactivity of an entity is assumed from its mod_timestamp, i.e. the last time it was updated, e.g. by an entity
+// discovery search; this approximates, but is not, ITSI's own evaluation of the policy.
+// Entities without mod_timestamp are not counted.
+func inactiveEntitiesFilter(filter string, since time.Time) string {
+	return fmt.Sprintf(`{"$and":[%s,{"mod_timestamp":{"$lt":%d}}]}`, filter, since.Unix())
+}
+
+// previewMatchingEntityCount is a non-fatal version of matchingEntityCount:
+// any errors are reported as warnings, since the entity count is informational only.
+func (r *resourceEntityManagementPolicy) previewMatchingEntityCount(ctx context.Context, m entityManagementPolicyModel) (count types.Int64, diags diag.Diagnostics) {
+	count, d := r.matchingEntityCount(ctx, m)
+	for _, e := range d {
+		if e.Severity() == diag.SeverityError {
+			diags.AddWarning("Unable to preview entities matching the entity management policy", fmt.Sprintf("%s: %s", e.Summary(), e.Detail()))
+		} else {
+			diags.Append(e)
+		}
+	}
+	return
+}
+
+// =================== [ Entity Management Policy Resource CRUD ] ===================
+
+/*
+Custom plan handling for entity management policies:
+computes the matching entity count preview whenever the policy is created or its entity rules change,
+so that the effect of the policy can be reviewed during plan.
+*/
+func (r *resourceEntityManagementPolicy) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return // destroy plan, nothing to do
+	}
+
+	var plan entityManagementPolicyModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+	if !plan.entityRulesKnown() || plan.InactivityPeriod.IsUnknown() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state entityManagementPolicyModel
+		if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+			return
+		}
+		if state.entityRulesHash() == plan.entityRulesHash() && state.InactivityPeriod.Equal(plan.InactivityPeriod) {
+			plan.MatchingEntityCount = state.MatchingEntityCount
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+			return
+		}
+	}
+
+	count, diags := r.previewMatchingEntityCount(ctx, plan)
+	if resp.Diagnostics.Append(diags...); count.IsNull() {
+		return
+	}
+	plan.MatchingEntityCount = count
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *resourceEntityManagementPolicy) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state entityManagementPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	timeouts := state.Timeouts
+	readTimeout, diags := timeouts.Read(ctx, tftimeout.Read)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	base := entityManagementPolicyBase(r.client, state.ID.ValueString(), state.Title.ValueString())
+	b, err := base.Find(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read entity management policy", err.Error())
+		return
+	}
+	if b == nil || b.RawJson == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// the preview is only computed when the policy is created or its entity rules change, not on every refresh
+	matchingEntityCount := state.MatchingEntityCount
+	state, diags = newAPIParser(b, new(entityManagementPolicyParseWorkflow)).parse(ctx, b)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	state.MatchingEntityCount = matchingEntityCount
+
+	state.Timeouts = timeouts
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *resourceEntityManagementPolicy) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan entityManagementPolicyModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, tftimeout.Create)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	base, diags := newAPIBuilder(r.client, new(entityManagementPolicyBuildWorkflow)).build(ctx, plan)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	base, err := base.Create(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create entity management policy", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(base.RESTKey)
+	if plan.MatchingEntityCount.IsUnknown() {
+		plan.MatchingEntityCount, diags = r.previewMatchingEntityCount(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *resourceEntityManagementPolicy) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan entityManagementPolicyModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, tftimeout.Update)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	base, diags := newAPIBuilder(r.client, new(entityManagementPolicyBuildWorkflow)).build(ctx, plan)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	existing, err := base.Find(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to update entity management policy", err.Error())
+		return
+	}
+	if existing == nil {
+		resp.Diagnostics.AddError("Unable to update entity management policy", "entity management policy not found")
+		return
+	}
+	if err := base.Update(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to update entity management policy", err.Error())
+		return
+	}
+
+	if plan.MatchingEntityCount.IsUnknown() {
+		plan.MatchingEntityCount, diags = r.previewMatchingEntityCount(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *resourceEntityManagementPolicy) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state entityManagementPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, tftimeout.Delete)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	base := entityManagementPolicyBase(r.client, state.ID.ValueString(), state.Title.ValueString())
+	b, err := base.Find(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to delete entity management policy", err.Error())
+		return
+	}
+	if b == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(b.Delete(ctx)...)
+}
+
+func (r *resourceEntityManagementPolicy) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, cancel := context.WithTimeout(ctx, tftimeout.Read)
+	defer cancel()
+
+	b := entityManagementPolicyBase(r.client, "", req.ID)
+	b, err := b.Find(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to find entity management policy model", err.Error())
+		return
+	}
+	if b == nil {
+		resp.Diagnostics.AddError("Entity management policy not found", fmt.Sprintf("Entity management policy '%s' not found", req.ID))
+		return
+	}
+
+	state, diags := newAPIParser(b, new(entityManagementPolicyParseWorkflow)).parse(ctx, b)
+	if resp.Diagnostics.Append(diags...); diags.HasError() {
+		return
+	}
+
+	state.MatchingEntityCount, diags = r.previewMatchingEntityCount(ctx, state)
+	resp.Diagnostics.Append(diags...)
+
+	var timeouts timeouts.Value
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("timeouts"), &timeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Timeouts = timeouts
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+func TestResourceEntityManagementPolicySchema(t *testing.T) {
+	testResourceSchema(t, new(resourceEntityManagementPolicy))
+}
+
+func TestEntityManagementPolicyInactivity(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		period   string
+		expected string
+	}{
+		{"30m", `{"$and":[{"title":{"$regex":"^a"}},{"mod_timestamp":{"$lt":1715340600}}]}`},
+		{"12h", `{"$and":[{"title":{"$regex":"^a"}},{"mod_timestamp":{"$lt":1715299200}}]}`},
+		{"7d", `{"$and":[{"title":{"$regex":"^a"}},{"mod_timestamp":{"$lt":1714737600}}]}`},
+	} {
+		period, err := parseInactivityPeriod(test.period)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := inactiveEntitiesFilter(`{"title":{"$regex":"^a"}}`, now.Add(-period)); actual != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.period, test.expected, actual)
+		}
+	}
+
+	if _, err := parseInactivityPeriod("0d"); err == nil {
+		t.Error("expected an error for a zero inactivity period")
+	}
+}
+
+func TestResourceEntityManagementPolicyPlan(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: providerFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: util.Dedent(`
+					provider "itsi" {
+						host     = "itsi.example.com"
+						user     = "user"
+						password = "password"
+						port     = 8089
+						timeout  = 20
+					}
+
+					resource "itsi_entity_management_policy" "test" {
+						title             = "autoscaling hosts cleanup"
+						inactivity_period = "7d"
+						action            = "retire"
+
+						entity_rules {
+							rule {
+								field      = "host"
+								field_type = "alias"
+								rule_type  = "matches"
+								value      = "ip-10-*"
+							}
+						}
+					}
+				`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceEntityManagementPolicyLifecycle(t *testing.T) {
+	t.Parallel()
+	var title = testAccResourceTitle("ResourceEntityManagementPolicyLifecycle")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		CheckDestroy: testAccCheckResourceDestroy(resourceNameEntityManagementPolicy, title),
+		Steps: []resource.TestStep{
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("itsi_entity_management_policy.test", "title", title),
+					resource.TestCheckResourceAttr("itsi_entity_management_policy.test", "action", "retire"),
+					resource.TestCheckResourceAttr("itsi_entity_management_policy.test", "enabled", "false"),
+					resource.TestCheckResourceAttrSet("itsi_entity_management_policy.test", "matching_entity_count"),
+					testAccCheckResourceExists(resourceNameEntityManagementPolicy, title),
+				),
+			},
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("itsi_entity_management_policy.test", "action", "delete"),
+					resource.TestCheckResourceAttr("itsi_entity_management_policy.test", "inactivity_period", "30d"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
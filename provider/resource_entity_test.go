@@ -1,8 +1,12 @@
 package provider
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/config"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
@@ -34,12 +38,12 @@ func TestResourceEntityPlan(t *testing.T) {
 						description = "example.com host"
 
 						aliases = {
-							"entityTitle" = "example"
+							"entityTitle" = ["example"]
 						}
 
 						info = {
-							"env" : "test"
-							"entityType" : "123"
+							"env" : ["test"]
+							"entityType" : ["123"]
 						}
 					}
 				`),
@@ -50,6 +54,35 @@ func TestResourceEntityPlan(t *testing.T) {
 	})
 }
 
+func TestUpgradeEntityFieldsV0(t *testing.T) {
+	ctx := context.Background()
+	v0 := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"host": types.StringValue("example.com"),
+		"ip":   types.StringValue("10.0.0.1,10.0.0.2"),
+	})
+
+	v1, diags := upgradeEntityFieldsV0(ctx, v0)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %s", diags)
+	}
+
+	var fields map[string][]string
+	if diags = v1.ElementsAs(ctx, &fields, false); diags.HasError() {
+		t.Fatalf("unexpected error: %s", diags)
+	}
+	expected := map[string][]string{
+		"host": {"example.com"},
+		"ip":   {"10.0.0.1", "10.0.0.2"},
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+
+	if v1, _ = upgradeEntityFieldsV0(ctx, types.MapNull(types.StringType)); len(v1.Elements()) != 0 || v1.IsNull() {
+		t.Errorf("expected an empty map for a null v0 value, got %v", v1)
+	}
+}
+
 func TestAccResourceEntityLifecycle(t *testing.T) {
 	t.Parallel()
 	var testAccEntityLifecycle_entityTitle = testAccResourceTitle("ResourceEntityLifecycle_ExampleHost")
@@ -72,6 +105,9 @@ func TestAccResourceEntityLifecycle(t *testing.T) {
 				ConfigDirectory:          config.TestStepDirectory(),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("itsi_entity.test", "description", "TEST DESCRIPTION update"),
+					resource.TestCheckResourceAttr("itsi_entity.test", "aliases.ip.#", "2"),
+					resource.TestCheckResourceAttr("itsi_entity.test", "aliases.ip.0", "10.0.0.2"),
+					resource.TestCheckResourceAttr("itsi_entity.test", "info.location.0", "Alviso, CA"),
 				),
 				ConfigPlanChecks: resource.ConfigPlanChecks{
 					PostApplyPostRefresh: []plancheck.PlanCheck{
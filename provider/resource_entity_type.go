@@ -35,8 +35,9 @@ const (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &resourceEntityType{}
-	_ tfmodel           = &entityTypeModel{}
+	_ resource.Resource               = &resourceEntityType{}
+	_ resource.ResourceWithModifyPlan = &resourceEntityType{}
+	_ tfmodel                         = &entityTypeModel{}
 )
 
 // =================== [ Entity Type ] ===================
@@ -311,8 +312,11 @@ func (r *resourceEntityType) vitalMetricSchema() schema.SetNestedBlock {
 								Required: true,
 							},
 							"cron_schedule": schema.StringAttribute{
-								MarkdownDescription: "Frequency of the alert search",
+								MarkdownDescription: "Frequency of the alert search, as a 5-field cron expression.",
 								Required:            true,
+								Validators: []validator.String{
+									stringvalidatorIsCron(),
+								},
 							},
 							"is_enabled": schema.BoolAttribute{
 								MarkdownDescription: "Indicates if the alert rule is enabled.",
@@ -321,10 +325,13 @@ func (r *resourceEntityType) vitalMetricSchema() schema.SetNestedBlock {
 								Default:             booldefault.StaticBool(false),
 							},
 							"suppress_time": schema.StringAttribute{
-								MarkdownDescription: "Frequency of the alert search",
+								MarkdownDescription: "How long to suppress the alert after it triggers, either in seconds or as a Splunk relative time modifier (e.g. 30m).",
 								Optional:            true,
 								Computed:            true,
 								Default:             stringdefault.StaticString("0"),
+								Validators: []validator.String{
+									stringvalidatorIsSplunkRelativeTime(),
+								},
 							},
 						},
 					},
@@ -879,6 +886,34 @@ func (w *entityTypeParseWorkflow) vitalMetrics(ctx context.Context, fields map[s
 
 // =================== [ Entity Type Resource CRUD ] ===================
 
+// ModifyPlan syntax-checks the vital metric searches against Splunk, if enabled in the provider configuration.
+func (r *resourceEntityType) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || !r.client.ValidateSearches {
+		return
+	}
+
+	var vitalMetrics types.Set
+	if resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("vital_metric"), &vitalMetrics)...); resp.Diagnostics.HasError() {
+		return
+	}
+	if vitalMetrics.IsNull() || vitalMetrics.IsUnknown() {
+		return
+	}
+
+	for _, elem := range vitalMetrics.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsUnknown() {
+			continue
+		}
+		search, ok := obj.Attributes()["search"].(types.String)
+		if !ok || search.IsUnknown() || search.IsNull() {
+			continue
+		}
+		searchPath := path.Root("vital_metric").AtSetValue(obj).AtName("search")
+		resp.Diagnostics.Append(validateSearchSyntax(ctx, r.client, search.ValueString(), searchPath)...)
+	}
+}
+
 func (r *resourceEntityType) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state entityTypeModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
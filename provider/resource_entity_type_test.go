@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/config"
@@ -115,6 +117,63 @@ func TestResourceEntityTypePlan(t *testing.T) {
 	})
 }
 
+func TestResourceEntityTypePlanInvalidAlertRule(t *testing.T) {
+	planConfig := func(cronSchedule, suppressTime string) string {
+		return fmt.Sprintf(util.Dedent(`
+			provider "itsi" {
+				host     = "itsi.example.com"
+				user     = "user"
+				password = "password"
+				port     = 8089
+				timeout  = 20
+			}
+
+			resource "itsi_entity_type" "test" {
+			  title = "Invalid alert rule"
+
+			  vital_metric {
+			    is_key = true
+			    matching_entity_fields = {
+			      host = "host"
+			    }
+			    metric_name = "Average CPU Usage"
+			    search      = "| mstats avg(cpu.usage) as val WHERE 1=1 by host span=5m"
+
+			    alert_rule {
+			      critical_threshold = 90
+			      warning_threshold  = 75
+			      cron_schedule      = %q
+			      suppress_time      = %q
+			    }
+			  }
+			}
+		`), cronSchedule, suppressTime)
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: providerFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      planConfig("*/10 * * *", "30m"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Cron expression validation failed`),
+			},
+			{
+				Config:      planConfig("*/10 * * * *", "1 hour"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Splunk relative time validation failed`),
+			},
+			{
+				Config:             planConfig("*/10 * * * *", "3600"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
 func TestAccResourceEntityTypeLifecycle(t *testing.T) {
 	t.Parallel()
 	//var testAccEntityTypeLifecycle_entityTypeTitle = testAccResourceTitle("ResourceEntityTypeLifecycle_kubernetes_pod") //TODO: find out / fix the reason for why this doesn't work..
@@ -3,7 +3,9 @@ package provider
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/go-uuid"
@@ -14,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -21,6 +24,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
 )
 
 var _ validator.String = baseSearchValidator{}
@@ -29,6 +33,21 @@ const (
 	itsiResourceKpiBaseSearch = "kpi_base_search"
 )
 
+var (
+	// KPI base search fields that are copied into every linked service KPI.
+	kpiBaseSearchKpiFields = []string{
+		"base_search", "is_entity_breakdown", "is_service_entity_filter", "entity_breakdown_id_fields",
+		"entity_id_fields", "alert_period", "alert_lag", "search_alert_earliest",
+	}
+	// KPI base search metric fields that are copied into every service KPI linked to the metric.
+	kpiBaseSearchMetricKpiFields = []string{
+		"aggregate_statop", "entity_statop", "fill_gaps", "gap_custom_alert_value", "gap_severity",
+		"gap_severity_color", "gap_severity_color_light", "gap_severity_value", "threshold_field", "unit",
+	}
+
+	kpiBaseSearchStatOpRegexp = regexp.MustCompile("(avg|count|dc|earliest|latest|max|median|min|stdev|sum|perc*)")
+)
+
 type baseSearchValidator struct{}
 
 // Description describes the validation in plain text formatting.
@@ -91,6 +110,8 @@ type KpiBaseSearchState struct {
 	SearchAlertEarliest        types.String `tfsdk:"search_alert_earliest" json:"search_alert_earliest"`
 	SecGrp                     types.String `tfsdk:"sec_grp" json:"sec_grp"`
 	SourceItsiDa               types.String `tfsdk:"source_itsi_da" json:"source_itsi_da"`
+	PropagateToServices        types.Bool   `tfsdk:"propagate_to_services"`
+	AllowOrphanedMetrics       types.Bool   `tfsdk:"allow_orphaned_metrics"`
 
 	Metrics []Metric `tfsdk:"metrics"`
 
@@ -212,7 +233,7 @@ func (r *resourceKpiBaseSearch) ModifyPlan(ctx context.Context, req resource.Mod
 				metricState.ID = metricToRemap.ID
 				delete(oldMetricsByTitle, metricState.Title.ValueString())
 			}
-		} else {
+		} else if oldMetric, ok := oldMetricsByTitle[metricState.Title.ValueString()]; ok && oldMetric.ID.Equal(metricState.ID) {
 			delete(oldMetricsByTitle, metricState.Title.ValueString())
 		}
 
@@ -236,19 +257,201 @@ func (r *resourceKpiBaseSearch) ModifyPlan(ctx context.Context, req resource.Mod
 	plan.Metrics = planMetrics
 
 	if len(oldMetricsByTitle) > 0 {
-		filter := []string{}
-		for _, metricToCheckLinking := range oldMetricsByTitle {
-			filter = append(filter, fmt.Sprintf("{\"kpis.base_search_metric\": %s}", metricToCheckLinking.ID))
+		references, diags := r.metricReferences(ctx, state.ID.ValueString(), oldMetricsByTitle)
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+			return
+		}
+
+		if len(references) > 0 {
+			summary := fmt.Sprintf("%s KPI BS metrics to be removed are referenced by %d service KPI(s)", state.Title.ValueString(), len(references))
+			detail := strings.Join(references, "\n")
+			if plan.AllowOrphanedMetrics.ValueBool() {
+				resp.Diagnostics.AddWarning(summary, detail)
+			} else {
+				resp.Diagnostics.AddError(summary, detail+"\n\nRelink these KPIs first, or set allow_orphaned_metrics = true to proceed anyway.")
+			}
 		}
+	}
 
-		abortLinkedKpis(fmt.Sprintf("{\"$and\": [{\"kpis.base_search_id\":%s}, {\"$or\":[%s]}]}",
-			state.ID, strings.Join(filter, ",")))
+	if plan.PropagateToServices.ValueBool() && kpiBaseSearchKpiFieldsChanged(state, plan) {
+		params.Filter = fmt.Sprintf("{\"kpis.base_search_id\":%s}", state.ID)
+		items, err := base.Dump(ctx, &params)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to check linked KPIs", err.Error())
+		} else if len(items) > 0 {
+			services := []string{}
+			for _, item := range items {
+				services = append(services, fmt.Sprintf("_key=%s title=%s", item.RESTKey, item.TFID))
+			}
+			resp.Diagnostics.AddWarning(
+				fmt.Sprintf("%s KPI BS changes will be propagated to %d linked service(s)", state.Title.ValueString(), len(items)),
+				strings.Join(services, "\n"))
+		}
 	}
 	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
 	tflog.Trace(ctx, "Finished modifying plan for collecton data resource")
 
 }
 
+// metricReferences returns a description of every service KPI that references one of the given metrics of the KPI base search.
+func (r *resourceKpiBaseSearch) metricReferences(ctx context.Context, kbsID string, metricsByTitle map[string]Metric) (references []string, diags diag.Diagnostics) {
+	metricTitlesByID := map[string]string{}
+	filter := []string{}
+	for title, metric := range metricsByTitle {
+		metricTitlesByID[metric.ID.ValueString()] = title
+		filter = append(filter, fmt.Sprintf("{\"kpis.base_search_metric\": %q}", metric.ID.ValueString()))
+	}
+
+	base := models.NewItsiObj(r.client, "", "", "service")
+	params := &models.Parameters{
+		Filter: fmt.Sprintf("{\"$and\": [{\"kpis.base_search_id\":%q}, {\"$or\":[%s]}]}", kbsID, strings.Join(filter, ",")),
+	}
+	for service, err := range base.Iter(ctx, params) {
+		if err != nil {
+			diags.AddError("Failed to check linked KPIs", err.Error())
+			return
+		}
+
+		fields, err := service.RawJson.ToInterfaceMap()
+		if err != nil {
+			diags.AddError("Failed to check linked KPIs", err.Error())
+			return
+		}
+		kpis, err := UnpackSlice[map[string]any](fields["kpis"])
+		if err != nil {
+			diags.AddError("Failed to check linked KPIs", err.Error())
+			return
+		}
+
+		for _, kpi := range kpis {
+			metricID, _ := kpi["base_search_metric"].(string)
+			metricTitle, ok := metricTitlesByID[metricID]
+			if kpi["base_search_id"] != kbsID || !ok {
+				continue
+			}
+			references = append(references, fmt.Sprintf("service %q (_key=%s): KPI %q (_key=%v) references metric %q (_key=%s)",
+				service.TFID, service.RESTKey, kpi["title"], kpi["_key"], metricTitle, metricID))
+		}
+	}
+
+	sort.Strings(references)
+	return
+}
+
+// kpiBaseSearchKpiFieldsChanged reports whether any of the fields copied into linked service KPIs differ between state and plan.
+func kpiBaseSearchKpiFieldsChanged(state, plan KpiBaseSearchState) bool {
+	stateFields, planFields := map[string]any{}, map[string]any{}
+	marshalBasicTypesByTag("json", &state, stateFields)
+	marshalBasicTypesByTag("json", &plan, planFields)
+	for _, field := range kpiBaseSearchKpiFields {
+		if stateFields[field] != planFields[field] {
+			return true
+		}
+	}
+
+	stateMetricsByID := map[string]Metric{}
+	for _, metric := range state.Metrics {
+		stateMetricsByID[metric.ID.ValueString()] = metric
+	}
+	for _, planMetric := range plan.Metrics {
+		stateMetric, ok := stateMetricsByID[planMetric.ID.ValueString()]
+		if !ok {
+			continue
+		}
+		stateFields, planFields := map[string]any{}, map[string]any{}
+		marshalBasicTypesByTag("json", &stateMetric, stateFields)
+		marshalBasicTypesByTag("json", &planMetric, planFields)
+		for _, field := range kpiBaseSearchMetricKpiFields {
+			if stateFields[field] != planFields[field] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// propagateToServices pushes the KPI base search fields into every service KPI linked to it.
+// Only services whose KPIs actually change are updated.
+func (r *resourceKpiBaseSearch) propagateToServices(ctx context.Context, kbs *models.ItsiObj) (diags diag.Diagnostics) {
+	kbsFields, err := kbs.RawJson.ToInterfaceMap()
+	if err != nil {
+		diags.AddError("Unable to propagate KPI Base Search changes", err.Error())
+		return
+	}
+
+	metricsByID := map[string]map[string]any{}
+	if metrics, err := UnpackSlice[map[string]any](kbsFields["metrics"]); err == nil {
+		for _, metric := range metrics {
+			if id, ok := metric["_key"].(string); ok {
+				metricsByID[id] = metric
+			}
+		}
+	}
+
+	filter := fmt.Sprintf("{\"kpis.base_search_id\":%q}", kbs.RESTKey)
+	base := models.NewItsiObj(r.client, "", "", "service")
+	updated := 0
+	for service, err := range base.Iter(ctx, &models.Parameters{Filter: filter}) {
+		if err != nil {
+			diags.AddError("Unable to propagate KPI Base Search changes", err.Error())
+			return
+		}
+
+		fields, err := service.RawJson.ToInterfaceMap()
+		if err != nil {
+			diags.AddError("Unable to propagate KPI Base Search changes", err.Error())
+			return
+		}
+		kpis, err := UnpackSlice[map[string]any](fields["kpis"])
+		if err != nil {
+			diags.AddError("Unable to propagate KPI Base Search changes", err.Error())
+			return
+		}
+
+		changed := false
+		setField := func(kpi map[string]any, field string, value any) {
+			if !reflect.DeepEqual(kpi[field], value) {
+				kpi[field] = value
+				changed = true
+			}
+		}
+		for _, kpi := range kpis {
+			if kpi["base_search_id"] != kbs.RESTKey {
+				continue
+			}
+			for _, field := range kpiBaseSearchKpiFields {
+				setField(kpi, field, kbsFields[field])
+			}
+			metricID, _ := kpi["base_search_metric"].(string)
+			metric, ok := metricsByID[metricID]
+			if !ok {
+				diags.AddWarning("KPI references a missing base search metric",
+					fmt.Sprintf("KPI %v of service %s references metric %q, which no longer exists in KPI BS %s.", kpi["title"], service.TFID, metricID, kbs.TFID))
+				continue
+			}
+			for _, field := range kpiBaseSearchMetricKpiFields {
+				setField(kpi, field, metric[field])
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		// the _tf_hash read with the service is replaced with the hash of its updated content
+		if err := service.PopulateRawJSON(ctx, fields); err != nil {
+			diags.AddError("Unable to propagate KPI Base Search changes", err.Error())
+			return
+		}
+		if diags.Append(service.UpdateAsync(ctx)...); diags.HasError() {
+			return
+		}
+		updated++
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Propagated KPI BS %s changes to %d service(s)", kbs.TFID, updated))
+	return
+}
+
 // =================== [ KPI Base Search API / Builder] ===================
 
 type kpiBaseSearchBuildWorkflow struct{}
@@ -352,14 +555,14 @@ func kpiBaseSearchMetricsBlock() schema.SetNestedBlock {
 					Required:    true,
 					Description: "Statistical operation (avg, max, median, stdev, and so on) used to combine data for the aggregate alert_value (used for all KPI).",
 					Validators: []validator.String{
-						stringvalidator.RegexMatches(regexp.MustCompile("(avg|count|dc|earliest|latest|max|median|min|stdev|sum|perc*)"), ""),
+						stringvalidator.RegexMatches(kpiBaseSearchStatOpRegexp, ""),
 					},
 				},
 				"entity_statop": schema.StringAttribute{
 					Required:    true,
 					Description: "Statistical operation (avg, max, mean, and so on) used to combine data for alert_values on a per entity basis (used if entity_breakdown is true).",
 					Validators: []validator.String{
-						stringvalidator.RegexMatches(regexp.MustCompile("(avg|count|dc|earliest|latest|max|median|min|stdev|sum|perc*)"), ""),
+						stringvalidator.RegexMatches(kpiBaseSearchStatOpRegexp, ""),
 					},
 				},
 				"fill_gaps": schema.StringAttribute{
@@ -503,6 +706,26 @@ func (r *resourceKpiBaseSearch) Schema(ctx context.Context, req resource.SchemaR
 				Description: "Source of DA used for this search. See KPI Threshold Templates.",
 				Default:     stringdefault.StaticString("itsi"),
 			},
+			"propagate_to_services": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				MarkdownDescription: util.Dedent(`
+					If true, changes to the fields that service KPIs copy from this base search
+					(e.g. alert_period, entity_id_fields or a metric's aggregate_statop) are pushed
+					into every linked service KPI after apply, instead of waiting for each service to be re-applied.
+					The affected services are listed as a warning at plan time.
+				`),
+				Default: booldefault.StaticBool(false),
+			},
+			"allow_orphaned_metrics": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				MarkdownDescription: util.Dedent(`
+					By default, the plan fails if a metric that is removed (or whose ID changes) is still referenced by service KPIs,
+					listing every affected service and KPI. If true, the plan proceeds and the references are reported as a warning.
+				`),
+				Default: booldefault.StaticBool(false),
+			},
 		},
 	}
 }
@@ -536,6 +759,8 @@ func (r *resourceKpiBaseSearch) Create(ctx context.Context, req resource.CreateR
 	if resp.Diagnostics.Append(diags...); diags.HasError() {
 		return
 	}
+	state.PropagateToServices = plan.PropagateToServices
+	state.AllowOrphanedMetrics = plan.AllowOrphanedMetrics
 	state.Timeouts = timeouts
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -564,11 +789,14 @@ func (r *resourceKpiBaseSearch) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	propagateToServices, allowOrphanedMetrics := state.PropagateToServices, state.AllowOrphanedMetrics
 	state, diags = newAPIParser(b, new(kpiBaseSearchParseWorkflow)).parse(ctx, b)
 	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
 		return
 	}
 
+	state.PropagateToServices = propagateToServices
+	state.AllowOrphanedMetrics = allowOrphanedMetrics
 	state.Timeouts = timeouts
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -611,8 +839,16 @@ func (r *resourceKpiBaseSearch) Update(ctx context.Context, req resource.UpdateR
 	if resp.Diagnostics.Append(diags...); diags.HasError() {
 		return
 	}
+	state.PropagateToServices = plan.PropagateToServices
+	state.AllowOrphanedMetrics = plan.AllowOrphanedMetrics
 	state.Timeouts = timeouts
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.Append(resp.State.Set(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.PropagateToServices.ValueBool() {
+		resp.Diagnostics.Append(r.propagateToServices(ctx, base)...)
+	}
 }
 
 func (r *resourceKpiBaseSearch) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -663,6 +899,8 @@ func (r *resourceKpiBaseSearch) ImportState(ctx context.Context, req resource.Im
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	state.PropagateToServices = types.BoolValue(false)
+	state.AllowOrphanedMetrics = types.BoolValue(false)
 	state.Timeouts = timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
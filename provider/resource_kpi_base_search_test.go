@@ -1,11 +1,17 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/config"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
 	"github.com/tivo/terraform-provider-splunk-itsi/util"
 )
 
@@ -149,3 +155,99 @@ func TestAccResourceKPIBaseSearchLifecycle(t *testing.T) {
 		},
 	})
 }
+
+func TestAccResourceKPIBaseSearchPropagateToServices(t *testing.T) {
+	t.Parallel()
+	serviceTitle := testAccResourceTitle("ResourceKPIBaseSearchPropagateToServices_service")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckResourceDestroy(resourceNameKPIBaseSearch, testAccResourceTitle("ResourceKPIBaseSearchPropagateToServices_base_search")),
+			testAccCheckResourceDestroy(resourceNameService, serviceTitle),
+		),
+		Steps: []resource.TestStep{
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceKpiField(serviceTitle, "KPI 1", "alert_period", "5"),
+					testAccCheckServiceKpiField(serviceTitle, "KPI 1", "aggregate_statop", "sum"),
+				),
+			},
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceKpiField(serviceTitle, "KPI 1", "alert_period", "15"),
+					testAccCheckServiceKpiField(serviceTitle, "KPI 1", "aggregate_statop", "avg"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceKPIBaseSearchOrphanedMetrics(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckResourceDestroy(resourceNameKPIBaseSearch, testAccResourceTitle("ResourceKPIBaseSearchOrphanedMetrics_base_search")),
+			testAccCheckResourceDestroy(resourceNameService, testAccResourceTitle("ResourceKPIBaseSearchOrphanedMetrics_service")),
+		),
+		Steps: []resource.TestStep{
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+			},
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				ExpectError:              regexp.MustCompile(`(?s)referenced by 1 service KPI.*KPI "KPI 1".*references metric "metric 2"`),
+			},
+		},
+	})
+}
+
+// testAccCheckServiceKpiField checks the value of a field, as stored in ITSI, of a service KPI.
+func testAccCheckServiceKpiField(serviceTitle, kpiTitle, field, expected string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		service, err := models.NewItsiObj(clientConfig, "", serviceTitle, string(resourceNameService)).Find(ctx)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			return fmt.Errorf("service %s not found", serviceTitle)
+		}
+		// bypass the cache to see the latest stored version
+		if service, err = service.Read(ctx); err != nil {
+			return err
+		}
+
+		fields, err := service.RawJson.ToInterfaceMap()
+		if err != nil {
+			return err
+		}
+		kpis, err := UnpackSlice[map[string]any](fields["kpis"])
+		if err != nil {
+			return err
+		}
+		for _, kpi := range kpis {
+			if kpi["title"] != kpiTitle {
+				continue
+			}
+			if actual := fmt.Sprintf("%v", kpi[field]); actual != expected {
+				return fmt.Errorf("service %s KPI %s: expected %s=%q, got %q", serviceTitle, kpiTitle, field, expected, actual)
+			}
+			return nil
+		}
+		return fmt.Errorf("service %s KPI %s not found", serviceTitle, kpiTitle)
+	}
+}
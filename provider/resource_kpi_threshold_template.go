@@ -2,15 +2,21 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -27,9 +33,10 @@ func kpiThresholdTemplateBase(clientConfig models.ClientConfig, key string, titl
 }
 
 var (
-	_ resource.Resource                = &resourceKpiThresholdTemplate{}
-	_ resource.ResourceWithConfigure   = &resourceKpiThresholdTemplate{}
-	_ resource.ResourceWithImportState = &resourceKpiThresholdTemplate{}
+	_ resource.Resource                   = &resourceKpiThresholdTemplate{}
+	_ resource.ResourceWithConfigure      = &resourceKpiThresholdTemplate{}
+	_ resource.ResourceWithImportState    = &resourceKpiThresholdTemplate{}
+	_ resource.ResourceWithValidateConfig = &resourceKpiThresholdTemplate{}
 )
 
 func NewResourceKpiThresholdTemplate() resource.Resource {
@@ -47,7 +54,11 @@ type modelKpiThresholdTemplate struct {
 	AdaptiveThresholdingOutlierExclusionAlgo        types.String                             `tfsdk:"adaptive_thresholding_outlier_exclusion_algo" json:"outlier_detection_algo"`
 	AdaptiveThresholdingOutlierExclusionSensitivity types.Float64                            `tfsdk:"adaptive_thresholding_outlier_exclusion_sensitivity" json:"outlier_detection_sensitivity"`
 
-	SecGrp   types.String   `tfsdk:"sec_grp" json:"sec_grp"`
+	SecGrp                      types.String `tfsdk:"sec_grp" json:"sec_grp"`
+	WaitForLinkedKpis           types.Bool   `tfsdk:"wait_for_linked_kpis"`
+	RecomputeAdaptiveThresholds types.Bool   `tfsdk:"recompute_adaptive_thresholds"`
+	LinkedKpis                  types.List   `tfsdk:"linked_kpis"`
+
 	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
@@ -221,6 +232,23 @@ const (
 	BASE_SEVERITY_LABEL_DEFAULT = "normal"
 )
 
+// ValidateConfig checks that the adaptive thresholds of the linked KPIs can be recomputed for the training window,
+// before the template is pushed into them.
+func (r *resourceKpiThresholdTemplate) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var recompute, adaptive types.Bool
+	var trainingWindow types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("recompute_adaptive_thresholds"), &recompute)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("adaptive_thresholds_is_enabled"), &adaptive)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("adaptive_thresholding_training_window"), &trainingWindow)...)
+	if resp.Diagnostics.HasError() || !recompute.ValueBool() || !adaptive.ValueBool() || trainingWindow.IsUnknown() || trainingWindow.IsNull() {
+		return
+	}
+
+	if err := ValidateAdaptiveThresholdsTrainingWindow(trainingWindow.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("adaptive_thresholding_training_window"), "Invalid training window", err.Error())
+	}
+}
+
 func (r *resourceKpiThresholdTemplate) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
 	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
 		return
@@ -399,6 +427,56 @@ func (r *resourceKpiThresholdTemplate) Schema(ctx context.Context, _ resource.Sc
 				Description: "The team the object belongs to.",
 				Default:     stringdefault.StaticString(itsiDefaultSecurityGroup),
 			},
+			"wait_for_linked_kpis": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				MarkdownDescription: util.Dedent(`
+					ITSI pushes threshold template changes into the linked KPIs asynchronously.
+					If true, an update waits (up to the update timeout) until the thresholds of every linked KPI match the template.
+				`),
+			},
+			"recompute_adaptive_thresholds": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				MarkdownDescription: util.Dedent(`
+					ITSI recomputes adaptive thresholds on a nightly schedule.
+					If true, after an update has been pushed into the linked KPIs (see wait_for_linked_kpis),
+					ITSI's adaptive thresholds search for the adaptive_thresholding_training_window (-7d, -14d, -30d or -60d) is triggered immediately,
+					and the update waits until it completes, so that the linked KPIs are recomputed by ITSI itself.
+					The new threshold values are reported in linked_kpis.
+				`),
+			},
+			"linked_kpis": schema.ListNestedAttribute{
+				MarkdownDescription: "Service KPIs linked to this threshold template through their `threshold_template_id`.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"service_id": schema.StringAttribute{
+							MarkdownDescription: "_key of the service.",
+							Computed:            true,
+						},
+						"service_title": schema.StringAttribute{
+							MarkdownDescription: "Title of the service.",
+							Computed:            true,
+						},
+						"kpi_id": schema.StringAttribute{
+							MarkdownDescription: "_key of the KPI.",
+							Computed:            true,
+						},
+						"kpi_title": schema.StringAttribute{
+							MarkdownDescription: "Title of the KPI.",
+							Computed:            true,
+						},
+						"thresholds": schema.MapAttribute{
+							MarkdownDescription: "Aggregate threshold values of the KPI, keyed by policy name and severity label.",
+							ElementType:         kpiThresholdsType.ElemType,
+							Computed:            true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -431,6 +509,7 @@ func (r *resourceKpiThresholdTemplate) Create(ctx context.Context, req resource.
 		return
 	}
 	resp.Diagnostics.Append(populateKpiThresholdTemplateModel(ctx, b, &plan)...)
+	resp.Diagnostics.Append(populateKpiThresholdTemplateLinkedKpis(ctx, r.client, b, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -471,6 +550,7 @@ func (r *resourceKpiThresholdTemplate) Read(ctx context.Context, req resource.Re
 	}
 
 	resp.Diagnostics.Append(populateKpiThresholdTemplateModel(ctx, b, &state)...)
+	resp.Diagnostics.Append(populateKpiThresholdTemplateLinkedKpis(ctx, r.client, b, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -536,12 +616,32 @@ func (r *resourceKpiThresholdTemplate) Update(ctx context.Context, req resource.
 		return
 	}
 	resp.Diagnostics.Append(populateKpiThresholdTemplateModel(ctx, base, &plan)...)
+	resp.Diagnostics.Append(populateKpiThresholdTemplateLinkedKpis(ctx, r.client, base, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.WaitForLinkedKpis.ValueBool() && !plan.RecomputeAdaptiveThresholds.ValueBool() {
+		return
+	}
+	if resp.Diagnostics.Append(waitForLinkedKpis(ctx, r.client, base)...); resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.RecomputeAdaptiveThresholds.ValueBool() {
+		resp.Diagnostics.Append(recomputeLinkedKpisAdaptiveThresholds(ctx, r.client, base)...)
+	}
+
+	// linked_kpis is read again, with the thresholds pushed into the linked KPIs
+	resp.Diagnostics.Append(populateKpiThresholdTemplateLinkedKpis(ctx, r.client, base, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -575,11 +675,278 @@ func (r *resourceKpiThresholdTemplate) Delete(ctx context.Context, req resource.
 	resp.Diagnostics.Append(existing.Delete(ctx)...)
 }
 
+// =================== [ KPI Threshold Template / Linked KPIs ] ===================
+
+const kpiThresholdTemplateLinkedKpisCheckInterval = 15 * time.Second
+
+type kpiThresholdTemplateLinkedKpiModel struct {
+	ServiceID    types.String `tfsdk:"service_id"`
+	ServiceTitle types.String `tfsdk:"service_title"`
+	KpiID        types.String `tfsdk:"kpi_id"`
+	KpiTitle     types.String `tfsdk:"kpi_title"`
+	Thresholds   types.Map    `tfsdk:"thresholds"`
+}
+
+// kpiThresholdsType is the type of the aggregate threshold values of a KPI: a map of policy names to maps of severity labels to threshold values.
+var kpiThresholdsType = types.MapType{ElemType: types.MapType{ElemType: types.Float64Type}}
+
+var kpiThresholdTemplateLinkedKpiType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"service_id":    types.StringType,
+	"service_title": types.StringType,
+	"kpi_id":        types.StringType,
+	"kpi_title":     types.StringType,
+	"thresholds":    kpiThresholdsType,
+}}
+
+// kpiThresholdsSignature returns the part of a threshold template (or of a KPI linked to it) that ITSI copies into linked KPIs,
+// normalized so that a template and a KPI in sync with it have equal signatures.
+// Threshold values of adaptive policies are left out, since ITSI recomputes them for every KPI.
+func kpiThresholdsSignature(fields map[string]any) (string, error) {
+	number := func(v any) any {
+		if s, ok := v.(string); ok && s == "" {
+			return 0
+		}
+		return v
+	}
+
+	adaptive, _ := fields["adaptive_thresholds_is_enabled"].(bool)
+	levelsSignature := func(setting any, includeValues bool) []any {
+		settingFields, _ := setting.(map[string]any)
+		levels, _ := UnpackSlice[map[string]any](settingFields["thresholdLevels"])
+		signature := []any{}
+		for _, level := range levels {
+			levelSignature := map[string]any{
+				"severityValue": number(level["severityValue"]),
+				"dynamicParam":  number(level["dynamicParam"]),
+			}
+			if includeValues {
+				levelSignature["thresholdValue"] = number(level["thresholdValue"])
+			}
+			signature = append(signature, levelSignature)
+		}
+		return signature
+	}
+
+	policiesSignature := map[string]any{}
+	spec, _ := fields["time_variate_thresholds_specification"].(map[string]any)
+	policies, _ := spec["policies"].(map[string]any)
+	for name, p := range policies {
+		policy, _ := p.(map[string]any)
+		includeValues := !adaptive || policy["policy_type"] == "static"
+		policiesSignature[name] = map[string]any{
+			"policy_type":          policy["policy_type"],
+			"time_blocks":          policy["time_blocks"],
+			"aggregate_thresholds": levelsSignature(policy["aggregate_thresholds"], includeValues),
+			"entity_thresholds":    levelsSignature(policy["entity_thresholds"], includeValues),
+		}
+	}
+
+	signature, err := json.Marshal(map[string]any{
+		"adaptive_thresholds_is_enabled":        fields["adaptive_thresholds_is_enabled"],
+		"adaptive_thresholding_training_window": fields["adaptive_thresholding_training_window"],
+		"time_variate_thresholds":               fields["time_variate_thresholds"],
+		"policies":                              policiesSignature,
+	})
+	return string(signature), err
+}
+
+// kpiThresholdTemplateLinkedKpis returns the service KPIs linked to the threshold template,
+// and the subset of them whose thresholds do not match the template yet.
+func kpiThresholdTemplateLinkedKpis(ctx context.Context, client models.ClientConfig, template *models.ItsiObj) (linked, outOfSync []kpiThresholdTemplateLinkedKpiModel, diags diag.Diagnostics) {
+	templateFields, err := template.RawJson.ToInterfaceMap()
+	if err != nil {
+		diags.AddError("Failed to check linked KPIs", err.Error())
+		return
+	}
+	templateSignature, err := kpiThresholdsSignature(templateFields)
+	if err != nil {
+		diags.AddError("Failed to check linked KPIs", err.Error())
+		return
+	}
+
+	// only the services linked to the template are requested, with just the KPI fields needed to compare thresholds
+	base := models.NewItsiObj(client, "", "", "service")
+	params := &models.Parameters{
+		Filter: fmt.Sprintf("{\"kpis.kpi_threshold_template_id\": %q}", template.RESTKey),
+		Fields: []string{
+			"_key", "title",
+			"kpis._key", "kpis.title", "kpis.kpi_threshold_template_id",
+			"kpis.adaptive_thresholds_is_enabled", "kpis.adaptive_thresholding_training_window",
+			"kpis.time_variate_thresholds", "kpis.time_variate_thresholds_specification",
+		},
+	}
+	for service, err := range base.Iter(ctx, params) {
+		if err != nil {
+			diags.AddError("Failed to check linked KPIs", err.Error())
+			return
+		}
+
+		fields, err := service.RawJson.ToInterfaceMap()
+		if err != nil {
+			diags.AddError("Failed to check linked KPIs", err.Error())
+			return
+		}
+		kpis, err := UnpackSlice[map[string]any](fields["kpis"])
+		if err != nil {
+			diags.AddError("Failed to check linked KPIs", err.Error())
+			return
+		}
+
+		for _, kpi := range kpis {
+			if kpi["kpi_threshold_template_id"] != template.RESTKey {
+				continue
+			}
+			kpiID, _ := kpi["_key"].(string)
+			kpiTitle, _ := kpi["title"].(string)
+			linkedKpi := kpiThresholdTemplateLinkedKpiModel{
+				ServiceID:    types.StringValue(service.RESTKey),
+				ServiceTitle: types.StringValue(service.TFID),
+				KpiID:        types.StringValue(kpiID),
+				KpiTitle:     types.StringValue(kpiTitle),
+			}
+
+			thresholds := map[string]map[string]float64{}
+			policies, _ := kpiThresholdPoliciesFromAPI(kpi)
+			for _, policy := range policies {
+				thresholds[policy.PolicyName.ValueString()] = map[string]float64{}
+				for _, level := range policy.ThresholdLevels {
+					thresholds[policy.PolicyName.ValueString()][level.SeverityLabel.ValueString()] = level.ThresholdValue.ValueFloat64()
+				}
+			}
+			var d diag.Diagnostics
+			linkedKpi.Thresholds, d = types.MapValueFrom(ctx, kpiThresholdsType.ElemType, thresholds)
+			if diags.Append(d...); diags.HasError() {
+				return
+			}
+			linked = append(linked, linkedKpi)
+
+			kpiSignature, err := kpiThresholdsSignature(kpi)
+			if err != nil {
+				diags.AddError("Failed to check linked KPIs", err.Error())
+				return
+			}
+			if kpiSignature != templateSignature {
+				outOfSync = append(outOfSync, linkedKpi)
+			}
+		}
+	}
+
+	sort.SliceStable(linked, func(i, j int) bool {
+		if linked[i].ServiceTitle.ValueString() != linked[j].ServiceTitle.ValueString() {
+			return linked[i].ServiceTitle.ValueString() < linked[j].ServiceTitle.ValueString()
+		}
+		return linked[i].KpiTitle.ValueString() < linked[j].KpiTitle.ValueString()
+	})
+	return
+}
+
+// populateKpiThresholdTemplateLinkedKpis sets the linked_kpis attribute of the model.
+func populateKpiThresholdTemplateLinkedKpis(ctx context.Context, client models.ClientConfig, b *models.ItsiObj, tfModelKpiThresholdTemplate *modelKpiThresholdTemplate) (diags diag.Diagnostics) {
+	linked, _, diags := kpiThresholdTemplateLinkedKpis(ctx, client, b)
+	if diags.HasError() {
+		return
+	}
+	if linked == nil {
+		linked = []kpiThresholdTemplateLinkedKpiModel{}
+	}
+
+	var d diag.Diagnostics
+	tfModelKpiThresholdTemplate.LinkedKpis, d = types.ListValueFrom(ctx, kpiThresholdTemplateLinkedKpiType, linked)
+	diags.Append(d...)
+	return
+}
+
+// waitForLinkedKpis polls the services linked to the threshold template, until ITSI has pushed the template thresholds into every linked KPI.
+func waitForLinkedKpis(ctx context.Context, client models.ClientConfig, template *models.ItsiObj) (diags diag.Diagnostics) {
+	ticker := time.NewTicker(kpiThresholdTemplateLinkedKpisCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		_, outOfSync, d := kpiThresholdTemplateLinkedKpis(ctx, client, template)
+		if diags.Append(d...); diags.HasError() || len(outOfSync) == 0 {
+			return
+		}
+		tflog.Info(ctx, fmt.Sprintf("Waiting for %d KPIs linked to threshold template %s", len(outOfSync), template.RESTKey))
+
+		select {
+		case <-ctx.Done():
+			kpis := []string{}
+			for _, kpi := range outOfSync {
+				kpis = append(kpis, fmt.Sprintf("service %q (_key=%s): KPI %q (_key=%s)",
+					kpi.ServiceTitle.ValueString(), kpi.ServiceID.ValueString(), kpi.KpiTitle.ValueString(), kpi.KpiID.ValueString()))
+			}
+			diags.AddError("Timeout while waiting for linked KPIs",
+				fmt.Sprintf("The thresholds of the following KPIs do not match KPI threshold template %s yet:\n%s",
+					template.RESTKey, strings.Join(kpis, "\n")))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recomputeLinkedKpisAdaptiveThresholds triggers the recomputation of the adaptive thresholds of the KPIs linked to the threshold template,
+// once they are in sync with it.
+func recomputeLinkedKpisAdaptiveThresholds(ctx context.Context, client models.ClientConfig, template *models.ItsiObj) (diags diag.Diagnostics) {
+	fields, err := template.RawJson.ToInterfaceMap()
+	if err != nil {
+		diags.AddError("Failed to recompute adaptive thresholds", err.Error())
+		return
+	}
+	if enabled, _ := fields["adaptive_thresholds_is_enabled"].(bool); !enabled {
+		return
+	}
+	trainingWindow, _ := fields["adaptive_thresholding_training_window"].(string)
+	if trainingWindow == "" {
+		trainingWindow = adaptiveThresholdsDefaultTrainingWindow
+	}
+
+	linked, _, diags := kpiThresholdTemplateLinkedKpis(ctx, client, template)
+	if diags.HasError() || len(linked) == 0 {
+		return
+	}
+
+	kpis := make([]AdaptiveThresholdsKpi, len(linked))
+	for i, kpi := range linked {
+		kpis[i] = AdaptiveThresholdsKpi{
+			ServiceID:      kpi.ServiceID.ValueString(),
+			ServiceTitle:   kpi.ServiceTitle.ValueString(),
+			KpiID:          kpi.KpiID.ValueString(),
+			KpiTitle:       kpi.KpiTitle.ValueString(),
+			TrainingWindow: trainingWindow,
+		}
+	}
+
+	results, d := RecomputeAdaptiveThresholds(ctx, client, kpis, false)
+	if diags.Append(d...); diags.HasError() {
+		return
+	}
+	for _, kpi := range results {
+		tflog.Info(ctx, fmt.Sprintf("Recomputed adaptive thresholds of service %q KPI %q", kpi.ServiceTitle, kpi.KpiTitle))
+	}
+	return
+}
+
 func kpiThresholdTemplate(ctx context.Context, tfKpiThresholdTemplate modelKpiThresholdTemplate, clientConfig models.ClientConfig) (config *models.ItsiObj, diags diag.Diagnostics) {
-	body := map[string]any{}
-	diags = append(diags, marshalBasicTypesByTag("json", &tfKpiThresholdTemplate, body)...)
+	body, diags := kpiThresholdTemplatePayload(ctx, tfKpiThresholdTemplate)
+	if diags.HasError() {
+		return
+	}
 	body["objectType"] = "kpi_threshold_template"
 
+	base := kpiThresholdTemplateBase(clientConfig, tfKpiThresholdTemplate.ID.ValueString(), tfKpiThresholdTemplate.Title.ValueString())
+	err := base.PopulateRawJSON(ctx, body)
+	if err != nil {
+		diags.AddError("Failed to populate kpi threshold template.", err.Error())
+		return
+	}
+	return base, nil
+}
+
+// kpiThresholdTemplatePayload renders the API payload of a threshold template model.
+func kpiThresholdTemplatePayload(ctx context.Context, tfKpiThresholdTemplate modelKpiThresholdTemplate) (body map[string]any, diags diag.Diagnostics) {
+	body = map[string]any{}
+	diags = append(diags, marshalBasicTypesByTag("json", &tfKpiThresholdTemplate, body)...)
+
 	outlierExclusionAlgo := tfKpiThresholdTemplate.AdaptiveThresholdingOutlierExclusionAlgo.ValueString()
 	outlierExclusionEnabled := !tfKpiThresholdTemplate.AdaptiveThresholdingOutlierExclusionAlgo.IsNull()
 	body["aggregate_outlier_detection_enabled"] = outlierExclusionEnabled
@@ -629,14 +996,7 @@ func kpiThresholdTemplate(ctx context.Context, tfKpiThresholdTemplate modelKpiTh
 			"policies": policies,
 		}
 	}
-
-	base := kpiThresholdTemplateBase(clientConfig, tfKpiThresholdTemplate.ID.ValueString(), tfKpiThresholdTemplate.Title.ValueString())
-	err := base.PopulateRawJSON(ctx, body)
-	if err != nil {
-		diags.AddError("Failed to populate kpi threshold template.", err.Error())
-		return
-	}
-	return base, nil
+	return
 }
 
 func populateKpiThresholdTemplateModel(_ context.Context, b *models.ItsiObj, tfModelKpiThresholdTemplate *modelKpiThresholdTemplate) (diags diag.Diagnostics) {
@@ -644,6 +1004,14 @@ func populateKpiThresholdTemplateModel(_ context.Context, b *models.ItsiObj, tfM
 	if err != nil {
 		diags.AddError("Failed to populate interfaceMap.", err.Error())
 	}
+	diags = append(diags, populateKpiThresholdTemplateModelFromAPI(interfaceMap, tfModelKpiThresholdTemplate)...)
+
+	tfModelKpiThresholdTemplate.ID = types.StringValue(b.RESTKey)
+	return
+}
+
+// populateKpiThresholdTemplateModelFromAPI populates a threshold template model from the API payload of a threshold template or a KPI.
+func populateKpiThresholdTemplateModelFromAPI(interfaceMap map[string]any, tfModelKpiThresholdTemplate *modelKpiThresholdTemplate) (diags diag.Diagnostics) {
 	diags = append(diags, unmarshalBasicTypesByTag("json", interfaceMap, tfModelKpiThresholdTemplate)...)
 
 	tfPolicies := []PolicyModel{}
@@ -695,8 +1063,6 @@ func populateKpiThresholdTemplateModel(_ context.Context, b *models.ItsiObj, tfM
 	}
 	tfModelKpiThresholdTemplate.TimeVariateThresholdsSpecification = &TimeVariateThresholdsSpecificationModel{}
 	tfModelKpiThresholdTemplate.TimeVariateThresholdsSpecification.Policies = tfPolicies
-
-	tfModelKpiThresholdTemplate.ID = types.StringValue(b.RESTKey)
 	return
 }
 
@@ -715,10 +1081,16 @@ func (r *resourceKpiThresholdTemplate) ImportState(ctx context.Context, req reso
 		return
 	}
 
-	state := modelKpiThresholdTemplate{}
+	state := modelKpiThresholdTemplate{
+		WaitForLinkedKpis:           types.BoolValue(false),
+		RecomputeAdaptiveThresholds: types.BoolValue(false),
+	}
 	if resp.Diagnostics.Append(populateKpiThresholdTemplateModel(ctx, b, &state)...); resp.Diagnostics.HasError() {
 		return
 	}
+	if resp.Diagnostics.Append(populateKpiThresholdTemplateLinkedKpis(ctx, r.client, b, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
 
 	var timeouts timeouts.Value
 	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("timeouts"), &timeouts)...)
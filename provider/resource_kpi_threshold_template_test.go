@@ -219,3 +219,119 @@ func TestAccResourceKPIThresholdTemplateDeletedInUI(t *testing.T) {
 		},
 	})
 }
+
+func TestKpiThresholdsSignature(t *testing.T) {
+	template := map[string]any{
+		"title":                                 "template",
+		"adaptive_thresholds_is_enabled":        true,
+		"adaptive_thresholding_training_window": "-7d",
+		"time_variate_thresholds":               false,
+		"time_variate_thresholds_specification": map[string]any{
+			"policies": map[string]any{
+				"default_policy": map[string]any{
+					"policy_type": "stdev",
+					"time_blocks": [][]any{},
+					"aggregate_thresholds": map[string]any{
+						"thresholdLevels": []any{
+							map[string]any{"severityValue": 6, "dynamicParam": 2.75, "thresholdValue": 2.75},
+						},
+					},
+					"entity_thresholds": map[string]any{"thresholdLevels": []any{}},
+				},
+			},
+		},
+	}
+
+	kpi := func(thresholdValue any, dynamicParam any) map[string]any {
+		return map[string]any{
+			"title":                                 "kpi",
+			"kpi_threshold_template_id":             "template",
+			"adaptive_thresholds_is_enabled":        true,
+			"adaptive_thresholding_training_window": "-7d",
+			"time_variate_thresholds":               false,
+			"time_variate_thresholds_specification": map[string]any{
+				"policies": map[string]any{
+					"default_policy": map[string]any{
+						"policy_type": "stdev",
+						"time_blocks": []any{},
+						"aggregate_thresholds": map[string]any{
+							"thresholdLevels": []any{
+								map[string]any{"severityValue": 6.0, "dynamicParam": dynamicParam, "thresholdValue": thresholdValue},
+							},
+						},
+						"entity_thresholds": map[string]any{"thresholdLevels": []any{}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		kpi    map[string]any
+		inSync bool
+		static bool
+	}{
+		{"in sync", kpi(2.75, 2.75), true, false},
+		{"adaptive threshold value recomputed", kpi(12.5, 2.75), true, false},
+		{"dynamic param not pushed yet", kpi(2.75, 2.5), false, false},
+		{"static threshold value not pushed yet", kpi(12.5, 2.75), false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			template["adaptive_thresholds_is_enabled"] = !test.static
+			test.kpi["adaptive_thresholds_is_enabled"] = !test.static
+
+			templateSignature, err := kpiThresholdsSignature(template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kpiSignature, err := kpiThresholdsSignature(test.kpi)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inSync := templateSignature == kpiSignature; inSync != test.inSync {
+				t.Errorf("expected in sync %v, got %v:\n%s\n%s", test.inSync, inSync, templateSignature, kpiSignature)
+			}
+		})
+	}
+}
+
+func TestAccResourceKPIThresholdTemplateWaitForLinkedKpis(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckResourceDestroy(resourceNameService, "TestAcc_ResourceKPIThresholdTemplateWaitForLinkedKpis_service"),
+			testAccCheckResourceDestroy(resourceNameKPIThresholdTemplate, "TestAcc_ResourceKPIThresholdTemplateWaitForLinkedKpis_threshold_template"),
+			testAccCheckResourceDestroy(resourceNameKPIBaseSearch, "TestAcc_ResourceKPIThresholdTemplateWaitForLinkedKpis_base_search"),
+		),
+		Steps: []resource.TestStep{
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("itsi_kpi_threshold_template.test", "wait_for_linked_kpis", "false"),
+				),
+			},
+			// the service is linked to the template after the template is created, so linked_kpis is refreshed in the next step
+			{
+				ProtoV6ProviderFactories: providerFactories,
+				ConfigDirectory:          config.TestStepDirectory(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("itsi_kpi_threshold_template.test", "wait_for_linked_kpis", "true"),
+					resource.TestCheckResourceAttr("itsi_kpi_threshold_template.test", "linked_kpis.#", "1"),
+					resource.TestCheckResourceAttr("itsi_kpi_threshold_template.test", "linked_kpis.0.service_title", "TestAcc_ResourceKPIThresholdTemplateWaitForLinkedKpis_service"),
+					resource.TestCheckResourceAttr("itsi_kpi_threshold_template.test", "linked_kpis.0.kpi_title", "KPI 1"),
+					resource.TestCheckResourceAttrPair("itsi_kpi_threshold_template.test", "linked_kpis.0.service_id", "itsi_service.test", "id"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
@@ -93,9 +93,11 @@ type KpiState struct {
 }
 
 func (ks KpiState) internalKey() string {
-	baseSearchId := ks.BaseSearchID.ValueString()
-	baseSearchMetricId := ks.BaseSearchMetric.ValueString()
+	return kpiInternalKey(ks.BaseSearchID.ValueString(), ks.BaseSearchMetric.ValueString())
+}
 
+// kpiInternalKey returns the key KPIs are matched by across plans, derived from their KPI base search and metric, or "" if either is unset.
+func kpiInternalKey(baseSearchId, baseSearchMetricId string) string {
 	if baseSearchId == "" || baseSearchMetricId == "" {
 		// Failed to identify key, do not modify this plan
 		return ""
@@ -276,42 +278,7 @@ func (r *resourceService) Schema(ctx context.Context, req resource.SchemaRequest
 					},
 				},
 			},
-			"entity_rules": schema.SetNestedBlock{
-				Description: "A set of rules within the rule group, which are combined using OR operator.",
-				NestedObject: schema.NestedBlockObject{
-					Blocks: map[string]schema.Block{
-						"rule": schema.SetNestedBlock{
-							Description: "A set of rules within the rule group, which are combined using AND operator.",
-							NestedObject: schema.NestedBlockObject{
-								Attributes: map[string]schema.Attribute{
-									"field": schema.StringAttribute{
-										Required:    true,
-										Description: "The field in the entity definition to compare values to evaluate this rule.",
-									},
-									"field_type": schema.StringAttribute{
-										Required:    true,
-										Description: "Takes values alias, info or title specifying in which category of fields the field attribute is located.",
-										Validators: []validator.String{
-											stringvalidator.OneOf("alias", "entity_type", "info", "title"),
-										},
-									},
-									"rule_type": schema.StringAttribute{
-										Required:    true,
-										Description: "Takes values not or matches to indicate whether it's an inclusion or exclusion rule.",
-										Validators: []validator.String{
-											stringvalidator.OneOf("matches", "not"),
-										},
-									},
-									"value": schema.StringAttribute{
-										Required:    true,
-										Description: "Values to evaluate in the rule. To specify multiple values, separate them with a comma. Values are not case sensitive.",
-									},
-								},
-							},
-						},
-					},
-				},
-			},
+			"entity_rules": entityRulesSchema(),
 			"service_depends_on": schema.SetNestedBlock{
 				Description: "A set of service descriptions with KPIs in those services that this service depends on.",
 				NestedObject: schema.NestedBlockObject{
@@ -860,30 +827,7 @@ func (w *serviceParseWorkflow) kpis(ctx context.Context, fields map[string]any,
 }
 
 func (w *serviceParseWorkflow) entityRules(ctx context.Context, fields map[string]any, res *ServiceState) (diags diag.Diagnostics) {
-	res.EntityRules = []EntityRuleState{}
-	entityRules, err := UnpackSlice[map[string]any](fields["entity_rules"])
-	if err != nil {
-		diags.AddError("Unable to unpack entity rules from service model", err.Error())
-		return
-	}
-
-	for _, entityRuleAndSet := range entityRules {
-		ruleState := EntityRuleState{}
-		ruleSet := []RuleState{}
-		ruleItems, err := UnpackSlice[map[string]any](entityRuleAndSet["rule_items"])
-		if err != nil {
-			diags.AddError("Unable to unpack rule_item from service model", err.Error())
-			return
-		}
-		for _, ruleItem := range ruleItems {
-			ruleTF := RuleState{}
-			diags.Append(unmarshalBasicTypesByTag("json", ruleItem, &ruleTF)...)
-			ruleSet = append(ruleSet, ruleTF)
-		}
-		ruleState.Rule = ruleSet
-		res.EntityRules = append(res.EntityRules, ruleState)
-	}
-
+	res.EntityRules, diags = parseEntityRules(fields["entity_rules"])
 	return
 }
 
@@ -949,22 +893,34 @@ func (w *serviceBuildWorkflow) basics(ctx context.Context, obj ServiceState) (bo
 	}, nil
 }
 
+// list of KPI-level thresholding configuration fields that will :
+// * be populated according to the threshold template, if `kpi_threshold_template_id` is provided in the `kpi` block,
+// * retained if the threshold template id is not provided (to allow for custom / AI-recommended thresholds)
+var kpiThresholdingConfigFields = []string{
+	"adaptive_thresholding_training_window",
+	"adaptive_thresholds_is_enabled",
+	"aggregate_outlier_detection_enabled",
+	"aggregate_thresholds",
+	"entity_thresholds",
+	"outlier_detection_algo",
+	"outlier_detection_sensitivity",
+	"threshold_recommendations",
+	"time_variate_thresholds_specification",
+	"time_variate_thresholds",
+}
+
 func (w *serviceBuildWorkflow) thresholdingConfigFields() []string {
-	// list of KPI-level thresholding configuration fields that will :
-	// * be populated according to the threshold template, if `kpi_threshold_template_id`` is provided in the `kpi` block,
-	// * retained if the threshold template id is not provided (to allow for custom / AI-recommended thresholds)
-	//
-	return []string{
-		"adaptive_thresholding_training_window",
-		"adaptive_thresholds_is_enabled",
-		"aggregate_outlier_detection_enabled",
-		"aggregate_thresholds",
-		"entity_thresholds",
-		"outlier_detection_algo",
-		"outlier_detection_sensitivity",
-		"threshold_recommendations",
-		"time_variate_thresholds_specification",
-		"time_variate_thresholds",
+	return kpiThresholdingConfigFields
+}
+
+// ApplyKpiThresholdTemplate links a KPI API payload to a threshold template
+// and copies the thresholding configuration of the template API payload into the KPI.
+func ApplyKpiThresholdTemplate(kpi map[string]any, templateID string, template map[string]any) {
+	kpi["kpi_threshold_template_id"] = templateID
+	for _, field := range kpiThresholdingConfigFields {
+		if value, ok := template[field]; ok {
+			kpi[field] = value
+		}
 	}
 }
 
@@ -1040,21 +996,16 @@ func (w *serviceBuildWorkflow) kpis(ctx context.Context, obj ServiceState) (body
 		kpiID, thldTplID := kpi.ID.ValueString(), kpi.ThresholdTemplateID.ValueString()
 
 		itsiKpi := map[string]any{
-			"_key":                       kpiID,
-			"title":                      kpi.Title.ValueString(),
-			"urgency":                    kpi.Urgency.ValueInt64(),
-			"search_type":                kpi.SearchType.ValueString(),
-			"type":                       kpi.Type.ValueString(),
-			"description":                kpi.Description.ValueString(),
-			"base_search_id":             kpiBsID,
-			"base_search":                kpiBS["base_search"],
-			"is_entity_breakdown":        kpiBS["is_entity_breakdown"],
-			"is_service_entity_filter":   kpiBS["is_service_entity_filter"],
-			"entity_breakdown_id_fields": kpiBS["entity_breakdown_id_fields"],
-			"entity_id_fields":           kpiBS["entity_id_fields"],
-			"alert_period":               kpiBS["alert_period"],
-			"alert_lag":                  kpiBS["alert_lag"],
-			"search_alert_earliest":      kpiBS["search_alert_earliest"],
+			"_key":           kpiID,
+			"title":          kpi.Title.ValueString(),
+			"urgency":        kpi.Urgency.ValueInt64(),
+			"search_type":    kpi.SearchType.ValueString(),
+			"type":           kpi.Type.ValueString(),
+			"description":    kpi.Description.ValueString(),
+			"base_search_id": kpiBsID,
+		}
+		for _, field := range kpiBaseSearchKpiFields {
+			itsiKpi[field] = kpiBS[field]
 		}
 		if len(kpi.MLThresholding) > 0 {
 			rt := kpi.MLThresholding[0]
@@ -1076,9 +1027,7 @@ func (w *serviceBuildWorkflow) kpis(ctx context.Context, obj ServiceState) (body
 			_metric := metric.(map[string]any)
 			if _metric["title"].(string) == kpi.BaseSearchMetric.ValueString() {
 				itsiKpi["base_search_metric"] = _metric["_key"].(string)
-				for _, metricKey := range []string{"aggregate_statop", "entity_statop", "fill_gaps",
-					"gap_custom_alert_value", "gap_severity", "gap_severity_color", "gap_severity_color_light",
-					"gap_severity_value", "threshold_field", "unit"} {
+				for _, metricKey := range kpiBaseSearchMetricKpiFields {
 					itsiKpi[metricKey] = _metric[metricKey]
 				}
 			}
@@ -1109,12 +1058,7 @@ func (w *serviceBuildWorkflow) kpis(ctx context.Context, obj ServiceState) (body
 				return
 			}
 
-			itsiKpi["kpi_threshold_template_id"] = thldTplID
-			for _, thresholdKey := range w.thresholdingConfigFields() {
-				if value, ok := thresholdTemplateInterface[thresholdKey]; ok {
-					itsiKpi[thresholdKey] = value
-				}
-			}
+			ApplyKpiThresholdTemplate(itsiKpi, thldTplID, thresholdTemplateInterface)
 
 			//populate training data from cache
 
@@ -1147,22 +1091,7 @@ func (w *serviceBuildWorkflow) kpis(ctx context.Context, obj ServiceState) (body
 }
 
 func (w *serviceBuildWorkflow) entityRules(_ context.Context, obj ServiceState) (_ map[string]any, diags diag.Diagnostics) {
-	itsiEntityRules := []map[string]any{}
-	for _, entityRuleGroup := range obj.EntityRules {
-		itsiEntityGroupRules := []map[string]any{}
-		if len(entityRuleGroup.Rule) == 0 {
-			continue
-		}
-
-		for _, entityRule := range entityRuleGroup.Rule {
-			rule := map[string]any{}
-			diags.Append(marshalBasicTypesByTag("json", &entityRule, rule)...)
-			itsiEntityGroupRules = append(itsiEntityGroupRules, rule)
-		}
-
-		itsiEntityRuleGroup := map[string]any{"rule_condition": "AND", "rule_items": itsiEntityGroupRules}
-		itsiEntityRules = append(itsiEntityRules, itsiEntityRuleGroup)
-	}
+	itsiEntityRules, diags := buildEntityRules(obj.EntityRules)
 	return map[string]any{"entity_rules": itsiEntityRules}, diags
 }
 
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
+)
+
+/*
+	The retry block configures the retries of failed Splunk/ITSI API requests and Splunk searches.
+	Retry-After headers of the responses are honored in addition to the exponential backoff.
+*/
+
+const (
+	defaultRetryMinInterval = 500 * time.Millisecond
+	defaultRetryMaxInterval = time.Minute
+)
+
+type retryModel struct {
+	MinInterval          timetypes.GoDuration `tfsdk:"min_interval"`
+	MaxInterval          timetypes.GoDuration `tfsdk:"max_interval"`
+	MaxAttempts          types.Int64          `tfsdk:"max_attempts"`
+	MaxElapsedTime       timetypes.GoDuration `tfsdk:"max_elapsed_time"`
+	RetryableStatusCodes types.List           `tfsdk:"retryable_status_codes"`
+}
+
+func retrySchema() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: util.Dedent(`
+			Retries of failed Splunk/ITSI API requests and Splunk searches, with an exponential backoff.
+			Retry-After headers of throttled responses are honored. By default, failed requests are retried indefinitely,
+			until the Terraform resource timeouts expire.
+		`),
+		Attributes: map[string]schema.Attribute{
+			"min_interval": schema.StringAttribute{
+				Optional:            true,
+				CustomType:          timetypes.GoDurationType{},
+				MarkdownDescription: fmt.Sprintf("Interval before the first retry, as a Go duration, e.g. 500ms. Defaults to %s.", defaultRetryMinInterval),
+			},
+			"max_interval": schema.StringAttribute{
+				Optional:            true,
+				CustomType:          timetypes.GoDurationType{},
+				MarkdownDescription: fmt.Sprintf("Maximum interval between retries, as a Go duration, e.g. 30s. Defaults to %s.", defaultRetryMaxInterval),
+			},
+			"max_attempts": schema.Int64Attribute{
+				Optional:            true,
+				Validators:          []validator.Int64{int64validator.AtLeast(0)},
+				MarkdownDescription: "Maximum number of attempts of a request, including the first one. 1 disables retries, 0 means no limit. Defaults to 0.",
+			},
+			"max_elapsed_time": schema.StringAttribute{
+				Optional:   true,
+				CustomType: timetypes.GoDurationType{},
+				MarkdownDescription: util.Dedent(`
+					Maximum time spent on a request and its retries, as a Go duration, e.g. 5m. Retrying stops once it elapses;
+					the request in flight is not interrupted. No limit by default.
+				`),
+			},
+			"retryable_status_codes": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Validators:  []validator.List{listvalidator.ValueInt64sAre(int64validator.Between(100, 599))},
+				MarkdownDescription: util.Dedent(`
+					HTTP status codes of failed requests to retry. Connection errors are always retried.
+					By default, requests failing with any status code but 400, 401, 403, 404 and 409 are retried.
+				`),
+			},
+		},
+	}
+}
+
+// apply configures the retries of the client config.
+func (m *retryModel) apply(ctx context.Context, client *models.ClientConfig) (diags diag.Diagnostics) {
+	const summary = "Invalid retry configuration"
+
+	minInterval, maxInterval := defaultRetryMinInterval, defaultRetryMaxInterval
+	var maxAttempts int64
+	if m != nil {
+		for _, setting := range []struct {
+			attribute string
+			value     timetypes.GoDuration
+			target    *time.Duration
+		}{
+			{"min_interval", m.MinInterval, &minInterval},
+			{"max_interval", m.MaxInterval, &maxInterval},
+			{"max_elapsed_time", m.MaxElapsedTime, &client.RetryMaxElapsedTime},
+		} {
+			if setting.value.IsNull() || setting.value.IsUnknown() {
+				continue
+			}
+			value, d := setting.value.ValueGoDuration()
+			if diags.Append(d...); d.HasError() {
+				continue
+			}
+			if value < 0 {
+				diags.AddAttributeError(path.Root("retry").AtName(setting.attribute), summary, fmt.Sprintf("%s cannot be negative", setting.attribute))
+				continue
+			}
+			*setting.target = value
+		}
+		maxAttempts = m.MaxAttempts.ValueInt64()
+
+		if !m.RetryableStatusCodes.IsNull() {
+			var statusCodes []int64
+			diags.Append(m.RetryableStatusCodes.ElementsAs(ctx, &statusCodes, false)...)
+			for _, statusCode := range statusCodes {
+				client.RetryStatusCodes = append(client.RetryStatusCodes, int(statusCode))
+			}
+		}
+	}
+	if minInterval > maxInterval {
+		diags.AddAttributeError(path.Root("retry").AtName("min_interval"), summary, "min_interval cannot be greater than max_interval")
+	}
+	if diags.HasError() {
+		return
+	}
+
+	client.RetryPolicy = models.NewRetryPolicy(minInterval, maxInterval, int(maxAttempts))
+	return
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+)
+
+func TestRetryConfigDefaults(t *testing.T) {
+	var client models.ClientConfig
+	var m *retryModel
+	if diags := m.apply(context.Background(), &client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if client.RetryPolicy == nil || client.RetryMaxElapsedTime != 0 || client.RetryStatusCodes != nil {
+		t.Errorf("expected the default retries, got %+v", client)
+	}
+	if !client.RetryableStatus(503) || client.RetryableStatus(404) {
+		t.Errorf("expected 503 to be retried and 404 not to be retried by default")
+	}
+}
+
+func TestRetryConfig(t *testing.T) {
+	var client models.ClientConfig
+	m := &retryModel{
+		MinInterval:    timetypes.NewGoDurationValueFromStringMust("1s"),
+		MaxInterval:    timetypes.NewGoDurationValueFromStringMust("10s"),
+		MaxAttempts:    types.Int64Value(3),
+		MaxElapsedTime: timetypes.NewGoDurationValueFromStringMust("2m"),
+		RetryableStatusCodes: types.ListValueMust(types.Int64Type, []attr.Value{
+			types.Int64Value(429), types.Int64Value(503),
+		}),
+	}
+	if diags := m.apply(context.Background(), &client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if client.RetryMaxElapsedTime != 2*time.Minute {
+		t.Errorf("expected max elapsed time 2m, got %s", client.RetryMaxElapsedTime)
+	}
+	if !slices.Equal(client.RetryStatusCodes, []int{429, 503}) {
+		t.Errorf("expected retryable status codes [429 503], got %v", client.RetryStatusCodes)
+	}
+	if !client.RetryableStatus(429) || !client.RetryableStatus(503) || client.RetryableStatus(500) {
+		t.Errorf("expected only 429 and 503 to be retried")
+	}
+}
+
+func TestRetryConfigInvalidIntervals(t *testing.T) {
+	var client models.ClientConfig
+	m := &retryModel{
+		MinInterval:          timetypes.NewGoDurationValueFromStringMust("1m"),
+		MaxInterval:          timetypes.NewGoDurationValueFromStringMust("1s"),
+		MaxElapsedTime:       timetypes.NewGoDurationNull(),
+		RetryableStatusCodes: types.ListNull(types.Int64Type),
+	}
+	if diags := m.apply(context.Background(), &client); !diags.HasError() {
+		t.Errorf("expected an error for min_interval greater than max_interval")
+	}
+}
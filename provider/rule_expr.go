@@ -0,0 +1,275 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+/*
+	Rule expressions are a compact notation for entity rules and NEAP criteria clauses:
+
+		host matches web-*,api-* AND info:env not prod OR title:name matches db-*
+		severity >= 4 AND title = "*CPU*"
+
+	An expression is a list of conditions combined using AND and OR operators (case insensitive),
+	where AND takes precedence over OR, so that the expression is a set of rule groups or clauses.
+	Conditions using the matches and not operators are entity rules: the field may be prefixed
+	by its field type (alias, entity_type, info or title), alias being the default.
+	Conditions using the =, !=, >=, > and < operators are NEAP notable event field conditions.
+	Fields and values may be double quoted to include spaces, quotes or operator characters.
+*/
+
+var (
+	ruleExprFieldTypes     = []string{"alias", "entity_type", "info", "title"}
+	ruleExprFieldOperators = []string{"=", "!=", ">=", ">", "<"}
+)
+
+type ruleExprTokenKind int
+
+const (
+	ruleExprWord ruleExprTokenKind = iota
+	ruleExprQuoted
+	ruleExprOperator
+	ruleExprComma
+	ruleExprEOF
+)
+
+type ruleExprToken struct {
+	kind  ruleExprTokenKind
+	text  string
+	col   int // 1-based column of the first character of the token
+	upper string
+}
+
+func (t ruleExprToken) String() string {
+	if t.kind == ruleExprEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// ruleExprError is a rule expression syntax error at a given column of the expression.
+type ruleExprError struct {
+	Column int
+	Msg    string
+}
+
+func (e *ruleExprError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+}
+
+func ruleExprErrorf(col int, format string, a ...any) *ruleExprError {
+	return &ruleExprError{Column: col, Msg: fmt.Sprintf(format, a...)}
+}
+
+func lexRuleExpr(expr string) (tokens []ruleExprToken, err error) {
+	runes := []rune(expr)
+	isWordRune := func(r rune) bool {
+		return !unicode.IsSpace(r) && !strings.ContainsRune(`"=!<>,()`, r)
+	}
+
+	for i := 0; i < len(runes); {
+		r, col := runes[i], i+1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == ',':
+			tokens = append(tokens, ruleExprToken{kind: ruleExprComma, text: ",", col: col})
+			i++
+		case r == '"':
+			var sb strings.Builder
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, ruleExprErrorf(col, "unterminated quoted string")
+				}
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+				} else if runes[i] == '"' {
+					break
+				}
+				sb.WriteRune(runes[i])
+			}
+			tokens = append(tokens, ruleExprToken{kind: ruleExprQuoted, text: sb.String(), col: col})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' {
+				op += "="
+			}
+			tokens = append(tokens, ruleExprToken{kind: ruleExprOperator, text: op, col: col})
+			i += len(op)
+		case r == '(' || r == ')':
+			return nil, ruleExprErrorf(col, "parentheses are not supported: AND takes precedence over OR")
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, ruleExprToken{kind: ruleExprWord, text: text, col: col, upper: strings.ToUpper(text)})
+		}
+	}
+	tokens = append(tokens, ruleExprToken{kind: ruleExprEOF, col: len(runes) + 1})
+	return
+}
+
+type ruleExprCondition struct {
+	field, fieldType, operator string
+	values                     []string
+
+	col int
+}
+
+func (c ruleExprCondition) isEntityRule() bool {
+	return c.operator == "matches" || c.operator == "not"
+}
+
+type ruleExprParser struct {
+	tokens []ruleExprToken
+	pos    int
+}
+
+func (p *ruleExprParser) peek() ruleExprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *ruleExprParser) next() ruleExprToken {
+	t := p.tokens[p.pos]
+	if t.kind != ruleExprEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *ruleExprParser) isKeyword(t ruleExprToken, keywords ...string) bool {
+	return t.kind == ruleExprWord && slices.Contains(keywords, t.upper)
+}
+
+// parse returns the conditions of the expression, grouped by the OR operator.
+func (p *ruleExprParser) parse() (groups [][]ruleExprCondition, err error) {
+	var first *ruleExprCondition
+	group := []ruleExprCondition{}
+	for {
+		c, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = &c
+		} else if first.isEntityRule() != c.isEntityRule() {
+			return nil, ruleExprErrorf(c.col, "entity rule conditions (matches, not) cannot be combined with notable event field conditions (=, !=, >=, >, <)")
+		}
+		group = append(group, c)
+
+		switch t := p.next(); {
+		case t.kind == ruleExprEOF:
+			return append(groups, group), nil
+		case p.isKeyword(t, "AND"):
+		case p.isKeyword(t, "OR"):
+			groups, group = append(groups, group), []ruleExprCondition{}
+		default:
+			return nil, ruleExprErrorf(t.col, "expected AND, OR or end of expression, got %s", t)
+		}
+	}
+}
+
+func (p *ruleExprParser) condition() (c ruleExprCondition, err error) {
+	field := p.next()
+	if field.kind != ruleExprWord && field.kind != ruleExprQuoted || p.isKeyword(field, "AND", "OR") {
+		return c, ruleExprErrorf(field.col, "expected a field, got %s", field)
+	}
+	c.col, c.field = field.col, field.text
+
+	if fieldType, name, ok := strings.Cut(field.text, ":"); ok && field.kind == ruleExprWord {
+		if !slices.Contains(ruleExprFieldTypes, fieldType) {
+			return c, ruleExprErrorf(field.col, "invalid field type %q: must be one of %s", fieldType, strings.Join(ruleExprFieldTypes, ", "))
+		}
+		if name == "" {
+			return c, ruleExprErrorf(field.col+len([]rune(fieldType))+1, "expected a field name after %q", fieldType+":")
+		}
+		c.fieldType, c.field = fieldType, name
+	}
+
+	op := p.next()
+	switch {
+	case p.isKeyword(op, "MATCHES", "NOT"):
+		c.operator = strings.ToLower(op.text)
+		if c.fieldType == "" {
+			c.fieldType = "alias"
+		}
+	case op.kind == ruleExprOperator && slices.Contains(ruleExprFieldOperators, op.text):
+		c.operator = op.text
+		if c.fieldType != "" {
+			return c, ruleExprErrorf(field.col, "field types only apply to entity rule conditions (matches, not)")
+		}
+	default:
+		return c, ruleExprErrorf(op.col, "expected an operator (matches, not, =, !=, >=, >, <) after field %q, got %s", c.field, op)
+	}
+
+	for {
+		value := p.next()
+		if value.kind != ruleExprWord && value.kind != ruleExprQuoted || p.isKeyword(value, "AND", "OR") {
+			return c, ruleExprErrorf(value.col, "expected a value after %q, got %s", op.text, value)
+		}
+		c.values = append(c.values, value.text)
+
+		if p.peek().kind != ruleExprComma {
+			break
+		}
+		comma := p.next()
+		if !c.isEntityRule() {
+			return c, ruleExprErrorf(comma.col, "notable event field conditions take a single value, use OR to match multiple values")
+		}
+	}
+	return
+}
+
+// parseRuleExpr parses a rule expression into entity rule groups or NEAP criteria clauses.
+func parseRuleExpr(expr string) (entityRules []EntityRuleState, clauses []neapCriteriaClauseModel, err error) {
+	entityRules, clauses = []EntityRuleState{}, []neapCriteriaClauseModel{}
+
+	tokens, err := lexRuleExpr(expr)
+	if err != nil {
+		return
+	}
+	if tokens[0].kind == ruleExprEOF {
+		err = ruleExprErrorf(1, "empty expression")
+		return
+	}
+
+	p := &ruleExprParser{tokens: tokens}
+	groups, err := p.parse()
+	if err != nil {
+		return
+	}
+
+	for _, group := range groups {
+		if group[0].isEntityRule() {
+			rules := make([]RuleState, len(group))
+			for i, c := range group {
+				rules[i] = RuleState{
+					Field:     types.StringValue(c.field),
+					FieldType: types.StringValue(c.fieldType),
+					RuleType:  types.StringValue(c.operator),
+					Value:     types.StringValue(strings.Join(c.values, ",")),
+				}
+			}
+			entityRules = append(entityRules, EntityRuleState{Rule: rules})
+		} else {
+			fields := make([]neapCriteriaClauseNotableEventFieldModel, len(group))
+			for i, c := range group {
+				fields[i] = neapCriteriaClauseNotableEventFieldModel{
+					Field:    types.StringValue(c.field),
+					Operator: types.StringValue(c.operator),
+					Value:    types.StringValue(c.values[0]),
+				}
+			}
+			clauses = append(clauses, neapCriteriaClauseModel{Condition: types.StringValue("AND"), NotableEventField: fields})
+		}
+	}
+	return
+}
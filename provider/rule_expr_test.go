@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseRuleExprEntityRules(t *testing.T) {
+	rule := func(field, fieldType, ruleType, value string) RuleState {
+		return RuleState{types.StringValue(field), types.StringValue(fieldType), types.StringValue(ruleType), types.StringValue(value)}
+	}
+
+	entityRules, clauses, err := parseRuleExpr(`host matches web-*, api-* and info:env NOT prod OR title:name matches "db 01"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []EntityRuleState{
+		{Rule: []RuleState{rule("host", "alias", "matches", "web-*,api-*"), rule("env", "info", "not", "prod")}},
+		{Rule: []RuleState{rule("name", "title", "matches", "db 01")}},
+	}
+	if !reflect.DeepEqual(entityRules, expected) {
+		t.Errorf("expected entity rules %v, got %v", expected, entityRules)
+	}
+	if len(clauses) != 0 {
+		t.Errorf("expected no clauses, got %v", clauses)
+	}
+}
+
+func TestParseRuleExprClauses(t *testing.T) {
+	field := func(field, operator, value string) neapCriteriaClauseNotableEventFieldModel {
+		return neapCriteriaClauseNotableEventFieldModel{types.StringValue(field), types.StringValue(operator), types.StringValue(value)}
+	}
+
+	entityRules, clauses, err := parseRuleExpr(`severity>=4 AND title = "*CPU \"high\"*" OR "service name"!=sample`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []neapCriteriaClauseModel{
+		{Condition: types.StringValue("AND"), NotableEventField: []neapCriteriaClauseNotableEventFieldModel{
+			field("severity", ">=", "4"), field("title", "=", `*CPU "high"*`),
+		}},
+		{Condition: types.StringValue("AND"), NotableEventField: []neapCriteriaClauseNotableEventFieldModel{
+			field("service name", "!=", "sample"),
+		}},
+	}
+	if !reflect.DeepEqual(clauses, expected) {
+		t.Errorf("expected clauses %v, got %v", expected, clauses)
+	}
+	if len(entityRules) != 0 {
+		t.Errorf("expected no entity rules, got %v", entityRules)
+	}
+}
+
+func TestParseRuleExprErrors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		column int
+	}{
+		{"", 1},
+		{"   ", 1},
+		{"host", 5},
+		{"host is web", 6},
+		{"host matches", 13},
+		{"host matches web AND", 21},
+		{"host matches web env not prod", 18},
+		{"host matches AND", 14},
+		{"host matches web OR severity = 4", 21},
+		{"severity = 4,5", 13},
+		{"severity <= 4", 10},
+		{"info:severity = 4", 1},
+		{"foo:host matches web", 1},
+		{"info: matches web", 6},
+		{`title = "unterminated`, 9},
+		{"(host matches web)", 1},
+	}
+	for _, test := range tests {
+		_, _, err := parseRuleExpr(test.expr)
+		var exprErr *ruleExprError
+		if !errors.As(err, &exprErr) {
+			t.Errorf("%q: expected a rule expression error, got %v", test.expr, err)
+			continue
+		}
+		if exprErr.Column != test.column {
+			t.Errorf("%q: expected error at column %d, got %s", test.expr, test.column, exprErr)
+		}
+	}
+}
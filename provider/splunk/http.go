@@ -3,7 +3,6 @@ package splunk
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,11 +17,11 @@ func (conn SplunkConnection) client() *http.Client {
 	if conn.HttpClient != nil {
 		return conn.HttpClient
 	}
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if conn.Proxy != nil {
+		tr.Proxy = conn.Proxy
 	}
-	client := &http.Client{Transport: tr}
-	return client
+	return &http.Client{Transport: tr}
 }
 
 func (conn SplunkConnection) httpGet(url string, data *url.Values) (string, error) {
@@ -60,6 +59,9 @@ func (conn SplunkConnection) httpCallWithContext(ctx context.Context, url string
 		return nil, err
 	}
 	conn.addAuthHeader(request)
+	for k, v := range conn.ExtraHeaders {
+		request.Header.Set(k, v)
+	}
 	response, err := conn.client().Do(request)
 
 	if err != nil {
@@ -72,7 +74,7 @@ func (conn SplunkConnection) addAuthHeader(request *http.Request) {
 	if conn.BearerToken != "" {
 		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", conn.BearerToken))
 	} else if conn.sessionKey.Value != "" {
-		request.Header.Add("Authorization", fmt.Sprintf("Splunk %s", conn.sessionKey))
+		request.Header.Add("Authorization", fmt.Sprintf("Splunk %s", conn.sessionKey.Value))
 	} else {
 		request.SetBasicAuth(conn.Username, conn.Password)
 	}
@@ -0,0 +1,128 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Job is the status of a search job.
+type Job struct {
+	SID           string
+	DispatchState string
+	IsDone        bool
+	IsFailed      bool
+	Messages      []string
+}
+
+type jobMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type jobResponse struct {
+	Entry []struct {
+		Content struct {
+			DispatchState string       `json:"dispatchState"`
+			IsDone        bool         `json:"isDone"`
+			IsFailed      bool         `json:"isFailed"`
+			Messages      []jobMessage `json:"messages"`
+		} `json:"content"`
+	} `json:"entry"`
+}
+
+// DispatchSavedSearch runs a saved search of the connection's user and app namespace, with its own time range,
+// and returns the search ID of the job. The args are passed to the dispatch endpoint, e.g. args.<name> or dispatch.<setting>.
+func (conn SplunkConnection) DispatchSavedSearch(ctx context.Context, name string, args map[string]string) (sid string, err error) {
+	data := make(url.Values)
+	data.Add("output_mode", "json")
+	for k, v := range args {
+		data.Add(k, v)
+	}
+
+	u := fmt.Sprintf("%s/servicesNS/%s/%s/saved/searches/%s/dispatch", conn.BaseURL, conn.SplunkUser, conn.SplunkApp, url.PathEscape(name))
+	body, err := conn.jobRequest(ctx, u, http.MethodPost, &data)
+	if err != nil {
+		return "", fmt.Errorf("failed to dispatch saved search %s: %w", name, err)
+	}
+
+	var dispatched struct {
+		SID string `json:"sid"`
+	}
+	if err = json.Unmarshal(body, &dispatched); err != nil {
+		return "", fmt.Errorf("failed to dispatch saved search %s: %w", name, err)
+	}
+	if dispatched.SID == "" {
+		return "", fmt.Errorf("failed to dispatch saved search %s: no search ID received", name)
+	}
+	return dispatched.SID, nil
+}
+
+// JobStatus returns the status of a search job of the connection's user and app namespace.
+func (conn SplunkConnection) JobStatus(ctx context.Context, sid string) (job Job, err error) {
+	data := make(url.Values)
+	data.Add("output_mode", "json")
+
+	u := fmt.Sprintf("%s/servicesNS/%s/%s/search/jobs/%s?%s", conn.BaseURL, conn.SplunkUser, conn.SplunkApp, url.PathEscape(sid), data.Encode())
+	body, err := conn.jobRequest(ctx, u, http.MethodGet, nil)
+	if err != nil {
+		return job, fmt.Errorf("failed to check search job %s: %w", sid, err)
+	}
+
+	var resp jobResponse
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return job, fmt.Errorf("failed to check search job %s: %w", sid, err)
+	}
+	if len(resp.Entry) == 0 {
+		return job, fmt.Errorf("failed to check search job %s: job not found", sid)
+	}
+
+	content := resp.Entry[0].Content
+	job = Job{
+		SID:           sid,
+		DispatchState: content.DispatchState,
+		IsDone:        content.IsDone || content.DispatchState == "DONE" || content.DispatchState == "FAILED",
+		IsFailed:      content.IsFailed || content.DispatchState == "FAILED",
+	}
+	for _, m := range content.Messages {
+		job.Messages = append(job.Messages, fmt.Sprintf("%s: %s", m.Type, m.Text))
+	}
+	return
+}
+
+// Err returns the error of a failed job, or nil.
+func (job Job) Err() error {
+	if !job.IsFailed {
+		return nil
+	}
+	if len(job.Messages) == 0 {
+		return fmt.Errorf("search job %s failed", job.SID)
+	}
+	return fmt.Errorf("search job %s failed: %s", job.SID, strings.Join(job.Messages, "; "))
+}
+
+func (conn SplunkConnection) jobRequest(ctx context.Context, u string, method string, data *url.Values) ([]byte, error) {
+	response, err := conn.httpCallWithContext(ctx, u, method, data)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case response.StatusCode == http.StatusUnauthorized:
+		return nil, ErrUnauthorized
+	case response.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, response.Status)
+	case response.StatusCode >= 300:
+		return nil, fmt.Errorf("%s: %s", response.Status, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SearchSyntaxError is returned by ParseSearch when Splunk rejects a search as syntactically invalid.
+type SearchSyntaxError struct {
+	Messages []string
+}
+
+func (e *SearchSyntaxError) Error() string {
+	return strings.Join(e.Messages, "; ")
+}
+
+type parserMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type parserResponse struct {
+	Messages []parserMessage `json:"messages"`
+}
+
+// ParseSearch checks the syntax of an SPL search using the search/parser endpoint, without running it.
+// A *SearchSyntaxError is returned if the search is invalid; any other error means the check could not be performed.
+func (conn SplunkConnection) ParseSearch(ctx context.Context, searchString string) error {
+	search := strings.TrimSpace(searchString)
+	if !strings.HasPrefix(search, "|") && !strings.HasPrefix(search, "search ") {
+		search = "search " + search
+	}
+
+	data := make(url.Values)
+	data.Add("q", search)
+	data.Add("parse_only", "true")
+	data.Add("output_mode", "json")
+
+	response, err := conn.httpCallWithContext(ctx, fmt.Sprintf("%s/services/search/parser", conn.BaseURL), http.MethodPost, &data)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		var parsed parserResponse
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return fmt.Errorf("search parser error: %s: %s", response.Status, body)
+		}
+		syntaxErr := &SearchSyntaxError{}
+		for _, m := range parsed.Messages {
+			syntaxErr.Messages = append(syntaxErr.Messages, m.Text)
+		}
+		if len(syntaxErr.Messages) == 0 {
+			syntaxErr.Messages = []string{response.Status}
+		}
+		return syntaxErr
+	case http.StatusUnauthorized:
+		return fmt.Errorf("search parser error: %w", ErrUnauthorized)
+	default:
+		return fmt.Errorf("search parser error: %s", response.Status)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/lestrrat-go/backoff/v2"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
 )
 
 const (
@@ -45,6 +47,15 @@ func parseLine(line string) (r Row, err error) {
 	return r, dec.Decode(&r)
 }
 
+// ErrUnauthorized is returned when a request is rejected because of invalid credentials or an expired session key
+var ErrUnauthorized = errors.New(http.StatusText(http.StatusUnauthorized))
+
+// ErrUnavailable is returned when the search head could not be reached or kept failing with 5xx responses
+var ErrUnavailable = errors.New("search head unavailable")
+
+// ErrThrottled is returned along with ErrUnavailable when the search head kept responding with 429 or 503
+var ErrThrottled = errors.New("throttled")
+
 func (conn SplunkConnection) Search(ctx context.Context, boPolicy backoff.Policy, searchString string, params ...map[string]string) (rows []Row, events []string, err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -61,13 +72,23 @@ func (conn SplunkConnection) Search(ctx context.Context, boPolicy backoff.Policy
 	}
 
 	var responseBody []byte
-	bo := boPolicy.Start(ctx)
+	var lastErr error
+
+	// retries stop after the max elapsed time, without interrupting the search in flight
+	retryCtx := ctx
+	if conn.RetryMaxElapsedTime > 0 {
+		var cancelRetries context.CancelFunc
+		retryCtx, cancelRetries = context.WithTimeout(ctx, conn.RetryMaxElapsedTime)
+		defer cancelRetries()
+	}
+	bo := boPolicy.Start(retryCtx)
 	attempt := 1
 
 	for backoff.Continue(bo) {
 		url := fmt.Sprintf("%s/servicesNS/%s/%s/search/jobs/export", conn.BaseURL, conn.SplunkUser, conn.SplunkApp)
 		start := time.Now()
 
+		var retryAfter time.Duration
 		statusCode, err := func() (statusCode int, err error) {
 			response, err := conn.httpCallWithContext(ctx, url, http.MethodPost, &data)
 			if err != nil {
@@ -78,20 +99,47 @@ func (conn SplunkConnection) Search(ctx context.Context, boPolicy backoff.Policy
 			statusCode = response.StatusCode
 			if statusCode != 200 {
 				err = fmt.Errorf("splunk search error: %v", response.Status)
+				retryAfter = util.RetryAfter(response.Header.Get("Retry-After"), time.Now())
 			}
 			return
 		}()
 		status := http.StatusText(statusCode)
 		tflog.Trace(ctx, fmt.Sprintf("POST %v (%v): %v %v [%s]", url, attempt, statusCode, status, time.Since(start).String()), map[string]any{"splunk_search": searchString, "params": params})
 		if err != nil {
-			if statusCode == 400 || statusCode == 401 || statusCode == 403 || statusCode == 404 {
+			if statusCode == 401 {
+				return nil, nil, fmt.Errorf("splunk search error: %w", ErrUnauthorized)
+			}
+			lastErr = err
+			if statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable {
+				lastErr = fmt.Errorf("%w: %w", ErrThrottled, err)
+			}
+			// transport errors have no status code and are always retried
+			if statusCode != 0 && conn.RetryableStatus != nil && !conn.RetryableStatus(statusCode) {
+				if statusCode >= 500 {
+					return nil, nil, fmt.Errorf("%w: %w", ErrUnavailable, lastErr)
+				}
 				return nil, nil, fmt.Errorf("splunk search error: %v", status)
 			}
+			if deadline, ok := retryCtx.Deadline(); ok && conn.RetryMaxElapsedTime > 0 && time.Now().Add(retryAfter).After(deadline) {
+				break
+			}
+			if retryAfter > 0 {
+				// the search head asked to wait before retrying
+				tflog.Trace(ctx, fmt.Sprintf("POST %v (%v): retrying after %s", url, attempt, retryAfter))
+				util.Sleep(retryCtx, retryAfter)
+			}
+			if retryCtx.Err() != nil {
+				break
+			}
 			attempt++
 			continue
 		}
+		lastErr = nil
 		break
 	}
+	if lastErr != nil && ctx.Err() == nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrUnavailable, lastErr)
+	}
 
 	scanner := bufio.NewScanner(bytes.NewReader(responseBody))
 	scanner.Buffer(make([]byte, bufferDefaultSize), bufferMaxSize)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type SplunkConnection struct {
@@ -13,6 +14,18 @@ type SplunkConnection struct {
 
 	sessionKey SessionKey
 	HttpClient *http.Client
+
+	// Proxy selects the proxy of the default HTTP client, used when HttpClient is nil.
+	// By default, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used.
+	Proxy func(*http.Request) (*url.URL, error)
+	// ExtraHeaders are added to every request.
+	ExtraHeaders map[string]string
+
+	// RetryableStatus returns whether a search that failed with the HTTP status code should be retried.
+	// If nil, all failed searches are retried. Searches failing with a transport error are always retried.
+	RetryableStatus func(statusCode int) bool
+	// RetryMaxElapsedTime bounds the time spent on a search and its retries, if set.
+	RetryMaxElapsedTime time.Duration
 }
 
 // SessionKey represents the JSON object returned from the Splunk authentication REST call
@@ -20,8 +33,8 @@ type SessionKey struct {
 	Value string `json:"sessionKey"`
 }
 
-// Login connects to the Splunk server and retrieves a session key
-func (conn SplunkConnection) Login() (key SessionKey, err error) {
+// Login connects to the Splunk server and retrieves a session key, used to authenticate the subsequent requests
+func (conn *SplunkConnection) Login() (key SessionKey, err error) {
 
 	data := make(url.Values)
 	data.Add("username", conn.Username)
@@ -46,6 +59,11 @@ func (conn SplunkConnection) Login() (key SessionKey, err error) {
 	return
 }
 
+// SetSessionKey authenticates the subsequent requests with a session key retrieved previously
+func (conn *SplunkConnection) SetSessionKey(key SessionKey) {
+	conn.sessionKey = key
+}
+
 func (conn SplunkConnection) HasSessionKey() bool {
 	return len(conn.sessionKey.Value) > 0
 }
@@ -2,4 +2,26 @@
 resource "itsi_entity_type" "test" {
   title       = "TestAcc_DataSourceEntityTypeLifecycle_sample_entity_type"
   description = "TestAcc EXAMPLE"
+
+  vital_metric {
+    is_key = true
+    matching_entity_fields = {
+      host = "host"
+    }
+    metric_name = "Average CPU Usage"
+    search      = "| mstats avg(cpu.usage) as val WHERE 1=1 by host span=5m"
+    unit        = "%"
+
+    alert_rule {
+      critical_threshold = 90
+      warning_threshold  = 75
+      cron_schedule      = "*/5 * * * *"
+      is_enabled         = true
+      entity_filter {
+        field      = "host"
+        field_type = "alias"
+        value      = "*"
+      }
+    }
+  }
 }
@@ -0,0 +1,9 @@
+resource "itsi_entity_type" "pod" {
+  title       = "TestAcc_DataSourceEntityTypesLifecycle_pod"
+  description = "TestAcc EXAMPLE"
+}
+
+resource "itsi_entity_type" "node" {
+  title       = "TestAcc_DataSourceEntityTypesLifecycle_node"
+  description = "TestAcc EXAMPLE"
+}
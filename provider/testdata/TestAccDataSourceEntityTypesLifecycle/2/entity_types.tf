@@ -0,0 +1,17 @@
+resource "itsi_entity_type" "pod" {
+  title       = "TestAcc_DataSourceEntityTypesLifecycle_pod"
+  description = "TestAcc EXAMPLE"
+}
+
+resource "itsi_entity_type" "node" {
+  title       = "TestAcc_DataSourceEntityTypesLifecycle_node"
+  description = "TestAcc EXAMPLE"
+}
+
+data "itsi_entity_types" "all" {
+  title = "testacc_datasourceentitytypeslifecycle_*"
+}
+
+data "itsi_entity_types" "pod" {
+  title = "TestAcc_DataSourceEntityTypesLifecycle_pod"
+}
@@ -0,0 +1,11 @@
+data "itsi_kpi_base_search_preview" "test" {
+  base_search = <<-EOT
+	  | makeresults count=10 | eval value=1
+	EOT
+
+  metric {
+    title            = "events"
+    threshold_field  = "value"
+    aggregate_statop = "sum"
+  }
+}
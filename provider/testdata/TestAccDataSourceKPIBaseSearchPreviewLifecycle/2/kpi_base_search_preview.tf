@@ -0,0 +1,33 @@
+resource "itsi_kpi_base_search" "test" {
+  title                      = "TestAcc_DataSourceKPIBaseSearchPreviewLifecycle"
+  description                = "TestAcc EXAMPLE"
+  alert_lag                  = "30"
+  alert_period               = "1"
+  base_search                = <<-EOT
+	  | makeresults count=10 | streamstats count as n | eval host=if(n%2==0, "even", "odd"), value=n
+	EOT
+  entity_breakdown_id_fields = "host"
+  entity_id_fields           = "host"
+  is_entity_breakdown        = true
+  is_service_entity_filter   = false
+  search_alert_earliest      = "5"
+  sec_grp                    = "default_itsi_security_group"
+  source_itsi_da             = "itsi"
+  metrics {
+    aggregate_statop         = "max"
+    entity_statop            = "sum"
+    fill_gaps                = "null_value"
+    gap_custom_alert_value   = 0
+    gap_severity             = "unknown"
+    gap_severity_color       = "#CCCCCC"
+    gap_severity_color_light = "#EEEEEE"
+    gap_severity_value       = "-1"
+    threshold_field          = "value"
+    title                    = "value"
+    unit                     = ""
+  }
+}
+
+data "itsi_kpi_base_search_preview" "test" {
+  base_search_id = itsi_kpi_base_search.test.id
+}
@@ -0,0 +1,6 @@
+data "itsi_kpi_threshold_preview" "test" {
+  threshold_template_id = itsi_kpi_threshold_template.test.id
+  service_id            = itsi_service.test.id
+  kpi_id                = one([for kpi in itsi_service.test.kpi : kpi.id if kpi.title == "KPI 1"])
+  earliest_time         = "-1h"
+}
@@ -0,0 +1,13 @@
+resource "itsi_service" "test" {
+  title   = "TestAcc_DataSourceKPIThresholdPreviewLifecycle_service"
+  enabled = true
+
+  kpi {
+    base_search_id        = itsi_kpi_base_search.test.id
+    base_search_metric    = "metric 1"
+    threshold_template_id = itsi_kpi_threshold_template.test.id
+    title                 = "KPI 1"
+    search_type           = "shared_base"
+    urgency               = 5
+  }
+}
@@ -0,0 +1,29 @@
+data "itsi_kpi_threshold_preview" "test" {
+  service_id    = itsi_service.test.id
+  kpi_id        = one([for kpi in itsi_service.test.kpi : kpi.id if kpi.title == "KPI 1"])
+  earliest_time = "-1h"
+  time_zone     = "America/New_York"
+
+  policy {
+    policy_name = "default_policy"
+    policy_type = "static"
+    threshold_levels {
+      severity_label  = "critical"
+      threshold_value = 90
+    }
+  }
+
+  policy {
+    policy_name         = "weekend"
+    policy_type         = "stdev"
+    base_severity_label = "normal"
+    time_blocks {
+      cron     = "0 0 * * 6"
+      interval = 2880
+    }
+    threshold_levels {
+      severity_label = "high"
+      dynamic_param  = 2
+    }
+  }
+}
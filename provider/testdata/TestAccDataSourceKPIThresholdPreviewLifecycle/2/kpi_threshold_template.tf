@@ -0,0 +1,41 @@
+resource "itsi_kpi_threshold_template" "test" {
+  title                                 = "TestAcc_DataSourceKPIThresholdPreviewLifecycle_threshold_template"
+  description                           = "static"
+  adaptive_thresholds_is_enabled        = false
+  adaptive_thresholding_training_window = "-7d"
+  time_variate_thresholds               = false
+  sec_grp                               = "default_itsi_security_group"
+  time_variate_thresholds_specification {
+    policies {
+      policy_name = "default_policy"
+      title       = "Default"
+      policy_type = "static"
+      aggregate_thresholds {
+        base_severity_label = "normal"
+        metric_field        = ""
+        is_max_static       = false
+        gauge_min           = 0
+        gauge_max           = 100
+        render_boundary_min = 0
+        render_boundary_max = 100
+        is_min_static       = false
+        threshold_levels {
+          severity_label  = "critical"
+          dynamic_param   = 0
+          threshold_value = 90
+        }
+      }
+
+      entity_thresholds {
+        base_severity_label = "normal"
+        gauge_max           = 100
+        gauge_min           = 0
+        is_max_static       = false
+        is_min_static       = false
+        metric_field        = ""
+        render_boundary_max = 100
+        render_boundary_min = 0
+      }
+    }
+  }
+}
@@ -0,0 +1,21 @@
+resource "itsi_entities" "test" {
+  scope = "TestAcc_ResourceEntitiesLifecycle"
+
+  entity {
+    title       = "TestAcc_ResourceEntitiesLifecycle_HostA"
+    description = "a.example.com"
+    aliases = {
+      "host" = ["a.example.com"]
+    }
+    info = {
+      "env" = ["test"]
+    }
+  }
+
+  entity {
+    title = "TestAcc_ResourceEntitiesLifecycle_HostB"
+    aliases = {
+      "host" = ["b.example.com"]
+    }
+  }
+}
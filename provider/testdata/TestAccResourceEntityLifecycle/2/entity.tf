@@ -2,11 +2,13 @@ resource "itsi_entity" "test" {
   title       = "TestAcc_ResourceEntityLifecycle_ExampleHost"
   description = "TEST DESCRIPTION update"
   aliases = {
-    "host"        = "entityTest.example.com"
-    "entityTitle" = "entityTest.example.com"
+    "host"        = ["entityTest.example.com"]
+    "entityTitle" = ["entityTest.example.com"]
+    "ip"          = ["10.0.0.2", "10.0.0.1"]
   }
   info = {
-    "env" : "test"
-    "entityType" : "123"
+    "env" : ["test"]
+    "entityType" : ["123"]
+    "location" : ["Alviso, CA"]
   }
 }
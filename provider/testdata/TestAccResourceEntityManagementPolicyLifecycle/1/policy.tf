@@ -0,0 +1,15 @@
+resource "itsi_entity_management_policy" "test" {
+  title             = "TestAcc_ResourceEntityManagementPolicyLifecycle"
+  description       = "Retire inactive test hosts"
+  inactivity_period = "7d"
+  action            = "retire"
+
+  entity_rules {
+    rule {
+      field      = "host"
+      field_type = "alias"
+      rule_type  = "matches"
+      value      = "TestAcc_*"
+    }
+  }
+}
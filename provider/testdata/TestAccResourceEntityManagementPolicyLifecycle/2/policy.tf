@@ -0,0 +1,22 @@
+resource "itsi_entity_management_policy" "test" {
+  title             = "TestAcc_ResourceEntityManagementPolicyLifecycle"
+  description       = "Delete inactive test hosts"
+  inactivity_period = "30d"
+  action            = "delete"
+  cron_schedule     = "0 0 * * *"
+
+  entity_rules {
+    rule {
+      field      = "host"
+      field_type = "alias"
+      rule_type  = "matches"
+      value      = "TestAcc_*"
+    }
+    rule {
+      field      = "env"
+      field_type = "info"
+      rule_type  = "not"
+      value      = "prod"
+    }
+  }
+}
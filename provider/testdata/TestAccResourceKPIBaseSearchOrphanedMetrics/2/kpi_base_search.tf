@@ -0,0 +1,21 @@
+resource "itsi_kpi_base_search" "test" {
+  title                      = "TestAcc_ResourceKPIBaseSearchOrphanedMetrics_base_search"
+  description                = "abc"
+  alert_lag                  = "5"
+  alert_period               = "5"
+  base_search                = "| makeresults count=10"
+  entity_breakdown_id_fields = "index"
+  entity_id_fields           = "pqdn"
+  is_entity_breakdown        = true
+  is_service_entity_filter   = true
+  search_alert_earliest      = "5"
+
+  metrics {
+    aggregate_statop = "sum"
+    entity_statop    = "sum"
+    fill_gaps        = "null_value"
+    threshold_field  = "count"
+    title            = "metric 1"
+    unit             = ""
+  }
+}
@@ -3,18 +3,26 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/tivo/terraform-provider-splunk-itsi/models"
+	"github.com/tivo/terraform-provider-splunk-itsi/provider/splunk"
+	"github.com/tivo/terraform-provider-splunk-itsi/util"
 )
 
 type jsonStringType int
 
 const (
-	jsonStringValidationError = "JSON string validation failed"
+	jsonStringValidationError         = "JSON string validation failed"
+	cronStringValidationError         = "Cron expression validation failed"
+	relativeTimeStringValidationError = "Splunk relative time validation failed"
+	searchSyntaxValidationError       = "SPL search validation failed"
 
 	jsonStringTypeNull    jsonStringType = 1 << 0
 	jsonStringTypeObject  jsonStringType = 1 << 1
@@ -56,6 +64,14 @@ func stringvalidatorIsJSON(jsonType jsonStringType) validator.String {
 	return jsonStringValidator{jsonType}
 }
 
+func stringvalidatorIsCron() validator.String {
+	return cronStringValidator{}
+}
+
+func stringvalidatorIsSplunkRelativeTime() validator.String {
+	return splunkRelativeTimeStringValidator{}
+}
+
 // (1.1) [ jsonStringValidator ] _________________________________________________
 
 type jsonStringValidator struct{ t jsonStringType }
@@ -146,3 +162,91 @@ func (v jsonStringValidator) ValidateString(ctx context.Context, req validator.S
 		resp.Diagnostics.Append(diag.WithPath(req.Path, d))
 	}
 }
+
+// (1.2) [ cronStringValidator ] _________________________________________________
+
+type cronStringValidator struct{}
+
+var _ validator.String = cronStringValidator{}
+
+func (v cronStringValidator) Description(_ context.Context) string {
+	return "string must be a valid 5-field cron expression"
+}
+
+func (v cronStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cronStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if err := util.ValidateCron(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, cronStringValidationError, err.Error())
+	}
+}
+
+// (1.3) [ splunkRelativeTimeStringValidator ] ___________________________________
+
+type splunkRelativeTimeStringValidator struct{}
+
+var _ validator.String = splunkRelativeTimeStringValidator{}
+
+func (v splunkRelativeTimeStringValidator) Description(_ context.Context) string {
+	return "string must be a number of seconds or a valid Splunk relative time modifier"
+}
+
+func (v splunkRelativeTimeStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v splunkRelativeTimeStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if err := util.ValidateSplunkRelativeTime(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, relativeTimeStringValidationError, err.Error())
+	}
+}
+
+// (2) [ Server-side Validators ] ______________________________________________
+
+// validateSearchSyntax checks an SPL search against the Splunk search/parser endpoint.
+// Syntax errors are reported as errors; failure to reach Splunk is only reported as a warning,
+// so that plans can still be produced when Splunk is unavailable.
+func validateSearchSyntax(ctx context.Context, client models.ClientConfig, search string, p path.Path) (diags diag.Diagnostics) {
+	client, err := client.WithCredentials(ctx)
+	if err != nil {
+		diags.AddAttributeWarning(p, "Unable to validate SPL search",
+			fmt.Sprintf("The Splunk credentials could not be retrieved: %s", err))
+		return
+	}
+	client = client.AnyEndpoint(ctx)
+
+	err = withRefreshedCredentials(ctx, client, func(client models.ClientConfig) error {
+		conn, sessionKey, err := splunkConnection(ctx, client, "", "")
+		if err != nil {
+			return err
+		}
+
+		err = conn.ParseSearch(ctx, search)
+		if errors.Is(err, splunk.ErrUnauthorized) && sessionKey != "" {
+			// the session key has expired or has been revoked: log in again and repeat the check
+			if err = renewSplunkSession(ctx, client, &conn, sessionKey); err == nil {
+				err = conn.ParseSearch(ctx, search)
+			}
+		}
+		return err
+	})
+
+	var syntaxErr *splunk.SearchSyntaxError
+	if errors.As(err, &syntaxErr) {
+		diags.AddAttributeError(p, searchSyntaxValidationError, syntaxErr.Error())
+	} else if err != nil {
+		diags.AddAttributeWarning(p, "Unable to validate SPL search",
+			fmt.Sprintf("The search syntax could not be checked against Splunk: %s", err))
+	}
+	return
+}
@@ -16,9 +16,8 @@ import (
 	"time"
 
 	"github.com/akamensky/argparse"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/lestrrat-go/backoff/v2"
+	"github.com/tivo/terraform-provider-splunk-itsi/credentials"
 	"github.com/tivo/terraform-provider-splunk-itsi/models"
 	"github.com/tivo/terraform-provider-splunk-itsi/provider"
 
@@ -96,7 +95,7 @@ func main() {
 	bearerToken := ""
 
 	if ssmTokenCommand.Happened() {
-		bearerToken, err = getTokenFromSSM(*tokenPath, *profile, *region)
+		bearerToken, err = credentials.TokenFromSSM(context.Background(), *tokenPath, *profile, *region)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -462,38 +461,3 @@ func auditFields(fieldsMap map[string]bool, objectType string) error {
 	}
 	return os.WriteFile(filename, by, 0644)
 }
-
-func getTokenFromSSM(tokenPath, profile, region string) (accessToken string, err error) {
-	cfg, err := config.LoadDefaultConfig(
-		context.Background(),
-		config.WithSharedConfigProfile(profile))
-	if err != nil {
-		return "", err
-	}
-
-	cfg.Region = region
-	client := ssm.NewFromConfig(cfg)
-	decryption := true
-
-	param, err := client.GetParameters(
-		context.Background(),
-		&ssm.GetParametersInput{
-			Names:          []string{tokenPath},
-			WithDecryption: &decryption,
-		})
-	if err != nil {
-		return "", err
-	}
-
-	secretsInfo := map[string]string{}
-	for _, item := range param.Parameters {
-		secretsInfo[*item.Name] = *item.Value
-	}
-
-	token, ok := secretsInfo[tokenPath]
-	if !ok {
-		return "", fmt.Errorf("client token not found from SSM")
-	}
-
-	return token, nil
-}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// AdaptiveLimiter is a concurrency limiter whose limit follows an AIMD (additive increase, multiplicative decrease) scheme:
+// the limit is halved when a request is throttled, and grows by one after a full limit's worth of successful requests,
+// staying between the min and max limits.
+type AdaptiveLimiter struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	min, max  int
+	limit     int
+	inFlight  int
+	successes int
+
+	// throttled requests that were already in flight when the limit was decreased do not decrease it again
+	lastDecrease     time.Time
+	decreaseCooldown time.Duration
+}
+
+const adaptiveLimiterDecreaseCooldown = time.Second
+
+// NewAdaptiveLimiter returns an adaptive limiter starting at the max limit.
+func NewAdaptiveLimiter(minLimit, maxLimit int) *AdaptiveLimiter {
+	minLimit = max(minLimit, 1)
+	maxLimit = max(maxLimit, minLimit)
+	l := &AdaptiveLimiter{
+		min:              minLimit,
+		max:              maxLimit,
+		limit:            maxLimit,
+		decreaseCooldown: adaptiveLimiterDecreaseCooldown,
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *AdaptiveLimiter) Acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inFlight >= l.limit {
+		l.cond.Wait()
+	}
+	l.inFlight++
+}
+
+func (l *AdaptiveLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	l.cond.Signal()
+}
+
+func (l *AdaptiveLimiter) Feedback(throttled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if throttled {
+		l.successes = 0
+		if time.Since(l.lastDecrease) < l.decreaseCooldown {
+			return
+		}
+		l.lastDecrease = time.Now()
+		l.limit = max(l.min, l.limit/2)
+		return
+	}
+
+	if l.limit >= l.max {
+		return
+	}
+	if l.successes++; l.successes >= l.limit {
+		l.successes = 0
+		l.limit++
+		l.cond.Broadcast()
+	}
+}
+
+// Limit returns the current concurrency limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
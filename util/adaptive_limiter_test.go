@@ -0,0 +1,200 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	limiter := NewAdaptiveLimiter(1, 2)
+
+	ok := make(chan bool)
+
+	go func() {
+		limiter.Acquire()
+		limiter.Acquire()
+		limiter.Release()
+		limiter.Release()
+
+		ok <- true
+	}()
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	select {
+	case <-ok:
+	case <-ticker.C:
+		t.Errorf("Expected Acquire and Release to succeed")
+	}
+}
+
+func TestAdaptiveLimiter_AcquireWhenFull(t *testing.T) {
+	limiter := NewAdaptiveLimiter(1, 2)
+	limiter.Acquire()
+	limiter.Acquire()
+	err := make(chan error)
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	go func() {
+		limiter.Acquire()
+		err <- nil
+	}()
+
+	select {
+	case <-err:
+		t.Errorf("Expected Acquire to block when limiter is full")
+	case <-ticker.C:
+	}
+}
+
+func TestAdaptiveLimiter_Bounds(t *testing.T) {
+	for _, test := range []struct {
+		min, max             int
+		expectMin, expectMax int
+	}{
+		{0, 0, 1, 1},
+		{2, 10, 2, 10},
+		{5, 3, 5, 5},
+	} {
+		limiter := NewAdaptiveLimiter(test.min, test.max)
+		if limiter.min != test.expectMin || limiter.max != test.expectMax || limiter.Limit() != test.expectMax {
+			t.Errorf("NewAdaptiveLimiter(%d, %d): expected bounds [%d, %d] starting at %d, got [%d, %d] starting at %d",
+				test.min, test.max, test.expectMin, test.expectMax, test.expectMax, limiter.min, limiter.max, limiter.Limit())
+		}
+	}
+}
+
+func TestAdaptiveLimiter_MultiplicativeDecrease(t *testing.T) {
+	limiter := NewAdaptiveLimiter(3, 16)
+	limiter.decreaseCooldown = 0
+
+	for _, expected := range []int{8, 4, 3, 3} {
+		limiter.Feedback(true)
+		if limit := limiter.Limit(); limit != expected {
+			t.Fatalf("Expected limit %d after throttling, got %d", expected, limit)
+		}
+	}
+}
+
+func TestAdaptiveLimiter_DecreaseCooldown(t *testing.T) {
+	limiter := NewAdaptiveLimiter(1, 16)
+
+	limiter.Feedback(true)
+	limiter.Feedback(true)
+	if limit := limiter.Limit(); limit != 8 {
+		t.Errorf("Expected throttling within the cooldown to decrease the limit once, got limit %d", limit)
+	}
+}
+
+func TestAdaptiveLimiter_AdditiveIncrease(t *testing.T) {
+	limiter := NewAdaptiveLimiter(1, 6)
+	limiter.Feedback(true)
+	if limit := limiter.Limit(); limit != 3 {
+		t.Fatalf("Expected limit 3 after throttling, got %d", limit)
+	}
+
+	// a full limit's worth of successes grows the limit by one
+	for range 2 {
+		limiter.Feedback(false)
+	}
+	if limit := limiter.Limit(); limit != 3 {
+		t.Errorf("Expected limit 3 before sustained success, got %d", limit)
+	}
+	limiter.Feedback(false)
+	if limit := limiter.Limit(); limit != 4 {
+		t.Errorf("Expected limit 4 after sustained success, got %d", limit)
+	}
+
+	for range 100 {
+		limiter.Feedback(false)
+	}
+	if limit := limiter.Limit(); limit != 6 {
+		t.Errorf("Expected limit to stay at max 6, got %d", limit)
+	}
+}
+
+func TestAdaptiveLimiter_ThrottlingResetsSuccesses(t *testing.T) {
+	limiter := NewAdaptiveLimiter(1, 8)
+	limiter.decreaseCooldown = time.Hour
+	limiter.lastDecrease = time.Now()
+	limiter.limit = 4
+
+	for range 3 {
+		limiter.Feedback(false)
+	}
+	// within the cooldown: the limit is kept, but the successes are reset
+	limiter.Feedback(true)
+	limiter.Feedback(false)
+	if limit := limiter.Limit(); limit != 4 {
+		t.Errorf("Expected limit 4, got %d", limit)
+	}
+}
+
+func TestAdaptiveLimiter_IncreaseWakesWaiters(t *testing.T) {
+	limiter := NewAdaptiveLimiter(1, 2)
+	limiter.Feedback(true)
+	limiter.Acquire()
+
+	ok := make(chan bool)
+	go func() {
+		limiter.Acquire()
+		ok <- true
+	}()
+
+	select {
+	case <-ok:
+		t.Fatalf("Expected Acquire to block at limit 1")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	limiter.Feedback(false)
+
+	select {
+	case <-ok:
+	case <-time.After(1 * time.Second):
+		t.Errorf("Expected Acquire to succeed once the limit grows")
+	}
+}
+
+func TestAdaptiveLimiter_DecreaseKeepsInFlight(t *testing.T) {
+	limiter := NewAdaptiveLimiter(1, 4)
+	for range 4 {
+		limiter.Acquire()
+	}
+	limiter.Feedback(true)
+
+	ok := make(chan bool)
+	go func() {
+		limiter.Acquire()
+		ok <- true
+	}()
+
+	// 4 requests in flight at limit 2: 3 releases are needed before another Acquire succeeds
+	for range 2 {
+		limiter.Release()
+	}
+	select {
+	case <-ok:
+		t.Fatalf("Expected Acquire to block until in-flight requests drop below the decreased limit")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	limiter.Release()
+	select {
+	case <-ok:
+	case <-time.After(1 * time.Second):
+		t.Errorf("Expected Acquire to succeed once in-flight requests drop below the decreased limit")
+	}
+}
+
+func TestLimiter_Feedback(t *testing.T) {
+	limiter := NewLimiter(2)
+	limiter.Feedback(true)
+	limiter.Acquire()
+	limiter.Acquire()
+	limiter.Release()
+	limiter.Release()
+}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	// both 0 and 7 stand for Sunday
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+const (
+	cronMinute = iota
+	cronHour
+	cronDayOfMonth
+	cronMonth
+	cronDayOfWeek
+)
+
+// CronSchedule is a parsed cron expression.
+type CronSchedule struct {
+	// bitmasks of the values matched by each field
+	fields [5]uint64
+	// whether the day of month / day of week fields are unrestricted
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// ValidateCron checks that expr is a standard 5-field cron expression,
+// as accepted by Splunk scheduled searches: minute, hour, day of month, month and day of week.
+// Each field may be a *, a value, a range (a-b), a list (a,b) and may have a step (*/n, a-b/n).
+func ValidateCron(expr string) error {
+	_, err := ParseCron(expr)
+	return err
+}
+
+// ParseCron parses a cron expression in the format accepted by ValidateCron.
+func ParseCron(expr string) (s CronSchedule, err error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return s, fmt.Errorf("expected %d space-separated fields, got %d", len(cronFields), len(fields))
+	}
+
+	for i, f := range cronFields {
+		if s.fields[i], err = f.parse(fields[i]); err != nil {
+			return s, fmt.Errorf("invalid %s field %q: %w", f.name, fields[i], err)
+		}
+	}
+	if s.fields[cronDayOfWeek]&(1<<7) != 0 {
+		s.fields[cronDayOfWeek] |= 1
+	}
+	s.anyDayOfMonth = strings.HasPrefix(fields[cronDayOfMonth], "*")
+	s.anyDayOfWeek = strings.HasPrefix(fields[cronDayOfWeek], "*")
+	return s, nil
+}
+
+// Matches reports whether the schedule fires at the minute of t.
+// As in standard cron, if both the day of month and the day of week are restricted, either of them may match.
+func (s CronSchedule) Matches(t time.Time) bool {
+	has := func(field, value int) bool {
+		return s.fields[field]&(1<<value) != 0
+	}
+
+	if !has(cronMinute, t.Minute()) || !has(cronHour, t.Hour()) || !has(cronMonth, int(t.Month())) {
+		return false
+	}
+
+	dayOfMonth, dayOfWeek := has(cronDayOfMonth, t.Day()), has(cronDayOfWeek, int(t.Weekday()))
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func (f cronField) parse(s string) (bits uint64, err error) {
+	for _, item := range strings.Split(s, ",") {
+		itemBits, err := f.parseItem(item)
+		if err != nil {
+			return 0, err
+		}
+		bits |= itemBits
+	}
+	return bits, nil
+}
+
+func (f cronField) parseItem(item string) (bits uint64, err error) {
+	if item == "" {
+		return 0, fmt.Errorf("empty list item")
+	}
+
+	step := 1
+	rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+	if hasStep {
+		step, err = strconv.Atoi(stepExpr)
+		if err != nil || step < 1 {
+			return 0, fmt.Errorf("step must be a positive integer, got %q", stepExpr)
+		}
+	}
+
+	start, end := f.min, f.max
+	if rangeExpr != "*" {
+		lo, hi, isRange := strings.Cut(rangeExpr, "-")
+		if start, err = f.value(lo); err != nil {
+			return 0, err
+		}
+		switch {
+		case isRange:
+			if end, err = f.value(hi); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("range start %d is greater than range end %d", start, end)
+			}
+		case !hasStep:
+			// a single value; with a step, a single value stands for the range value-max
+			end = start
+		}
+	}
+
+	for v := start; v <= end; v += step {
+		bits |= 1 << v
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid value", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d is out of range [%d, %d]", v, f.min, f.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateCron(t *testing.T) {
+	testCases := []struct {
+		input string
+		valid bool
+	}{
+		{input: "* * * * *", valid: true},
+		{input: "*/5 * * * *", valid: true},
+		{input: "0 * * * *", valid: true},
+		{input: "15,45 8-18 * * mon-fri", valid: true},
+		{input: "0 0 1 jan,jul 0", valid: true},
+		{input: "0 0 * * 7", valid: true},
+		{input: "0-30/10 */2 1-15 * *", valid: true},
+		{input: "", valid: false},
+		{input: "* * * *", valid: false},
+		{input: "* * * * * *", valid: false},
+		{input: "60 * * * *", valid: false},
+		{input: "* 24 * * *", valid: false},
+		{input: "* * 0 * *", valid: false},
+		{input: "* * * 13 *", valid: false},
+		{input: "* * * * 8", valid: false},
+		{input: "*/0 * * * *", valid: false},
+		{input: "30-10 * * * *", valid: false},
+		{input: "1,,2 * * * *", valid: false},
+		{input: "every 5 minutes * *", valid: false},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateCron(tc.input)
+		if (err == nil) != tc.valid {
+			t.Errorf("ValidateCron(%q) = %v, expected valid: %v", tc.input, err, tc.valid)
+		}
+	}
+}
+
+func TestCronScheduleMatches(t *testing.T) {
+	// 2024-01-01 is a Monday
+	monday := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+	sunday := time.Date(2024, time.January, 7, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		expr    string
+		t       time.Time
+		matches bool
+	}{
+		{expr: "* * * * *", t: monday, matches: true},
+		{expr: "30 9 * * *", t: monday, matches: true},
+		{expr: "0 9 * * *", t: monday, matches: false},
+		{expr: "*/15 8-18 * * mon-fri", t: monday, matches: true},
+		{expr: "*/15 8-18 * * mon-fri", t: sunday, matches: false},
+		{expr: "30 9 * * 7", t: sunday, matches: true},
+		{expr: "30 9 * * 0", t: sunday, matches: true},
+		{expr: "10/20 * * * *", t: monday, matches: true},
+		{expr: "30 9 1 jan *", t: monday, matches: true},
+		{expr: "30 9 1 feb *", t: monday, matches: false},
+		// day of month and day of week are both restricted: either may match
+		{expr: "30 9 15 * 1", t: monday, matches: true},
+		{expr: "30 9 7 * 1", t: sunday, matches: true},
+		{expr: "30 9 15 * 2", t: monday, matches: false},
+	}
+
+	for _, tc := range testCases {
+		s, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) returned an error: %s", tc.expr, err)
+		}
+		if matches := s.Matches(tc.t); matches != tc.matches {
+			t.Errorf("ParseCron(%q).Matches(%s) = %v, expected %v", tc.expr, tc.t, matches, tc.matches)
+		}
+	}
+}
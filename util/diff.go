@@ -0,0 +1,80 @@
+package util
+
+import "strings"
+
+// LineDiff compares two texts line by line and returns the lines that differ,
+// prefixed with "- " (only in a) or "+ " (only in b),
+// along with up to context unchanged lines (prefixed with "  ") around every change.
+// Skipped unchanged lines are marked with "  ...". Returns an empty string if the texts are equal.
+func LineDiff(a, b string, context int) string {
+	x, y := strings.Split(strings.TrimSuffix(a, "\n"), "\n"), strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	lines := []line{}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			lines = append(lines, line{' ', x[i]})
+			i, j = i+1, j+1
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', x[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', y[j]})
+			j++
+		}
+	}
+
+	// mark the unchanged lines that are within context of a change
+	show := make([]bool, len(lines))
+	changed := false
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		changed = true
+		for c := max(0, k-context); c <= min(len(lines)-1, k+context); c++ {
+			show[c] = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	skipped := false
+	for k, l := range lines {
+		if !show[k] {
+			if !skipped {
+				sb.WriteString("  ...\n")
+			}
+			skipped = true
+			continue
+		}
+		skipped = false
+		sb.WriteByte(l.op)
+		sb.WriteByte(' ')
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package util
+
+import "testing"
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\nc\n",
+			b:    "a\nb\nc\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			a:    "a\nb\nc\n",
+			b:    "a\nB\nc\n",
+			want: "  ...\n- b\n+ B\n  ...\n",
+		},
+		{
+			name:    "changed line with context",
+			a:       "a\nb\nc\nd\ne\n",
+			b:       "a\nb\nC\nd\ne\n",
+			context: 1,
+			want:    "  ...\n  b\n- c\n+ C\n  d\n  ...\n",
+		},
+		{
+			name:    "added and removed lines",
+			a:       "a\nb\n",
+			b:       "b\nc\n",
+			context: 1,
+			want:    "- a\n  b\n+ c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LineDiff(tt.a, tt.b, tt.context); got != tt.want {
+				t.Errorf("LineDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
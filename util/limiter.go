@@ -1,5 +1,18 @@
 package util
 
+// ConcurrencyLimiter bounds the number of concurrent requests.
+// Feedback reports whether a request was throttled, for limiters adapting their limit to it.
+type ConcurrencyLimiter interface {
+	Acquire()
+	Release()
+	Feedback(throttled bool)
+}
+
+var (
+	_ ConcurrencyLimiter = (*Limiter)(nil)
+	_ ConcurrencyLimiter = (*AdaptiveLimiter)(nil)
+)
+
 type Limiter chan struct{}
 
 func NewLimiter(n int) *Limiter {
@@ -23,3 +36,6 @@ func (l *Limiter) Release() {
 	}
 	(*l) <- struct{}{}
 }
+
+// Feedback is a no-op: the limit of a Limiter is fixed.
+func (l *Limiter) Feedback(bool) {}
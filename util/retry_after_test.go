@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"120", 2 * time.Minute},
+		{" 5 ", 5 * time.Second},
+		{"-5", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-30 * time.Second).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	} {
+		if actual := RetryAfter(test.value, now); actual != test.expected {
+			t.Errorf("RetryAfter(%q): expected %s, got %s", test.value, test.expected, actual)
+		}
+	}
+}
+
+func TestSleepCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	Sleep(ctx, time.Minute)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected Sleep to return once the context is done, took %s", elapsed)
+	}
+}
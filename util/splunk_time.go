@@ -0,0 +1,37 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	splunkTimeUnits = `(?:s|secs?|seconds?|m|mins?|minutes?|h|hrs?|hours?|d|days?|w|weeks?|mon|months?|q|qtrs?|quarters?|y|yrs?|years?)`
+
+	// An offset is an optional sign, an optional integer and a time unit, e.g. -1d, +30m or h.
+	splunkTimeOffset = `[+-]?\d*` + splunkTimeUnits
+	// A snap-to unit, optionally a specific day of week, e.g. @d or @w1.
+	splunkTimeSnap = `@(?:w[0-7]|` + splunkTimeUnits + `)`
+
+	splunkRelativeTimeRE = regexp.MustCompile(
+		`^(?:` + splunkTimeOffset + `)*(?:` + splunkTimeSnap + `(?:` + splunkTimeOffset + `)*)?$`,
+	)
+	splunkSecondsRE = regexp.MustCompile(`^\d+$`)
+)
+
+// ValidateSplunkRelativeTime checks that s is either a number of seconds,
+// "now" or a Splunk relative time modifier such as -1d@d, +8h or 30m.
+// See https://docs.splunk.com/Documentation/Splunk/latest/Search/Specifytimemodifiersinyoursearch
+func ValidateSplunkRelativeTime(s string) error {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return fmt.Errorf("relative time must not be empty")
+	case s == "now", splunkSecondsRE.MatchString(s):
+		return nil
+	case splunkRelativeTimeRE.MatchString(s):
+		return nil
+	}
+	return fmt.Errorf("%q is not a valid Splunk relative time modifier", s)
+}
@@ -0,0 +1,36 @@
+package util
+
+import "testing"
+
+func TestValidateSplunkRelativeTime(t *testing.T) {
+	testCases := []struct {
+		input string
+		valid bool
+	}{
+		{input: "0", valid: true},
+		{input: "3600", valid: true},
+		{input: "now", valid: true},
+		{input: "30m", valid: true},
+		{input: "1h", valid: true},
+		{input: "-1d@d", valid: true},
+		{input: "-1d@d+8h", valid: true},
+		{input: "@w1", valid: true},
+		{input: "-2mon@mon", valid: true},
+		{input: "+3days", valid: true},
+		{input: "-1h-30m", valid: true},
+		{input: "", valid: false},
+		{input: "1x", valid: false},
+		{input: "-", valid: false},
+		{input: "@", valid: false},
+		{input: "@w8", valid: false},
+		{input: "1 hour", valid: false},
+		{input: "yesterday", valid: false},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateSplunkRelativeTime(tc.input)
+		if (err == nil) != tc.valid {
+			t.Errorf("ValidateSplunkRelativeTime(%q) = %v, expected valid: %v", tc.input, err, tc.valid)
+		}
+	}
+}